@@ -0,0 +1,143 @@
+// Command import reads vendors from a CSV or NDJSON file, or from standard
+// input, and writes them to the database like the import endpoint does. It
+// prints the row by row report as JSON and exits with status 1 when a row
+// failed.
+//
+//	import [-format csv|ndjson] [-key _id|name] [-delimiter |] [-dry-run] file
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+	mongoRepository "vendors/internal/repository/mongodb"
+	partitionedRepository "vendors/internal/repository/partitioned"
+	"vendors/internal/service"
+	"vendors/pkg/database"
+	"vendors/pkg/lib/utils"
+)
+
+// fileFormats maps file extensions to the import format they hold.
+var fileFormats = map[string]domain.ImportFormat{
+	".csv":    domain.ImportCSV,
+	".ndjson": domain.ImportNDJSON,
+	".jsonl":  domain.ImportNDJSON,
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run imports the file named on the command line and returns the exit
+// status, so that its deferred cleanup runs before the process exits.
+func run() int {
+	format := flag.String("format", "", "csv or ndjson; by default taken from the file extension")
+	key := flag.String("key", "", "update the vendors whose _id or name matches a row instead of creating them")
+	delimiter := flag.String("delimiter", domain.DefaultListDelimiter, "joins the values of CSV list columns")
+	dryRun := flag.Bool("dry-run", false, "check and match the rows without writing anything")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: import [flags] file (- for standard input)")
+		flag.PrintDefaults()
+		return 2
+	}
+
+	path := flag.Arg(0)
+	opts := domain.ImportOptions{
+		Format:        domain.ImportFormat(*format),
+		Key:           *key,
+		ListDelimiter: *delimiter,
+		DryRun:        *dryRun,
+	}
+	if opts.Format == "" {
+		opts.Format = fileFormats[strings.ToLower(filepath.Ext(path))]
+	}
+
+	var source io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			slog.Error("failed to open import file", utils.Err(err))
+			return 1
+		}
+		defer file.Close()
+		source = file
+	}
+
+	cfg := config.LoadConfig()
+
+	if err := database.InitDB(cfg); err != nil {
+		slog.Error("failed to initialize database", utils.Err(err))
+		return 1
+	}
+	defer database.Close()
+
+	report, err := newVendorService(cfg).ImportVendors(context.Background(), source, opts)
+	if err != nil {
+		slog.Error("failed to import vendors", utils.Err(err))
+		return 1
+	}
+
+	for i := range report.Rows {
+		if row := &report.Rows[i]; row.Err != nil {
+			row.Error = row.Err.Error()
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	fmt.Fprintf(os.Stderr, "created %d, updated %d, failed %d\n", report.Created, report.Updated, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newVendorService sets up the vendor service the way the server does for
+// MongoDB storage. Webhook deliveries are queued for the server to send, and
+// with the outbox enabled the server's relay publishes the events.
+func newVendorService(cfg *config.Config) *service.VendorService {
+	db := database.GetDB()
+	collections := cfg.MongoDB.VendorCollections()
+	outbox := db.Collection(cfg.MongoDB.OutboxCollection)
+
+	partitions := make(map[string]repository.VendorRepository, len(domain.VendorTypes))
+	for _, vendorType := range domain.VendorTypes {
+		partition := mongoRepository.NewMongoDBVendorRepository(db.Collection(collections[vendorType]), cfg.Timeouts)
+		if cfg.Outbox.Enabled {
+			partition.WithOutbox(outbox)
+		}
+		partitions[vendorType] = partition
+	}
+
+	webhookService := service.NewWebhookService(mongoRepository.NewMongoDBWebhookRepository(
+		db.Collection(cfg.MongoDB.WebhookCollection),
+		db.Collection(cfg.MongoDB.DeliveryCollection),
+		cfg.Timeouts,
+	), cfg.Webhooks)
+
+	vendorService := service.NewVendorService(
+		partitionedRepository.NewPartitionedVendorRepository(partitions),
+		mongoRepository.NewMongoDBHistoryRepository(db.Collection(cfg.MongoDB.HistoryCollection), cfg.Timeouts),
+		webhookService,
+		cfg.Trash,
+		cfg.Events,
+	)
+	if cfg.Outbox.Enabled {
+		vendorService.PublishThrough(service.NewOutboxRelay(mongoRepository.NewMongoDBOutboxRepository(outbox, cfg.Timeouts), cfg.Outbox))
+	}
+
+	return vendorService
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
@@ -8,7 +9,12 @@ import (
 	"syscall"
 	"vendors/internal/config"
 	routes "vendors/internal/delivery/routers"
-	repository "vendors/internal/repository/mongodb"
+	"vendors/internal/domain"
+	"vendors/internal/migrations"
+	repository "vendors/internal/repository/interfaces"
+	memoryRepository "vendors/internal/repository/memory"
+	mongoRepository "vendors/internal/repository/mongodb"
+	partitionedRepository "vendors/internal/repository/partitioned"
 	"vendors/internal/service"
 	"vendors/pkg/database"
 	"vendors/pkg/lib/utils"
@@ -22,32 +28,118 @@ func main() {
 
 	logger, err := logger.SetupLogger(cfg.Env)
 	if err != nil {
-		slog.Error("failed to set up logger: %v", err)
+		slog.Error("failed to set up logger", utils.Err(err))
 		os.Exit(1)
 	}
 
-	slog.Info("Starting the server...", slog.String("env", cfg.Env))
+	slog.Info("Starting the server...", slog.String("env", cfg.Env), slog.String("storage", cfg.Storage))
 	slog.Debug("Debug messages are enabled")
 
-	if err := database.InitDB(cfg); err != nil {
-		logger.ErrorLogger.Error("failed to initialize database: %v", err)
+	var vendorRepository repository.VendorRepository
+	var historyRepository repository.HistoryRepository
+	var webhookRepository repository.WebhookRepository
+	var outboxRepository repository.OutboxRepository
+	var changeStream *mongoRepository.MongoDBVendorEventSource
+
+	vendorTypes := domain.VendorTypes
+	partitions := make(map[string]repository.VendorRepository, len(vendorTypes))
+
+	switch cfg.Storage {
+	case config.StorageMemory:
+		for _, vendorType := range vendorTypes {
+			partitions[vendorType] = memoryRepository.NewMemoryVendorRepository()
+		}
+		historyRepository = memoryRepository.NewMemoryHistoryRepository()
+		webhookRepository = memoryRepository.NewMemoryWebhookRepository()
+
+		if cfg.Outbox.Enabled {
+			logger.ErrorLogger.Error("the outbox needs mongodb storage")
+			os.Exit(1)
+		}
+	case config.StorageMongoDB:
+		if err := database.InitDB(cfg); err != nil {
+			logger.ErrorLogger.Error("failed to initialize database", utils.Err(err))
+			os.Exit(1)
+		}
+		defer database.Close()
+
+		if cfg.Migrations.RunOnStartup {
+			migrator := migrations.NewMigrator(migrations.NewTarget(database.GetDB(), cfg.MongoDB), migrations.All(), cfg.Migrations)
+			if _, err := migrator.Run(context.Background()); err != nil {
+				logger.ErrorLogger.Error("failed to run database migrations", utils.Err(err))
+				os.Exit(1)
+			}
+		}
+
+		collections := cfg.MongoDB.VendorCollections()
+		collectionNames := make([]string, 0, len(vendorTypes))
+		outbox := database.GetDB().Collection(cfg.MongoDB.OutboxCollection)
+		for _, vendorType := range vendorTypes {
+			partition := mongoRepository.NewMongoDBVendorRepository(database.GetDB().Collection(collections[vendorType]), cfg.Timeouts)
+			if cfg.Outbox.Enabled {
+				partition.WithOutbox(outbox)
+			}
+			partitions[vendorType] = partition
+			collectionNames = append(collectionNames, collections[vendorType])
+		}
+		changeStream = mongoRepository.NewMongoDBVendorEventSource(database.GetDB(), collectionNames)
+		historyRepository = mongoRepository.NewMongoDBHistoryRepository(database.GetDB().Collection(cfg.MongoDB.HistoryCollection), cfg.Timeouts)
+		webhookRepository = mongoRepository.NewMongoDBWebhookRepository(
+			database.GetDB().Collection(cfg.MongoDB.WebhookCollection),
+			database.GetDB().Collection(cfg.MongoDB.DeliveryCollection),
+			cfg.Timeouts,
+		)
+		if cfg.Outbox.Enabled {
+			outboxRepository = mongoRepository.NewMongoDBOutboxRepository(outbox, cfg.Timeouts)
+		}
+	default:
+		logger.ErrorLogger.Error("unknown storage backend", slog.String("storage", cfg.Storage))
 		os.Exit(1)
 	}
-	defer database.Close()
+
+	vendorRepository = partitionedRepository.NewPartitionedVendorRepository(partitions)
 
 	mainRouter := chi.NewRouter()
 
 	vendorRouter := chi.NewRouter()
+	webhookRouter := chi.NewRouter()
 
 	mainRouter.Route("/api/vendor", func(r chi.Router) {
 		r.Mount("/", vendorRouter)
 	})
+	mainRouter.Route("/api/webhook", func(r chi.Router) {
+		r.Mount("/", webhookRouter)
+	})
+
+	webhookService := service.NewWebhookService(webhookRepository, cfg.Webhooks)
+	routes.SetupWebhookRouter(webhookRouter, webhookService)
 
-	vendorCollection := database.GetDB().Collection("vendors")
-	vendorRepository := repository.NewMongoDBVendorRepository(vendorCollection)
-	vendorService := service.NewVendorService(vendorRepository)
+	vendorService := service.NewVendorService(vendorRepository, historyRepository, webhookService, cfg.Trash, cfg.Events)
 	routes.SetupVendorRouter(vendorRouter, vendorService)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	switch {
+	case cfg.Events.Source == config.EventSourceService:
+	case changeStream != nil && changeStream.Available(ctx):
+		vendorService.EventSource = changeStream
+		slog.Info("Streaming vendor events from change streams")
+	case cfg.Events.Source == config.EventSourceChangeStream:
+		logger.ErrorLogger.Error("change streams are not available on this database")
+		os.Exit(1)
+	}
+
+	if outboxRepository != nil {
+		relay := service.NewOutboxRelay(outboxRepository, cfg.Outbox, vendorService.EventPublishers()...)
+		vendorService.PublishThrough(relay)
+		go relay.Run(ctx)
+		slog.Info("Publishing vendor events through the outbox")
+	}
+
+	go vendorService.RunTrashPurger(ctx)
+	go webhookService.RunWebhookDispatcher(ctx)
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -55,6 +147,7 @@ func main() {
 		<-stop
 		logger.InfoLogger.Info("Shutting down the server gracefully...")
 
+		cancel()
 		database.Close()
 		os.Exit(0)
 	}()
@@ -0,0 +1,61 @@
+// Command migrate applies pending database migrations, or lists them with
+// -status, without starting the server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"vendors/internal/config"
+	"vendors/internal/migrations"
+	"vendors/pkg/database"
+	"vendors/pkg/lib/utils"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run applies or lists the migrations and returns the exit status, so that
+// its deferred cleanup runs before the process exits.
+func run() int {
+	status := flag.Bool("status", false, "list migrations and whether they have been applied")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	if err := database.InitDB(cfg); err != nil {
+		slog.Error("failed to initialize database", utils.Err(err))
+		return 1
+	}
+	defer database.Close()
+
+	migrator := migrations.NewMigrator(migrations.NewTarget(database.GetDB(), cfg.MongoDB), migrations.All(), cfg.Migrations)
+	ctx := context.Background()
+
+	if *status {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("failed to read migration status", utils.Err(err))
+			return 1
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-28s %s\n", s.Version, applied, s.Description)
+		}
+		return 0
+	}
+
+	ran, err := migrator.Run(ctx)
+	if err != nil {
+		slog.Error("failed to run database migrations", utils.Err(err))
+		return 1
+	}
+	fmt.Printf("applied %d migration(s)\n", len(ran))
+	return 0
+}
@@ -2,27 +2,123 @@ package config
 
 import (
 	"log"
+	"time"
+	"vendors/internal/domain"
 	"vendors/pkg/lib/utils"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	Env     string  `yaml:"env"`
-	Server  Server  `yaml:"server"`
-	MongoDB MongoDB `yaml:"mongodb"`
+	Env        string     `yaml:"env"`
+	Storage    string     `yaml:"storage" env-default:"mongodb"`
+	Server     Server     `yaml:"server"`
+	MongoDB    MongoDB    `yaml:"mongodb"`
+	Timeouts   Timeouts   `yaml:"timeouts"`
+	Trash      Trash      `yaml:"trash"`
+	Migrations Migrations `yaml:"migrations"`
+	Events     Events     `yaml:"events"`
+	Webhooks   Webhooks   `yaml:"webhooks"`
+	Outbox     Outbox     `yaml:"outbox"`
 }
 
 type Server struct {
 	Address string `yaml:"address"`
 }
 
+// MongoDB names the database and the collection each vendor type is stored in.
 type MongoDB struct {
-	URI               string `yaml:"uri"`
-	Database          string `yaml:"database"`
-	CinemaCollection  string `yaml:"cinemaCollection"`
-	TheatreCollection string `yaml:"theatreCollection"`
-	FoodCollection    string `yaml:"foodCollection"`
+	URI                string `yaml:"uri"`
+	Database           string `yaml:"database"`
+	CinemaCollection   string `yaml:"cinemaCollection" env-default:"cinemas"`
+	TheatreCollection  string `yaml:"theatreCollection" env-default:"theatres"`
+	FoodCollection     string `yaml:"foodCollection" env-default:"food"`
+	HistoryCollection  string `yaml:"historyCollection" env-default:"vendor_history"`
+	WebhookCollection  string `yaml:"webhookCollection" env-default:"webhooks"`
+	DeliveryCollection string `yaml:"deliveryCollection" env-default:"webhook_deliveries"`
+	OutboxCollection   string `yaml:"outboxCollection" env-default:"vendor_outbox"`
+}
+
+// VendorCollections maps each vendor type to the collection it is stored in.
+func (m MongoDB) VendorCollections() map[string]string {
+	return map[string]string{
+		domain.VendorTypeCinema:  m.CinemaCollection,
+		domain.VendorTypeTheatre: m.TheatreCollection,
+		domain.VendorTypeFood:    m.FoodCollection,
+	}
+}
+
+const (
+	StorageMongoDB = "mongodb"
+	StorageMemory  = "memory"
+)
+
+// Timeouts bound how long a single repository operation may run before its
+// context is cancelled. A zero value disables the deadline for that kind of
+// operation and leaves only the request context in charge.
+type Timeouts struct {
+	Read  time.Duration `yaml:"read" env-default:"5s"`
+	Write time.Duration `yaml:"write" env-default:"5s"`
+	Count time.Duration `yaml:"count" env-default:"10s"`
+}
+
+// Trash controls how long soft deleted vendors are kept before they are
+// purged for good, and how often the purge runs.
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purgeInterval" env-default:"1h"`
+}
+
+// Migrations controls when database migrations run, how long the lock that
+// keeps them from running twice lasts unless its holder renews it, and how
+// long an instance waits for it.
+type Migrations struct {
+	RunOnStartup bool          `yaml:"runOnStartup" env-default:"true"`
+	LockTTL      time.Duration `yaml:"lockTTL" env-default:"10m"`
+	LockWait     time.Duration `yaml:"lockWait" env-default:"2m"`
+}
+
+const (
+	EventSourceAuto         = "auto"
+	EventSourceChangeStream = "changestream"
+	EventSourceService      = "service"
+)
+
+// Events chooses where the vendor event stream comes from. Change streams see
+// the changes of every instance but need a replica set; service events only
+// see the changes made by this instance. Auto uses change streams when the
+// database supports them. Buffer is how many recent service events are kept
+// for clients resuming a stream.
+type Events struct {
+	Source string `yaml:"source" env-default:"auto"`
+	Buffer int    `yaml:"buffer" env-default:"1000"`
+}
+
+// Webhooks controls how vendor events are delivered to webhooks. A failed
+// delivery is retried after InitialBackoff, doubling up to MaxBackoff, and
+// moved to the dead letters once MaxAttempts attempts have failed. Due
+// deliveries are looked for every PollInterval, and right away after a
+// change.
+type Webhooks struct {
+	Timeout        time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts    int           `yaml:"maxAttempts" env-default:"8"`
+	InitialBackoff time.Duration `yaml:"initialBackoff" env-default:"30s"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" env-default:"1h"`
+	PollInterval   time.Duration `yaml:"pollInterval" env-default:"5s"`
+}
+
+// Outbox controls the transactional outbox. When it is enabled, MongoDB
+// vendor writes add their events to the outbox in the same transaction, which
+// needs a replica set, and a relay publishes them from there every
+// PollInterval, and right away after a change. A claimed record is hidden
+// from other relays for Lease. An event that could not be published is
+// retried after InitialBackoff, doubling up to MaxBackoff.
+type Outbox struct {
+	Enabled        bool          `yaml:"enabled" env-default:"false"`
+	PollInterval   time.Duration `yaml:"pollInterval" env-default:"1s"`
+	Lease          time.Duration `yaml:"lease" env-default:"1m"`
+	InitialBackoff time.Duration `yaml:"initialBackoff" env-default:"1s"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" env-default:"5m"`
 }
 
 func LoadConfig() *Config {
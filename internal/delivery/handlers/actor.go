@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+	"vendors/internal/domain"
+)
+
+// ActorHeader names the request header that identifies who is making a
+// change. It is recorded as the actor of vendor history entries.
+const ActorHeader = "X-Actor"
+
+func ActorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get(ActorHeader); actor != "" {
+			r = r.WithContext(domain.ContextWithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
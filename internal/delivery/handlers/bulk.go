@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxBulkBodyBytes bounds the body of a bulk request. It leaves room for
+// MaxBulkItems vendors of a few kilobytes each.
+const maxBulkBodyBytes = 4 << 20
+
+// bulkRequest is the body of every bulk endpoint. Ordered defaults to true:
+// processing stops at the first failing vendor.
+type bulkRequest[T any] struct {
+	Ordered *bool `json:"ordered"`
+	Vendors []T   `json:"vendors"`
+}
+
+type bulkUpdateItem struct {
+	ID      primitive.ObjectID `json:"_id"`
+	Version int64              `json:"version"`
+	domain.UpdateVendorRequest
+}
+
+type bulkDeleteItem struct {
+	ID      primitive.ObjectID `json:"_id"`
+	Version int64              `json:"version"`
+}
+
+func (h *VendorHandler) BulkCreateVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := decodeBulkRequest[*domain.CreateVendorRequest](w, r)
+	if !ok {
+		return
+	}
+
+	for _, vendor := range request.Vendors {
+		if vendor == nil {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestBody)
+			return
+		}
+	}
+
+	result, err := h.VendorService.BulkCreateVendors(r.Context(), request.Vendors, *request.Ordered)
+	respondWithBulkResult(w, result, err)
+}
+
+func (h *VendorHandler) BulkUpdateVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := decodeBulkRequest[bulkUpdateItem](w, r)
+	if !ok {
+		return
+	}
+
+	updates := make([]domain.BulkUpdate, len(request.Vendors))
+	for i := range request.Vendors {
+		item := &request.Vendors[i]
+		updates[i] = domain.BulkUpdate{ID: item.ID, Vendor: &item.UpdateVendorRequest, Version: item.Version}
+	}
+
+	result, err := h.VendorService.BulkUpdateVendors(r.Context(), updates, *request.Ordered)
+	respondWithBulkResult(w, result, err)
+}
+
+func (h *VendorHandler) BulkDeleteVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := decodeBulkRequest[bulkDeleteItem](w, r)
+	if !ok {
+		return
+	}
+
+	deletes := make([]domain.BulkDelete, len(request.Vendors))
+	for i, item := range request.Vendors {
+		deletes[i] = domain.BulkDelete{ID: item.ID, Version: item.Version}
+	}
+
+	result, err := h.VendorService.BulkDeleteVendors(r.Context(), deletes, *request.Ordered)
+	respondWithBulkResult(w, result, err)
+}
+
+// decodeBulkRequest reads a bulk body within the size limits and responds
+// with an error itself when it can't.
+func decodeBulkRequest[T any](w http.ResponseWriter, r *http.Request) (*bulkRequest[T], bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkBodyBytes)
+
+	var request bulkRequest[T]
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.RespondWithErrorJSON(w, status.PayloadTooLarge, errs.RequestTooLarge)
+			return nil, false
+		}
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestBody)
+		return nil, false
+	}
+
+	switch {
+	case len(request.Vendors) == 0:
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.EmptyBulkRequest)
+		return nil, false
+	case len(request.Vendors) > domain.MaxBulkItems:
+		utils.RespondWithErrorJSON(w, status.PayloadTooLarge, errs.TooManyBulkItems)
+		return nil, false
+	}
+
+	if request.Ordered == nil {
+		ordered := true
+		request.Ordered = &ordered
+	}
+
+	return &request, true
+}
+
+func respondWithBulkResult(w http.ResponseWriter, result *domain.BulkResult, err error) {
+	if err != nil {
+		slog.Error("Error running bulk request: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	for i := range result.Results {
+		if item := &result.Results[i]; item.Err != nil {
+			item.Error = bulkItemError(item.Err)
+		}
+	}
+
+	utils.RespondWithJSON(w, status.OK, result)
+}
+
+// bulkItemError turns the error of one bulk item into the message the
+// single-vendor endpoints would have answered with.
+func bulkItemError(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrVendorNotFound):
+		return errs.VendorNotFound
+	case errors.Is(err, domain.ErrVersionConflict):
+		return errs.VersionConflict
+	case errors.Is(err, domain.ErrInvalidCoordinates):
+		return errs.InvalidCoordinates
+	case errors.Is(err, domain.ErrUnknownVendorType):
+		return errs.UnknownVendorType
+	case errors.Is(err, domain.ErrVendorTypeChanged):
+		return errs.VendorTypeChanged
+	default:
+		slog.Error("Error writing bulk item: ", utils.Err(err))
+		return errs.InternalServerError
+	}
+}
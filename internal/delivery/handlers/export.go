@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+)
+
+// exportContentTypes maps each export format to the content type it is sent as.
+var exportContentTypes = map[domain.ExportFormat]string{
+	domain.ExportJSON:   "application/json",
+	domain.ExportNDJSON: "application/x-ndjson",
+	domain.ExportCSV:    "text/csv; charset=utf-8",
+}
+
+// exportWriter sends the headers of an export with its first bytes, so that
+// an export failing before it wrote anything can still answer with an error.
+type exportWriter struct {
+	http.ResponseWriter
+	format  domain.ExportFormat
+	started bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", exportContentTypes[w.format])
+		w.Header().Set("Content-Disposition", `attachment; filename="vendors.`+string(w.format)+`"`)
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// ExportVendorsHandler streams every vendor matching the listing filters, in
+// the listing sort order, as JSON, NDJSON or CSV. In CSV, delimiter joins the
+// values of list columns like an import expects them.
+func (h *VendorHandler) ExportVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := domain.ExportOptions{
+		Format:        domain.ExportFormat(query.Get("format")),
+		ListDelimiter: query.Get("delimiter"),
+	}
+	if err := opts.Validate(); err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		return
+	}
+
+	sort, ok := parseSort(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidSort)
+		return
+	}
+
+	filter, err := h.VendorService.ParseVendorFilter(query)
+	if err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		return
+	}
+
+	out := &exportWriter{ResponseWriter: w, format: opts.Format}
+	err = h.VendorService.ExportVendors(r.Context(), out, filter, sort, opts)
+	switch {
+	case err == nil:
+	case out.started:
+		slog.Error("Error exporting vendors after the response started: ", utils.Err(err))
+	case errors.Is(err, domain.ErrUnknownVendorType):
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+	default:
+		slog.Error("Error exporting vendors: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+)
+
+// maxImportBodyBytes bounds the body of an import. It leaves room for
+// MaxImportRows vendors of a few kilobytes each.
+const maxImportBodyBytes = 32 << 20
+
+// importFormats maps the content types an import may be sent as to its format.
+var importFormats = map[string]domain.ImportFormat{
+	"text/csv":             domain.ImportCSV,
+	"application/x-ndjson": domain.ImportNDJSON,
+	"application/ndjson":   domain.ImportNDJSON,
+	"application/jsonl":    domain.ImportNDJSON,
+}
+
+// ImportVendorsHandler imports vendors from a CSV or NDJSON body. The format
+// parameter names the format, which otherwise follows the content type. Rows
+// are matched to existing vendors by the key parameter, _id or name, and
+// dry_run=true only reports what would be written. In CSV, delimiter joins the
+// values of list columns and defaults to a pipe.
+func (h *VendorHandler) ImportVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := domain.ImportOptions{
+		Format:        domain.ImportFormat(query.Get("format")),
+		Key:           query.Get("key"),
+		ListDelimiter: query.Get("delimiter"),
+	}
+	if opts.Format == "" {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		opts.Format = importFormats[contentType]
+	}
+	if value := query.Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidDryRun)
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBodyBytes)
+
+	report, err := h.VendorService.ImportVendors(r.Context(), r.Body, opts)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			utils.RespondWithErrorJSON(w, status.PayloadTooLarge, errs.RequestTooLarge)
+		case errors.Is(err, domain.ErrInvalidImport):
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		default:
+			slog.Error("Error importing vendors: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	for i := range report.Rows {
+		if row := &report.Rows[i]; row.Err != nil {
+			row.Error = importRowError(row.Err)
+		}
+	}
+
+	utils.RespondWithJSON(w, status.OK, report)
+}
+
+// importRowError turns the error of one import row into a message. Errors
+// about reading or matching the row carry the details the client needs to
+// fix it; the others read like those of a bulk item.
+func importRowError(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidImportRow),
+		errors.Is(err, domain.ErrDuplicateImportKey),
+		errors.Is(err, domain.ErrAmbiguousImportKey):
+		return err.Error()
+	default:
+		return bulkItemError(err)
+	}
+}
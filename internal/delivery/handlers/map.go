@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"vendors/internal/domain"
+)
+
+// parseMapQuery reads the map area from either the bbox or the polygon query
+// parameter, and the optional grid size. Exactly one area must be given.
+func parseMapQuery(r *http.Request) (domain.MapQuery, bool) {
+	var query domain.MapQuery
+
+	bbox := r.URL.Query().Get("bbox")
+	polygon := r.URL.Query().Get("polygon")
+
+	switch {
+	case bbox != "" && polygon == "":
+		parts := strings.Split(bbox, ",")
+		if len(parts) != 4 {
+			return query, false
+		}
+		corners := make([]float64, len(parts))
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return query, false
+			}
+			corners[i] = value
+		}
+		area, err := domain.BoundingBoxPolygon(corners[0], corners[1], corners[2], corners[3])
+		if err != nil {
+			return query, false
+		}
+		query.Area = area
+	case polygon != "" && bbox == "":
+		var area domain.GeoPolygon
+		if err := json.Unmarshal([]byte(polygon), &area); err != nil {
+			return query, false
+		}
+		// Only the strict winding CRS set for bounding boxes is understood,
+		// so a client supplied one is dropped rather than passed through.
+		area.CRS = nil
+		query.Area = &area
+	default:
+		return query, false
+	}
+
+	if gridStr := r.URL.Query().Get("grid"); gridStr != "" {
+		grid, err := strconv.Atoi(gridStr)
+		if err != nil {
+			return query, false
+		}
+		query.Grid = grid
+	}
+
+	return query, true
+}
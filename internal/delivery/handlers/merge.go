@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxMergeBodyBytes bounds the body of a merge, which lists at most
+// MaxMergeVendors IDs.
+const maxMergeBodyBytes = 16 << 10
+
+// FindDuplicateVendorsHandler lists pairs of vendors that are likely the same
+// vendor entered twice, best match first. The type parameter narrows the
+// search to one type, threshold sets the lowest score reported and limit the
+// number of pairs.
+func (h *VendorHandler) FindDuplicateVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	query := domain.DuplicateQuery{Type: r.URL.Query().Get("type")}
+
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidThreshold)
+			return
+		}
+		query.Threshold = threshold
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidLimit)
+			return
+		}
+		query.Limit = limit
+	}
+
+	report, err := h.VendorService.FindDuplicateVendors(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDuplicateQuery):
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		case errors.Is(err, domain.ErrUnknownVendorType):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+		default:
+			slog.Error("Error finding duplicate vendors: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	utils.RespondWithJSON(w, status.OK, report)
+}
+
+// MergeVendorsHandler folds the vendors listed in the body into the vendor in
+// the path. Their IDs keep resolving to it, and they are moved to the trash.
+// If-Match applies to the vendor in the path. A merge that stops part way
+// lists the vendors already in the trash, and the ETag is the version to
+// send it again with.
+func (h *VendorHandler) MergeVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	objectID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Invalid vendor ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidVendorID)
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidIfMatch)
+		return
+	}
+
+	var request domain.MergeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMergeBodyBytes)).Decode(&request); err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestBody)
+		return
+	}
+
+	vendor, err := h.VendorService.MergeVendors(r.Context(), objectID, request, expectedVersion)
+	var partial *domain.PartialMergeError
+	if errors.As(err, &partial) {
+		code := status.InternalServerError
+		if errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrVendorNotFound) {
+			code = status.Conflict
+		} else {
+			slog.Error("Error merging vendors: ", utils.Err(err))
+		}
+
+		setETag(w, partial.Version)
+		utils.RespondWithJSON(w, code, map[string]interface{}{
+			"status":  code,
+			"message": errs.MergeIncomplete,
+			"deleted": partial.Deleted,
+		})
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidMerge):
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		case errors.Is(err, domain.ErrVendorNotFound):
+			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotFound)
+		case errors.Is(err, domain.ErrVersionConflict):
+			utils.RespondWithErrorJSON(w, status.PreconditionFailed, errs.VersionConflict)
+		default:
+			slog.Error("Error merging vendors: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	setETag(w, vendor.Version)
+	utils.RespondWithJSON(w, status.OK, vendor)
+}
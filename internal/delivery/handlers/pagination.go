@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"vendors/internal/domain"
+)
+
+const defaultPageSize = 10
+
+// parseListOptions reads the page and cursor query parameters. When both are
+// given the cursor wins, since it already pins an exact position.
+func parseListOptions(r *http.Request) (domain.ListOptions, bool) {
+	opts := domain.ListOptions{
+		Page:     1,
+		PageSize: defaultPageSize,
+		Cursor:   r.URL.Query().Get("cursor"),
+	}
+
+	pageStr := r.URL.Query().Get("page")
+	if pageStr != "" && opts.Cursor == "" {
+		pageNum, err := strconv.Atoi(pageStr)
+		if err != nil || pageNum < 1 {
+			return opts, false
+		}
+		opts.Page = pageNum
+	}
+
+	return opts, true
+}
+
+// parseSort reads the sort query parameter, such as "-created_at,name".
+func parseSort(r *http.Request) (domain.Sort, bool) {
+	sort, err := domain.ParseSort(r.URL.Query().Get("sort"))
+	return sort, err == nil
+}
+
+// parseFacets reads the facets query parameter, such as "tags,type".
+func parseFacets(r *http.Request) ([]string, bool) {
+	fields, err := domain.ParseFacets(r.URL.Query().Get("facets"))
+	return fields, err == nil
+}
+
+// paginationBlock describes where a page sits in the full result set, whose
+// size is totalVendors. Page number links are only returned in page mode;
+// next_cursor is returned in both modes so that a client can switch to
+// cursors after the first page.
+func paginationBlock(opts domain.ListOptions, totalVendors int, nextCursor string) map[string]interface{} {
+	totalPages := int(math.Ceil(float64(totalVendors) / float64(opts.PageSize)))
+
+	var firstPage interface{}
+	if totalPages > 0 {
+		firstPage = 1
+	} else {
+		firstPage = nil
+	}
+
+	var lastPage interface{}
+	if totalPages >= 1 {
+		lastPage = totalPages
+	} else {
+		lastPage = firstPage
+	}
+
+	var next interface{}
+	if nextCursor != "" {
+		next = nextCursor
+	}
+
+	if opts.Cursor != "" {
+		return map[string]interface{}{
+			"cursor":      opts.Cursor,
+			"next_cursor": next,
+			"first_page":  firstPage,
+			"last_page":   lastPage,
+			"total_count": totalVendors,
+			"total_pages": totalPages,
+		}
+	}
+
+	var prevPage interface{}
+	if opts.Page > 1 {
+		prevPage = opts.Page - 1
+	} else {
+		prevPage = nil
+	}
+
+	var nextPage interface{}
+	if nextCursor != "" {
+		nextPage = opts.Page + 1
+	} else {
+		nextPage = nil
+	}
+
+	return map[string]interface{}{
+		"current_page": opts.Page,
+		"prev_page":    prevPage,
+		"next_page":    nextPage,
+		"first_page":   firstPage,
+		"last_page":    lastPage,
+		"next_cursor":  next,
+		"total_count":  totalVendors,
+		"total_pages":  totalPages,
+	}
+}
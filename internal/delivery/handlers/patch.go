@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPatchBodyBytes bounds the body of a PATCH request.
+const maxPatchBodyBytes = 1 << 20
+
+// patchFormats maps the accepted PATCH content types to their format.
+var patchFormats = map[string]domain.PatchFormat{
+	"application/merge-patch+json": domain.PatchFormatMerge,
+	"application/json-patch+json":  domain.PatchFormatJSON,
+}
+
+func (h *VendorHandler) PatchVendorHandler(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	objectID, err := primitive.ObjectIDFromHex(vendorID)
+	if err != nil {
+		slog.Error("Invalid vendor ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidVendorID)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format, ok := patchFormats[mediaType]
+	if !ok {
+		w.Header().Set("Accept-Patch", "application/merge-patch+json, application/json-patch+json")
+		utils.RespondWithErrorJSON(w, status.UnsupportedMediaType, errs.UnsupportedPatchType)
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidIfMatch)
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.RespondWithErrorJSON(w, status.PayloadTooLarge, errs.RequestTooLarge)
+			return
+		}
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestBody)
+		return
+	}
+
+	vendor, err := h.VendorService.PatchVendor(r.Context(), objectID, format, patch, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVendorNotFound):
+			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotFound)
+		case errors.Is(err, domain.ErrVersionConflict):
+			utils.RespondWithErrorJSON(w, status.PreconditionFailed, errs.VersionConflict)
+		case errors.Is(err, domain.ErrPatchTestFailed):
+			utils.RespondWithErrorJSON(w, status.Conflict, errs.PatchTestFailed)
+		case errors.Is(err, domain.ErrInvalidPatch):
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		case errors.Is(err, domain.ErrInvalidCoordinates):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCoordinates)
+		case errors.Is(err, domain.ErrVendorTypeChanged):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.VendorTypeChanged)
+		default:
+			slog.Error("Error patching vendor: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	setETag(w, vendor.Version)
+	utils.RespondWithJSON(w, status.OK, vendor)
+}
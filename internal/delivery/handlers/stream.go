@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+)
+
+// streamHeartbeat is how often an idle event stream sends a comment, so that
+// proxies don't close the connection and clients notice when it drops.
+const streamHeartbeat = 15 * time.Second
+
+// StreamVendorEventsHandler streams vendor changes as Server-Sent Events. A
+// client resumes after the last event it received with the Last-Event-ID
+// header, or the last_event_id parameter on its first connection. The filter
+// parameters of the vendor listing select events by the vendor each carries.
+func (h *VendorHandler) StreamVendorEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		slog.Error("Response writer does not support streaming")
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	filter, err := h.VendorService.ParseVendorFilter(r.URL.Query())
+	if err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	ctx := r.Context()
+	events, err := h.VendorService.StreamVendorEvents(ctx, filter, lastEventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEventID):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidEventID)
+		case errors.Is(err, domain.ErrEventsExpired):
+			utils.RespondWithErrorJSON(w, status.Gone, errs.EventsExpired)
+		default:
+			slog.Error("Error subscribing to vendor events: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(status.OK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				slog.Error("Error encoding vendor event: ", utils.Err(err))
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 	"net/http"
+	"path"
 	"strconv"
 	"vendors/internal/domain"
 	service "vendors/internal/service/interfaces"
@@ -22,80 +23,66 @@ type VendorHandler struct {
 	Router        *chi.Mux
 }
 
+// defaultNearRadiusMeters is used by the near endpoint when no radius is given.
+const defaultNearRadiusMeters = 1000.0
+
 type StatusMessage struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
+// GetAllVendorsHandler lists live vendors, narrowed down by any of the tags,
+// categories, type, q and has_* filter parameters.
 func (h *VendorHandler) GetAllVendorsHandler(w http.ResponseWriter, r *http.Request) {
-	page := 1      // Default page if not provided
-	pageSize := 10 // Default page size, adjust as needed
-
-	pageStr := r.URL.Query().Get("page")
-	if pageStr != "" {
-		pageNum, err := strconv.Atoi(pageStr)
-		if err != nil || pageNum < 1 {
-			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
-			return
-		}
-		page = pageNum
-	}
-
-	totalVendors, err := h.VendorService.GetTotalVendorsCount()
-	if err != nil {
-		slog.Error("Error getting total vendors count: ", utils.Err(err))
-		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+	opts, ok := parseListOptions(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(totalVendors) / float64(pageSize)))
-
-	vendors, err := h.VendorService.GetAllVendors(page, pageSize)
-	if err != nil {
-		slog.Error("Error getting vendors: ", utils.Err(err))
-		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+	opts.Sort, ok = parseSort(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidSort)
 		return
 	}
 
-	var prevPage interface{}
-	if page > 1 {
-		prevPage = page - 1
-	} else {
-		prevPage = nil
-	}
-
-	var nextPage interface{}
-	if len(vendors) == pageSize {
-		nextPage = page + 1
-	} else {
-		nextPage = nil
-	}
-
-	var firstPage interface{}
-	if totalPages > 0 {
-		firstPage = 1
-	} else {
-		firstPage = nil
+	opts.Facets, ok = parseFacets(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidFacet)
+		return
 	}
 
-	var lastPage interface{}
-	if totalPages >= 1 {
-		lastPage = totalPages
-	} else {
-		lastPage = firstPage
+	filter, err := h.VendorService.ParseVendorFilter(r.URL.Query())
+	if err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		return
 	}
 
-	pagination := map[string]interface{}{
-		"current_page": page,
-		"prev_page":    prevPage,
-		"next_page":    nextPage,
-		"first_page":   firstPage,
-		"last_page":    lastPage,
+	vendors, totalVendors, err := h.VendorService.FilterVendors(r.Context(), filter, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownVendorType) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCursor)
+			return
+		}
+		if errors.Is(err, domain.ErrPageTooDeep) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.PageTooDeep)
+			return
+		}
+		slog.Error("Error getting vendors: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
 	}
 
 	responseData := map[string]interface{}{
-		"vendors":    vendors,
-		"pagination": pagination,
+		"vendors":    vendors.Vendors,
+		"pagination": paginationBlock(opts, totalVendors, vendors.NextCursor),
+	}
+	if vendors.Facets != nil {
+		responseData["facets"] = vendors.Facets
 	}
 
 	utils.RespondWithJSON(w, status.OK, responseData)
@@ -111,7 +98,7 @@ func (h *VendorHandler) GetVendorByIDHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	vendor, err := h.VendorService.GetVendorByID(objectID)
+	vendor, err := h.VendorService.ResolveVendor(r.Context(), objectID)
 	if err != nil {
 		slog.Error("Error getting vendor by ID: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
@@ -123,6 +110,13 @@ func (h *VendorHandler) GetVendorByIDHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// A vendor found by the ID of a vendor merged into it points clients to
+	// its own URL.
+	if vendor.ID != objectID {
+		w.Header().Set("Content-Location", path.Join(path.Dir(r.URL.Path), vendor.ID.Hex()))
+	}
+
+	setETag(w, vendor.Version)
 	utils.RespondWithJSON(w, status.OK, vendor)
 }
 
@@ -134,13 +128,22 @@ func (h *VendorHandler) CreateVendorHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	vendor, err := h.VendorService.CreateVendor(&createVendorRequest)
+	vendor, err := h.VendorService.CreateVendor(r.Context(), &createVendorRequest)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCoordinates) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCoordinates)
+			return
+		}
+		if errors.Is(err, domain.ErrUnknownVendorType) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+			return
+		}
 		slog.Error("Error creating vendor: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, fmt.Sprintf("Error creating vendor: %v", err))
 		return
 	}
 
+	setETag(w, vendor.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(vendor)
@@ -156,7 +159,13 @@ func (h *VendorHandler) UpdateVendorHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	existingVendor, err := h.VendorService.GetVendorByID(objectID)
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidIfMatch)
+		return
+	}
+
+	existingVendor, err := h.VendorService.GetVendorByID(r.Context(), objectID)
 	if err != nil {
 		slog.Error("Error checking if vendor exists: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
@@ -174,17 +183,25 @@ func (h *VendorHandler) UpdateVendorHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	vendor, err := h.VendorService.UpdateVendor(objectID, &updateVendorRequest)
+	vendor, err := h.VendorService.UpdateVendor(r.Context(), objectID, &updateVendorRequest, expectedVersion)
 	if err != nil {
-		slog.Error("Error updating vendor: ", utils.Err(err))
-		if err.Error() == "vendor not found" {
+		switch {
+		case errors.Is(err, domain.ErrVendorNotFound):
 			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotFound)
-		} else {
+		case errors.Is(err, domain.ErrVersionConflict):
+			utils.RespondWithErrorJSON(w, status.PreconditionFailed, errs.VersionConflict)
+		case errors.Is(err, domain.ErrInvalidCoordinates):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCoordinates)
+		case errors.Is(err, domain.ErrVendorTypeChanged):
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.VendorTypeChanged)
+		default:
+			slog.Error("Error updating vendor: ", utils.Err(err))
 			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		}
 		return
 	}
 
+	setETag(w, vendor.Version)
 	utils.RespondWithJSON(w, status.OK, vendor)
 }
 
@@ -198,11 +215,20 @@ func (h *VendorHandler) DeleteVendor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.VendorService.DeleteVendor(objectID)
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidIfMatch)
+		return
+	}
+
+	err = h.VendorService.DeleteVendor(r.Context(), objectID, expectedVersion)
 	if err != nil {
-		if err.Error() == "vendor not found" {
+		switch {
+		case errors.Is(err, domain.ErrVendorNotFound):
 			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotFound)
-		} else {
+		case errors.Is(err, domain.ErrVersionConflict):
+			utils.RespondWithErrorJSON(w, status.PreconditionFailed, errs.VersionConflict)
+		default:
 			slog.Error("Error deleting vendor:", utils.Err(err))
 			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		}
@@ -217,157 +243,389 @@ func (h *VendorHandler) DeleteVendor(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, status.OK, response)
 }
 
-func (h *VendorHandler) SearchVendorsHandler(w http.ResponseWriter, r *http.Request) {
-	page := 1      // Default page if not provided
-	pageSize := 10 // Default page size, adjust as needed
-
-	pageStr := r.URL.Query().Get("page")
-	if pageStr != "" {
-		pageNum, err := strconv.Atoi(pageStr)
-		if err != nil || pageNum < 1 {
-			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+func (h *VendorHandler) GetDeletedVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	opts, ok := parseListOptions(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+		return
+	}
+
+	opts.Sort, ok = parseSort(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidSort)
+		return
+	}
+
+	totalVendors, err := h.VendorService.GetDeletedVendorsCount(r.Context())
+	if err != nil {
+		slog.Error("Error getting deleted vendors count: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	vendors, err := h.VendorService.GetDeletedVendors(r.Context(), opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCursor)
+			return
+		}
+		if errors.Is(err, domain.ErrPageTooDeep) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.PageTooDeep)
 			return
 		}
-		page = pageNum
+		slog.Error("Error getting deleted vendors: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"vendors":    vendors.Vendors,
+		"pagination": paginationBlock(opts, totalVendors, vendors.NextCursor),
+	}
+
+	utils.RespondWithJSON(w, status.OK, responseData)
+}
+
+func (h *VendorHandler) RestoreVendorHandler(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	objectID, err := primitive.ObjectIDFromHex(vendorID)
+	if err != nil {
+		slog.Error("Invalid vendor ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidVendorID)
+		return
+	}
+
+	err = h.VendorService.RestoreVendor(r.Context(), objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrVendorNotFound) {
+			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotInTrash)
+		} else {
+			slog.Error("Error restoring vendor:", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	vendor, err := h.VendorService.GetVendorByID(r.Context(), objectID)
+	if err != nil {
+		slog.Error("Error getting restored vendor: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+	if vendor != nil {
+		setETag(w, vendor.Version)
 	}
 
-	totalVendors, err := h.VendorService.GetTotalVendorsCount()
+	utils.RespondWithJSON(w, status.OK, vendor)
+}
+
+func (h *VendorHandler) PurgeDeletedVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	purged, err := h.VendorService.PurgeDeletedVendors(r.Context())
 	if err != nil {
-		slog.Error("Error getting total vendor count: ", utils.Err(err))
+		slog.Error("Error purging deleted vendors:", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(totalVendors) / float64(pageSize)))
+	responseData := map[string]interface{}{
+		"purged": purged,
+	}
 
-	query := r.URL.Query().Get("query")
+	utils.RespondWithJSON(w, status.OK, responseData)
+}
 
-	vendors, err := h.VendorService.SearchVendors(query, page, pageSize)
+func (h *VendorHandler) GetVendorHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	objectID, err := primitive.ObjectIDFromHex(vendorID)
 	if err != nil {
-		slog.Error("Error searching vendors: ", utils.Err(err))
+		slog.Error("Invalid vendor ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidVendorID)
+		return
+	}
+
+	entries, err := h.VendorService.GetVendorHistory(r.Context(), objectID)
+	if err != nil {
+		slog.Error("Error getting vendor history: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		return
 	}
 
-	var prevPage interface{}
-	if page > 1 {
-		prevPage = page - 1
-	} else {
-		prevPage = nil
+	if entries == nil {
+		entries = []*domain.VendorHistoryEntry{}
 	}
 
-	var nextPage interface{}
-	if len(vendors) == pageSize {
-		nextPage = page + 1
-	} else {
-		nextPage = nil
+	responseData := map[string]interface{}{
+		"history": entries,
 	}
 
-	var firstPage interface{}
-	if totalPages > 0 {
-		firstPage = 1
-	} else {
-		firstPage = nil
+	utils.RespondWithJSON(w, status.OK, responseData)
+}
+
+func (h *VendorHandler) RevertVendorHandler(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	objectID, err := primitive.ObjectIDFromHex(vendorID)
+	if err != nil {
+		slog.Error("Invalid vendor ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidVendorID)
+		return
+	}
+
+	revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 64)
+	if err != nil || revision < 1 {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRevision)
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidIfMatch)
+		return
+	}
+
+	vendor, err := h.VendorService.RevertVendor(r.Context(), objectID, revision, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRevisionNotFound):
+			utils.RespondWithErrorJSON(w, status.NotFound, errs.RevisionNotFound)
+		case errors.Is(err, domain.ErrVendorNotFound):
+			utils.RespondWithErrorJSON(w, status.NotFound, errs.VendorNotFound)
+		case errors.Is(err, domain.ErrVersionConflict):
+			utils.RespondWithErrorJSON(w, status.PreconditionFailed, errs.VersionConflict)
+		default:
+			slog.Error("Error reverting vendor: ", utils.Err(err))
+			utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		}
+		return
+	}
+
+	setETag(w, vendor.Version)
+	utils.RespondWithJSON(w, status.OK, vendor)
+}
+
+func (h *VendorHandler) SearchVendorsHandler(w http.ResponseWriter, r *http.Request) {
+	opts, ok := parseListOptions(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+		return
 	}
 
-	var lastPage interface{}
-	if totalPages >= 1 {
-		lastPage = totalPages
-	} else {
-		lastPage = firstPage
+	opts.Sort, ok = parseSort(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidSort)
+		return
 	}
 
-	pagination := map[string]interface{}{
-		"current_page": page,
-		"prev_page":    prevPage,
-		"next_page":    nextPage,
-		"first_page":   firstPage,
-		"last_page":    lastPage,
+	opts.Facets, ok = parseFacets(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidFacet)
+		return
+	}
+
+	opts.Type = r.URL.Query().Get("type")
+
+	query := domain.SearchQuery{
+		Text: r.URL.Query().Get("query"),
+		Mode: domain.SearchMode(r.URL.Query().Get("mode")),
+	}
+
+	vendors, totalVendors, err := h.VendorService.SearchVendors(r.Context(), query, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearchQuery) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUnknownVendorType) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCursor)
+			return
+		}
+		if errors.Is(err, domain.ErrPageTooDeep) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.PageTooDeep)
+			return
+		}
+		slog.Error("Error searching vendors: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
 	}
 
 	responseData := map[string]interface{}{
-		"vendors":    vendors,
-		"pagination": pagination,
+		"vendors":    vendors.Vendors,
+		"pagination": paginationBlock(opts, totalVendors, vendors.NextCursor),
+	}
+	if vendors.Facets != nil {
+		responseData["facets"] = vendors.Facets
 	}
 
 	utils.RespondWithJSON(w, status.OK, responseData)
 }
 
-func (h *VendorHandler) FilterVendorsByTagsHandler(w http.ResponseWriter, r *http.Request) {
-	page := 1      // Default page if not provided
-	pageSize := 10 // Default page size, adjust as needed
-	queryTags := r.URL.Query()["tags"]
-
-	pageStr := r.URL.Query().Get("page")
-	if pageStr != "" {
-		pageNum, err := strconv.Atoi(pageStr)
-		if err != nil || pageNum < 1 {
-			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+func (h *VendorHandler) FindVendorsNearHandler(w http.ResponseWriter, r *http.Request) {
+	opts, ok := parseListOptions(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+		return
+	}
+
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(r.URL.Query().Get("lng"), 64)
+	if latErr != nil || lngErr != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCoordinates)
+		return
+	}
+
+	radius := defaultNearRadiusMeters
+	if radiusStr := r.URL.Query().Get("radius"); radiusStr != "" {
+		parsed, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || parsed <= 0 || parsed > domain.MaxNearRadiusMeters {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRadius)
 			return
 		}
-		page = pageNum
+		radius = parsed
 	}
 
-	totalVendors, err := h.VendorService.GetTotalVendorsCount()
+	vendors, totalVendors, err := h.VendorService.FindVendorsNear(r.Context(), lat, lng, radius, opts)
 	if err != nil {
-		slog.Error("Error getting total vendors count: ", utils.Err(err))
+		if errors.Is(err, domain.ErrInvalidCoordinates) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCoordinates)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCursor)
+			return
+		}
+		if errors.Is(err, domain.ErrPageTooDeep) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.PageTooDeep)
+			return
+		}
+		slog.Error("Error finding vendors near point: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(totalVendors) / float64(pageSize)))
+	responseData := map[string]interface{}{
+		"vendors":    vendors.Vendors,
+		"pagination": paginationBlock(opts, totalVendors, vendors.NextCursor),
+	}
 
-	if len(queryTags) == 0 {
-		utils.RespondWithErrorJSON(w, status.BadRequest, errs.MissingTags)
+	utils.RespondWithJSON(w, status.OK, responseData)
+}
+
+// GetVendorMapHandler serves the vendors inside a map viewport, given either
+// as bbox=minLng,minLat,maxLng,maxLat or as a GeoJSON polygon. A grid
+// parameter switches to clustered results.
+func (h *VendorHandler) GetVendorMapHandler(w http.ResponseWriter, r *http.Request) {
+	query, ok := parseMapQuery(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidMapArea)
 		return
 	}
 
-	vendors, err := h.VendorService.FilterVendorsByTags(queryTags, page, pageSize)
+	vendorMap, err := h.VendorService.GetVendorMap(r.Context(), query)
 	if err != nil {
-		slog.Error("Error filtering vendors by tags: ", utils.Err(err))
+		if errors.Is(err, domain.ErrInvalidMapArea) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidMapArea)
+			return
+		}
+		slog.Error("Error getting vendor map: ", utils.Err(err))
 		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
 		return
 	}
 
-	var prevPage interface{}
-	if page > 1 {
-		prevPage = page - 1
-	} else {
-		prevPage = nil
+	utils.RespondWithJSON(w, status.OK, vendorMap)
+}
+
+// GetVendorFacetsHandler counts live vendors by tag, category and type, or by
+// the fields named in the facets parameter.
+func (h *VendorHandler) GetVendorFacetsHandler(w http.ResponseWriter, r *http.Request) {
+	fields, ok := parseFacets(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidFacet)
+		return
+	}
+	if len(fields) == 0 {
+		fields = domain.FacetFields
 	}
 
-	var nextPage interface{}
-	if len(vendors) == pageSize {
-		nextPage = page + 1
-	} else {
-		nextPage = nil
+	facets, err := h.VendorService.GetVendorFacets(r.Context(), fields, r.URL.Query().Get("type"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownVendorType) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+			return
+		}
+		slog.Error("Error counting vendor facets: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
 	}
 
-	var firstPage interface{}
-	if totalPages > 0 {
-		firstPage = 1
-	} else {
-		firstPage = nil
+	utils.RespondWithJSON(w, status.OK, map[string]interface{}{"facets": facets})
+}
+
+// FilterVendorsByTagsHandler lists vendors by their tags. The tags_match
+// parameter, or match without it, selects vendors with all, any or none of
+// the tags, and exclude_tags drops vendors with any of the given tags.
+func (h *VendorHandler) FilterVendorsByTagsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.VendorService.ParseVendorFilter(r.URL.Query())
+	if err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+		return
 	}
 
-	var lastPage interface{}
-	if totalPages >= 1 {
-		lastPage = totalPages
-	} else {
-		lastPage = firstPage
+	opts, ok := parseListOptions(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestFormat)
+		return
+	}
+
+	opts.Sort, ok = parseSort(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidSort)
+		return
+	}
+
+	opts.Facets, ok = parseFacets(r)
+	if !ok {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidFacet)
+		return
+	}
+
+	opts.Type = filter.Type
+
+	if filter.Tags.Empty() {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.MissingTags)
+		return
 	}
 
-	pagination := map[string]interface{}{
-		"current_page": page,
-		"prev_page":    prevPage,
-		"next_page":    nextPage,
-		"first_page":   firstPage,
-		"last_page":    lastPage,
+	vendors, totalVendors, err := h.VendorService.FilterVendorsByTags(r.Context(), filter.Tags, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownVendorType) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.UnknownVendorType)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidCursor)
+			return
+		}
+		if errors.Is(err, domain.ErrPageTooDeep) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, errs.PageTooDeep)
+			return
+		}
+		slog.Error("Error filtering vendors by tags: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
 	}
 
 	responseData := map[string]interface{}{
-		"vendors":    vendors,
-		"pagination": pagination,
+		"vendors":    vendors.Vendors,
+		"pagination": paginationBlock(opts, totalVendors, vendors.NextCursor),
+	}
+	if vendors.Facets != nil {
+		responseData["facets"] = vendors.Facets
 	}
 
 	utils.RespondWithJSON(w, status.OK, responseData)
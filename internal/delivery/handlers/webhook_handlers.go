@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"vendors/internal/domain"
+	service "vendors/internal/service/interfaces"
+	"vendors/pkg/lib/errs"
+	"vendors/pkg/lib/status"
+	"vendors/pkg/lib/utils"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookHandler struct {
+	WebhookService service.WebhookService
+	Router         *chi.Mux
+}
+
+func (h *WebhookHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		slog.Error("Error decoding request body: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidRequestBody)
+		return
+	}
+
+	webhook, err := h.WebhookService.CreateWebhook(r.Context(), &request)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidWebhook) {
+			utils.RespondWithErrorJSON(w, status.BadRequest, err.Error())
+			return
+		}
+		slog.Error("Error creating webhook: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	utils.RespondWithJSON(w, status.Created, webhook)
+}
+
+func (h *WebhookHandler) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.WebhookService.GetWebhooks(r.Context())
+	if err != nil {
+		slog.Error("Error getting webhooks: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+		return
+	}
+
+	if webhooks == nil {
+		webhooks = []*domain.Webhook{}
+	}
+
+	utils.RespondWithJSON(w, status.OK, map[string]interface{}{"webhooks": webhooks})
+}
+
+func (h *WebhookHandler) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.WebhookService.GetWebhook(r.Context(), webhookID)
+	if err != nil {
+		respondWebhookError(w, err, "Error getting webhook: ")
+		return
+	}
+
+	utils.RespondWithJSON(w, status.OK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.WebhookService.DeleteWebhook(r.Context(), webhookID); err != nil {
+		respondWebhookError(w, err, "Error deleting webhook: ")
+		return
+	}
+
+	utils.RespondWithJSON(w, status.OK, StatusMessage{
+		Code:    200,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// GetDeliveriesHandler serves the delivery log of a webhook. The status
+// parameter narrows it down; status=dead lists the dead letters.
+func (h *WebhookHandler) GetDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryStatus := domain.DeliveryStatus(r.URL.Query().Get("status"))
+	switch deliveryStatus {
+	case "", domain.DeliveryPending, domain.DeliverySucceeded, domain.DeliveryDead:
+	default:
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidDeliveryStatus)
+		return
+	}
+
+	deliveries, err := h.WebhookService.GetDeliveries(r.Context(), webhookID, deliveryStatus)
+	if err != nil {
+		respondWebhookError(w, err, "Error getting webhook deliveries: ")
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+
+	utils.RespondWithJSON(w, status.OK, map[string]interface{}{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) RetryDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "delivery"))
+	if err != nil {
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidDeliveryID)
+		return
+	}
+
+	delivery, err := h.WebhookService.RetryDelivery(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		respondWebhookError(w, err, "Error retrying webhook delivery: ")
+		return
+	}
+
+	utils.RespondWithJSON(w, status.OK, delivery)
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	webhookID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Invalid webhook ID: ", utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.BadRequest, errs.InvalidWebhookID)
+		return webhookID, false
+	}
+	return webhookID, true
+}
+
+func respondWebhookError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		utils.RespondWithErrorJSON(w, status.NotFound, errs.WebhookNotFound)
+	case errors.Is(err, domain.ErrDeliveryNotFound):
+		utils.RespondWithErrorJSON(w, status.NotFound, errs.DeliveryNotFound)
+	default:
+		slog.Error(message, utils.Err(err))
+		utils.RespondWithErrorJSON(w, status.InternalServerError, errs.InternalServerError)
+	}
+}
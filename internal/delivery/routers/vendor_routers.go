@@ -13,11 +13,30 @@ func SetupVendorRouter(vendorRouter *chi.Mux, vendorService *service.VendorServi
 		VendorService: vendorService,
 	}
 
+	vendorRouter.Use(handlers.ActorMiddleware)
+
 	vendorRouter.Get("/", vendorHandler.GetAllVendorsHandler)
 	vendorRouter.Get("/{id}", vendorHandler.GetVendorByIDHandler)
 	vendorRouter.Post("/", vendorHandler.CreateVendorHandler)
 	vendorRouter.Put("/{id}", vendorHandler.UpdateVendorHandler)
+	vendorRouter.Patch("/{id}", vendorHandler.PatchVendorHandler)
 	vendorRouter.Delete("/{id}", vendorHandler.DeleteVendor)
 	vendorRouter.Get("/search", vendorHandler.SearchVendorsHandler)
+	vendorRouter.Get("/near", vendorHandler.FindVendorsNearHandler)
+	vendorRouter.Get("/map", vendorHandler.GetVendorMapHandler)
+	vendorRouter.Get("/facets", vendorHandler.GetVendorFacetsHandler)
+	vendorRouter.Get("/stream", vendorHandler.StreamVendorEventsHandler)
+	vendorRouter.Post("/bulk", vendorHandler.BulkCreateVendorsHandler)
+	vendorRouter.Put("/bulk", vendorHandler.BulkUpdateVendorsHandler)
+	vendorRouter.Post("/bulk/delete", vendorHandler.BulkDeleteVendorsHandler)
+	vendorRouter.Post("/import", vendorHandler.ImportVendorsHandler)
+	vendorRouter.Get("/export", vendorHandler.ExportVendorsHandler)
+	vendorRouter.Get("/duplicates", vendorHandler.FindDuplicateVendorsHandler)
 	vendorRouter.Get("/filter/tags", vendorHandler.FilterVendorsByTagsHandler)
+	vendorRouter.Get("/trash", vendorHandler.GetDeletedVendorsHandler)
+	vendorRouter.Delete("/trash", vendorHandler.PurgeDeletedVendorsHandler)
+	vendorRouter.Post("/{id}/merge", vendorHandler.MergeVendorsHandler)
+	vendorRouter.Post("/{id}/restore", vendorHandler.RestoreVendorHandler)
+	vendorRouter.Get("/{id}/history", vendorHandler.GetVendorHistoryHandler)
+	vendorRouter.Post("/{id}/history/{revision}/revert", vendorHandler.RevertVendorHandler)
 }
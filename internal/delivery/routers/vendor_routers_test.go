@@ -0,0 +1,961 @@
+package routers_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/delivery/routers"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/memory"
+	"vendors/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testWebhooks retries failed deliveries quickly and gives up after three.
+var testWebhooks = config.Webhooks{
+	Timeout:        time.Second,
+	MaxAttempts:    3,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     40 * time.Millisecond,
+	PollInterval:   10 * time.Millisecond,
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	webhookService := service.NewWebhookService(repository.NewMemoryWebhookRepository(), testWebhooks)
+	webhookRouter := chi.NewRouter()
+	routers.SetupWebhookRouter(webhookRouter, webhookService)
+
+	vendorRouter := chi.NewRouter()
+	routers.SetupVendorRouter(vendorRouter, service.NewVendorService(repository.NewMemoryVendorRepository(), repository.NewMemoryHistoryRepository(), webhookService, config.Trash{}, config.Events{Buffer: 100}))
+
+	mainRouter := chi.NewRouter()
+	mainRouter.Mount("/api/vendor", vendorRouter)
+	mainRouter.Mount("/api/webhook", webhookRouter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go webhookService.RunWebhookDispatcher(ctx)
+
+	server := httptest.NewServer(mainRouter)
+	t.Cleanup(server.Close)
+	t.Cleanup(cancel)
+	return server
+}
+
+func TestVendorAPIEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	body, _ := json.Marshal(domain.CreateVendorRequest{Name: "Pizza Place", Tags: []string{"pizza"}})
+	resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created domain.CreateVendorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.False(t, created.ID.IsZero())
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCount  int
+	}{
+		{name: "List", path: "/api/vendor/", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "Search", path: "/api/vendor/search?query=pizza", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "Filter by tags", path: "/api/vendor/filter/tags?tags=pizza", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "Filter by unknown tag", path: "/api/vendor/filter/tags?tags=sushi", wantStatus: http.StatusOK, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			var payload struct {
+				Vendors []domain.GetVendorResponse `json:"vendors"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+			assert.Len(t, payload.Vendors, tt.wantCount)
+		})
+	}
+
+	resp, err = http.Get(server.URL + "/api/vendor/search?mode=regex&query=(")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/?sort=-created_at,name")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/?sort=phone_numbers")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/" + created.ID.Hex())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	body, _ = json.Marshal(domain.UpdateVendorRequest{Name: "Pizza Palace"})
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/vendor/"+created.ID.Hex(), bytes.NewReader(body))
+	req.Header.Set("If-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+
+	req, _ = http.NewRequest(http.MethodPut, server.URL+"/api/vendor/"+created.ID.Hex(), bytes.NewReader(body))
+	req.Header.Set("If-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/api/vendor/"+created.ID.Hex(), nil)
+	req.Header.Set("If-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/api/vendor/"+created.ID.Hex(), nil)
+	req.Header.Set("If-Match", `"2"`)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/" + created.ID.Hex())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/trash")
+	require.NoError(t, err)
+	var trash struct {
+		Vendors []domain.GetVendorResponse `json:"vendors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&trash))
+	resp.Body.Close()
+	require.Len(t, trash.Vendors, 1)
+	assert.Equal(t, created.ID, trash.Vendors[0].ID)
+
+	resp, err = http.Post(server.URL+"/api/vendor/"+created.ID.Hex()+"/restore", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/vendor/" + created.ID.Hex())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestVendorHistoryEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	send := func(method, path string, payload interface{}) *http.Response {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req, _ := http.NewRequest(method, server.URL+path, &body)
+		req.Header.Set("X-Actor", "editor")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/api/vendor/", domain.CreateVendorRequest{Name: "Pizza Place", PhoneNumbers: []string{"111"}})
+	var created domain.CreateVendorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	path := "/api/vendor/" + created.ID.Hex()
+	send(http.MethodPut, path, domain.UpdateVendorRequest{Name: "Pizza Place", PhoneNumbers: []string{"222"}}).Body.Close()
+
+	resp = send(http.MethodGet, path+"/history", nil)
+	var payload struct {
+		History []domain.VendorHistoryEntry `json:"history"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	resp.Body.Close()
+
+	require.Len(t, payload.History, 2)
+	assert.Equal(t, domain.HistoryActionCreated, payload.History[0].Action)
+	assert.Equal(t, "editor", payload.History[1].Actor)
+	assert.Equal(t, int64(2), payload.History[1].Revision)
+	require.Len(t, payload.History[1].Changes, 1)
+	assert.Equal(t, "phone_numbers", payload.History[1].Changes[0].Field)
+
+	resp = send(http.MethodPost, path+"/history/1/revert", nil)
+	var reverted domain.UpdateVendorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reverted))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"111"}, reverted.PhoneNumbers)
+	assert.Equal(t, int64(3), reverted.Version)
+
+	resp = send(http.MethodPost, path+"/history/42/revert", nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestVendorMapEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, vendor := range []domain.CreateVendorRequest{
+		{Name: "a", Coordinates: domain.NewGeoPoint(1.1, 1.1)},
+		{Name: "b", Coordinates: domain.NewGeoPoint(1.2, 1.2)},
+		{Name: "c", Coordinates: domain.NewGeoPoint(8.5, 8.5)},
+		{Name: "outside", Coordinates: domain.NewGeoPoint(20, 20)},
+		{Name: "unplaced"},
+	} {
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	get := func(path string) (*http.Response, domain.VendorMap) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var payload domain.VendorMap
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		}
+		return resp, payload
+	}
+
+	resp, payload := get("/api/vendor/map?bbox=0,0,10,10")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, payload.Vendors, 3)
+	assert.Empty(t, payload.Clusters)
+	assert.False(t, payload.Truncated)
+
+	resp, payload = get("/api/vendor/map?bbox=0,0,10,10&grid=5")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, payload.Vendors, 1)
+	assert.Equal(t, "c", payload.Vendors[0].Name)
+	require.Len(t, payload.Clusters, 1)
+	assert.Equal(t, 2, payload.Clusters[0].Count)
+	assert.InDelta(t, 1.15, payload.Clusters[0].Coordinates.Lng(), 1e-9)
+
+	resp, payload = get(`/api/vendor/map?polygon={"type":"Polygon","coordinates":[[[0,0],[5,0],[0,5],[0,0]]]}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, payload.Vendors, 2)
+
+	for _, path := range []string{
+		"/api/vendor/map",
+		"/api/vendor/map?bbox=10,10,0,0",
+		"/api/vendor/map?bbox=0,0,10",
+		"/api/vendor/map?bbox=0,0,10,10&grid=1000",
+		`/api/vendor/map?polygon={"type":"Polygon","coordinates":[[[0,0],[5,0],[0,5]]]}`,
+	} {
+		resp, _ = get(path)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+	}
+}
+
+func TestVendorBulkEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	send := func(method, path string, payload interface{}) (*http.Response, domain.BulkResult) {
+		t.Helper()
+		var body bytes.Buffer
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		req, _ := http.NewRequest(method, server.URL+path, &body)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result domain.BulkResult
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp, result
+	}
+
+	statuses := func(result domain.BulkResult) []domain.BulkItemStatus {
+		var statuses []domain.BulkItemStatus
+		for _, item := range result.Results {
+			statuses = append(statuses, item.Status)
+		}
+		return statuses
+	}
+
+	invalid := map[string]interface{}{"name": "Nowhere", "coordinates": map[string]interface{}{"type": "Point", "coordinates": []float64{500, 0}}}
+
+	resp, result := send(http.MethodPost, "/api/vendor/bulk", map[string]interface{}{
+		"vendors": []interface{}{map[string]string{"name": "a"}, invalid, map[string]string{"name": "b"}},
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemFailed, domain.BulkItemSkipped}, statuses(result))
+	assert.Equal(t, "Invalid coordinates", result.Results[1].Error)
+
+	resp, result = send(http.MethodPost, "/api/vendor/bulk", map[string]interface{}{
+		"ordered": false,
+		"vendors": []interface{}{map[string]string{"name": "c"}, invalid, map[string]string{"name": "d"}},
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemFailed, domain.BulkItemOK}, statuses(result))
+	assert.Equal(t, 2, result.Succeeded)
+	c, d := result.Results[0], result.Results[2]
+
+	resp, result = send(http.MethodPut, "/api/vendor/bulk", map[string]interface{}{
+		"ordered": false,
+		"vendors": []interface{}{
+			map[string]interface{}{"_id": c.ID, "version": c.Version, "name": "c2"},
+			map[string]interface{}{"_id": d.ID, "version": 42, "name": "d2"},
+		},
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemFailed}, statuses(result))
+	assert.Equal(t, int64(2), result.Results[0].Version)
+	assert.Equal(t, "Vendor was modified by another request", result.Results[1].Error)
+
+	resp, result = send(http.MethodPost, "/api/vendor/bulk/delete", map[string]interface{}{
+		"vendors": []interface{}{map[string]interface{}{"_id": c.ID}, map[string]interface{}{"_id": c.ID}},
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemFailed}, statuses(result))
+
+	resp, _ = send(http.MethodPost, "/api/vendor/bulk", map[string]interface{}{"vendors": []interface{}{}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	tooMany := make([]map[string]string, domain.MaxBulkItems+1)
+	resp, _ = send(http.MethodPost, "/api/vendor/bulk", map[string]interface{}{"vendors": tooMany})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestVendorPatchEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	body, _ := json.Marshal(domain.CreateVendorRequest{Name: "Pizza Place", Location: "Main St", Tags: []string{"pizza", "pasta"}})
+	resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	var created domain.CreateVendorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	path := server.URL + "/api/vendor/" + created.ID.Hex()
+	patch := func(contentType, body, ifMatch string) (*http.Response, domain.UpdateVendorResponse) {
+		req, _ := http.NewRequest(http.MethodPatch, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", contentType)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var vendor domain.UpdateVendorResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&vendor))
+		}
+		return resp, vendor
+	}
+
+	resp, vendor := patch("application/merge-patch+json", `{"name":"Pizza Palace","location":null}`, `"1"`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	assert.Equal(t, "Pizza Palace", vendor.Name)
+	assert.Empty(t, vendor.Location)
+	assert.Equal(t, []string{"pizza", "pasta"}, vendor.Tags)
+
+	resp, vendor = patch("application/json-patch+json", `[{"op":"add","path":"/tags/-","value":"salad"},{"op":"remove","path":"/tags/0"},{"op":"add","path":"/media/-","value":"menu.png"}]`, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"pasta", "salad"}, vendor.Tags)
+	assert.Equal(t, []string{"menu.png"}, vendor.Media)
+	assert.Equal(t, int64(3), vendor.Version)
+
+	resp, _ = patch("application/json-patch+json", `[{"op":"test","path":"/name","value":"Sushi Bar"}]`, "")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, _ = patch("application/merge-patch+json", `{"rating":5}`, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = patch("application/merge-patch+json", `{"name":"Stale"}`, `"1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, _ = patch("application/json", `{"name":"Plain"}`, "")
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Get(path + "/history")
+	require.NoError(t, err)
+	var history struct {
+		History []domain.VendorHistoryEntry `json:"history"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+	resp.Body.Close()
+	require.Len(t, history.History, 3)
+	assert.Equal(t, "tags", history.History[2].Changes[1].Field)
+}
+
+func TestVendorFacetsEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, vendor := range []domain.CreateVendorRequest{
+		{Name: "Pizza Place", Type: "food", Tags: []string{"pizza", "vegan"}},
+		{Name: "Pizza Corner", Type: "food", Tags: []string{"pizza"}, Categories: []string{"takeaway"}},
+		{Name: "Sushi Bar", Type: "food", Tags: []string{"sushi"}},
+	} {
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	get := func(path string) (*http.Response, map[string][]domain.FacetCount) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var payload struct {
+			Facets map[string][]domain.FacetCount `json:"facets"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		return resp, payload.Facets
+	}
+
+	resp, facets := get("/api/vendor/facets")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.FacetCount{{Value: "pizza", Count: 2}, {Value: "sushi", Count: 1}, {Value: "vegan", Count: 1}}, facets["tags"])
+	assert.Equal(t, []domain.FacetCount{{Value: "takeaway", Count: 1}}, facets["categories"])
+	assert.Equal(t, []domain.FacetCount{{Value: "food", Count: 3}}, facets["type"])
+
+	resp, facets = get("/api/vendor/search?query=pizza&facets=tags")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.FacetCount{{Value: "pizza", Count: 2}, {Value: "vegan", Count: 1}}, facets["tags"])
+	assert.NotContains(t, facets, "type")
+
+	resp, facets = get("/api/vendor/filter/tags?tags=pizza&facets=categories")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.FacetCount{{Value: "takeaway", Count: 1}}, facets["categories"])
+
+	resp, _ = get("/api/vendor/facets?facets=name")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestVendorFilterEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, vendor := range []domain.CreateVendorRequest{
+		{Name: "Green Pizza", Type: "food", Tags: []string{"vegan", "pizza"}, Websites: []string{"green.example"}},
+		{Name: "Pizza Express", Type: "food", Tags: []string{"pizza"}},
+		{Name: "Vegan Cinema", Type: "cinema", Tags: []string{"vegan"}},
+	} {
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	list := func(query string) (*http.Response, []string) {
+		resp, err := http.Get(server.URL + "/api/vendor/?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var payload struct {
+			Vendors []domain.GetVendorResponse `json:"vendors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+
+		var names []string
+		for _, vendor := range payload.Vendors {
+			names = append(names, vendor.Name)
+		}
+		return resp, names
+	}
+
+	resp, names := list("tags=pizza&q=green&has_website=true")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"Green Pizza"}, names)
+
+	_, names = list("tags=vegan,pizza")
+	assert.Equal(t, []string{"Green Pizza"}, names)
+
+	_, names = list("tags=vegan&type=cinema")
+	assert.Equal(t, []string{"Vegan Cinema"}, names)
+
+	_, names = list("has_website=false&type=food")
+	assert.Equal(t, []string{"Pizza Express"}, names)
+
+	_, names = list("tags=vegan,pizza&match=any&exclude_tags=pizza")
+	assert.Equal(t, []string{"Vegan Cinema"}, names)
+
+	_, names = list("tags=vegan&match=none")
+	assert.Equal(t, []string{"Pizza Express"}, names)
+
+	resp, _ = list("has_website=maybe")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = list("tags=vegan&match=some")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err := http.Get(server.URL + "/api/vendor/filter/tags?tags=pizza&match=any&exclude_tags=vegan")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var payload struct {
+		Vendors []domain.GetVendorResponse `json:"vendors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	require.Len(t, payload.Vendors, 1)
+	assert.Equal(t, "Pizza Express", payload.Vendors[0].Name)
+}
+
+func TestVendorTotalCountsEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	for i := 0; i < 12; i++ {
+		vendor := domain.CreateVendorRequest{Name: fmt.Sprintf("Cinema %d", i), Type: "cinema", Tags: []string{"film"}}
+		if i < 3 {
+			vendor = domain.CreateVendorRequest{Name: fmt.Sprintf("Pizza %d", i), Type: "food", Tags: []string{"pizza"}}
+		}
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	pagination := func(path string) (int, int) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var payload struct {
+			Pagination struct {
+				TotalCount int `json:"total_count"`
+				TotalPages int `json:"total_pages"`
+			} `json:"pagination"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		return payload.Pagination.TotalCount, payload.Pagination.TotalPages
+	}
+
+	tests := []struct {
+		path  string
+		count int
+		pages int
+	}{
+		{path: "/api/vendor/", count: 12, pages: 2},
+		{path: "/api/vendor/search?query=pizza&mode=prefix", count: 3, pages: 1},
+		{path: "/api/vendor/search?query=pizza", count: 3, pages: 1},
+		{path: "/api/vendor/?tags=film", count: 9, pages: 1},
+		{path: "/api/vendor/filter/tags?tags=pizza&match=none", count: 9, pages: 1},
+		{path: "/api/vendor/filter/tags?tags=sushi", count: 0, pages: 0},
+	}
+
+	for _, tt := range tests {
+		count, pages := pagination(tt.path)
+		assert.Equal(t, tt.count, count, tt.path)
+		assert.Equal(t, tt.pages, pages, tt.path)
+	}
+}
+
+// openStream subscribes to the vendor event stream. The handler subscribes
+// before it sends the response headers, so changes made once this returns
+// are part of the stream.
+func openStream(t *testing.T, url string, lastEventID string) *bufio.Scanner {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return bufio.NewScanner(resp.Body)
+}
+
+// readEvents reads the next n events from a stream.
+func readEvents(t *testing.T, stream *bufio.Scanner, n int) []domain.VendorEvent {
+	t.Helper()
+
+	var events []domain.VendorEvent
+	for len(events) < n && stream.Scan() {
+		data, ok := strings.CutPrefix(stream.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event domain.VendorEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, n)
+	return events
+}
+
+func TestVendorStreamEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	create := func(vendor domain.CreateVendorRequest) domain.CreateVendorResponse {
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var created domain.CreateVendorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		return created
+	}
+
+	stream := openStream(t, server.URL+"/api/vendor/stream?type=food&tags=pizza", "")
+	barStream := openStream(t, server.URL+"/api/vendor/stream?categories=bar&q=sushi&has_cover=false", "")
+
+	create(domain.CreateVendorRequest{Name: "Cinema", Type: "cinema", Tags: []string{"pizza"}})
+	pizza := create(domain.CreateVendorRequest{Name: "Pizza", Type: "food", Tags: []string{"pizza"}})
+	create(domain.CreateVendorRequest{Name: "Sushi", Type: "food", Tags: []string{"sushi"}, Categories: []string{"bar"}})
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/vendor/"+pizza.ID.Hex(), nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	events := readEvents(t, stream, 2)
+	assert.Equal(t, domain.VendorEventCreated, events[0].Type)
+	assert.Equal(t, pizza.ID, events[0].VendorID)
+	assert.Equal(t, "Pizza", events[0].Vendor.Name)
+	assert.Equal(t, domain.VendorEventDeleted, events[1].Type)
+	assert.Equal(t, pizza.ID, events[1].VendorID)
+
+	bar := readEvents(t, barStream, 1)
+	assert.Equal(t, "Sushi", bar[0].Vendor.Name, "every listing filter applies to the stream")
+
+	resumed := readEvents(t, openStream(t, server.URL+"/api/vendor/stream", events[0].ID), 2)
+	assert.Equal(t, "Sushi", resumed[0].Vendor.Name)
+	assert.Equal(t, domain.VendorEventDeleted, resumed[1].Type)
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/api/vendor/stream", nil)
+	req.Header.Set("Last-Event-ID", "bogus")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestVendorImportEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	send := func(query, contentType, body string) (*http.Response, domain.ImportReport) {
+		t.Helper()
+		resp, err := http.Post(server.URL+"/api/vendor/import"+query, contentType, strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var report domain.ImportReport
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		}
+		return resp, report
+	}
+
+	actions := func(report domain.ImportReport) []domain.ImportAction {
+		var actions []domain.ImportAction
+		for _, row := range report.Rows {
+			actions = append(actions, row.Action)
+		}
+		return actions
+	}
+
+	csv := "name,type,tags,longitude,latitude\n" +
+		"Pizza Place,food,pizza|vegan,13.4,52.5\n" +
+		"Odeon,cinema,,,\n" +
+		"Nowhere,food,,500,0\n" +
+		"Odd,pub,,,\n"
+
+	resp, report := send("?dry_run=true", "text/csv", csv)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []domain.ImportAction{domain.ImportCreated, domain.ImportCreated, domain.ImportFailed, domain.ImportFailed}, actions(report))
+	assert.Equal(t, []int{2, 3, 4, 5}, []int{report.Rows[0].Line, report.Rows[1].Line, report.Rows[2].Line, report.Rows[3].Line})
+	assert.Equal(t, "Invalid coordinates", report.Rows[2].Error)
+	assert.Equal(t, "Unknown vendor type", report.Rows[3].Error)
+
+	resp, _ = send("", "application/json", `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	list := func() []*domain.GetVendorResponse {
+		t.Helper()
+		resp, err := http.Get(server.URL + "/api/vendor/?page_size=50")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var page struct {
+			Vendors []*domain.GetVendorResponse `json:"vendors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+		return page.Vendors
+	}
+	assert.Empty(t, list())
+
+	resp, report = send("?key=name", "text/csv", csv)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.False(t, report.Rows[0].ID.IsZero())
+	pizzaID := report.Rows[0].ID
+
+	ndjson := `{"name":"Pizza Place","type":"food","tags":["pizza"]}` + "\n" +
+		"\n" +
+		`{"name":"Opera","type":"theatre"}` + "\n" +
+		`{"name":"Opera","type":"theatre"}` + "\n" +
+		`{"name":"Odeon","type":"food"}` + "\n" +
+		`{"name":"Broken","colour":"red"}` + "\n"
+
+	resp, report = send("?format=ndjson&key=name", "", ndjson)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.ImportAction{domain.ImportUpdated, domain.ImportCreated, domain.ImportFailed, domain.ImportFailed, domain.ImportFailed}, actions(report))
+	assert.Equal(t, pizzaID, report.Rows[0].ID)
+	assert.Equal(t, 3, report.Rows[1].Line)
+	assert.Contains(t, report.Rows[2].Error, "duplicate import key")
+	assert.Equal(t, "Vendor type cannot be changed", report.Rows[3].Error)
+	assert.Contains(t, report.Rows[4].Error, "unknown field")
+
+	var pizza *domain.GetVendorResponse
+	for _, vendor := range list() {
+		if vendor.ID == pizzaID {
+			pizza = vendor
+		}
+	}
+	require.NotNil(t, pizza)
+	assert.Equal(t, []string{"pizza"}, pizza.Tags)
+	assert.Equal(t, int64(2), pizza.Version)
+
+	resp, report = send("?key=_id", "application/x-ndjson", fmt.Sprintf(`{"_id":%q,"name":"Pizza Palace","type":"food"}`+"\n"+`{"_id":"nope","name":"x","type":"food"}`, pizzaID.Hex()))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.ImportAction{domain.ImportUpdated, domain.ImportFailed}, actions(report))
+
+	full := `{"name":"Cinema City","type":"cinema","location":"Main Street 1","phone_numbers":["111"],"media":["a.jpg"],"tags":["imax"],"coordinates":{"type":"Point","coordinates":[13.4,52.5]}}`
+	resp, report = send("?key=name", "application/x-ndjson", full)
+	require.Equal(t, []domain.ImportAction{domain.ImportCreated}, actions(report))
+	cinemaID := report.Rows[0].ID
+
+	resp, report = send("?key=name", "text/csv", "name,tags\nCinema City,imax|3d\n")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.ImportAction{domain.ImportUpdated}, actions(report), "a row without a type may update")
+	resp, report = send("?key=_id", "application/x-ndjson", fmt.Sprintf(`{"_id":%q,"Location":"Main Street 2"}`, cinemaID.Hex()))
+	assert.Equal(t, []domain.ImportAction{domain.ImportUpdated}, actions(report))
+
+	var cinema *domain.GetVendorResponse
+	for _, vendor := range list() {
+		if vendor.ID == cinemaID {
+			cinema = vendor
+		}
+	}
+	require.NotNil(t, cinema)
+	assert.Equal(t, []string{"imax", "3d"}, cinema.Tags)
+	assert.Equal(t, "Main Street 2", cinema.Location)
+	assert.Equal(t, domain.VendorTypeCinema, cinema.Type)
+	assert.Equal(t, []string{"111"}, cinema.PhoneNumbers, "columns left out of an import are kept")
+	assert.Equal(t, []string{"a.jpg"}, cinema.Media)
+	assert.Equal(t, domain.NewGeoPoint(13.4, 52.5), cinema.Coordinates)
+
+	resp, report = send("?key=name", "text/csv", "name,tags\nNew Place,x\n")
+	assert.Equal(t, "Unknown vendor type", report.Rows[0].Error, "a row that creates a vendor needs a type")
+
+	resp, _ = send("", "text/csv", "name,colour\nx,red\n")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = send("?key=location", "text/csv", "name\nx\n")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = send("?dry_run=maybe", "text/csv", "name\nx\n")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestVendorExportEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, vendor := range []domain.CreateVendorRequest{
+		{Name: "Pizza Place", Type: domain.VendorTypeFood, Tags: []string{"pizza", "vegan"}, Coordinates: domain.NewGeoPoint(13.4, 52.5)},
+		{Name: "Odeon", Type: domain.VendorTypeCinema, PhoneNumbers: []string{"+49 30 1234"}},
+		{Name: "Burger Bar", Type: domain.VendorTypeFood, Tags: []string{"burger"}},
+	} {
+		body, _ := json.Marshal(vendor)
+		resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	export := func(query string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + "/api/vendor/export" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body strings.Builder
+		_, err = io.Copy(&body, resp.Body)
+		require.NoError(t, err)
+		return resp, body.String()
+	}
+
+	resp, body := export("?type=food&sort=name")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var vendors []domain.GetVendorResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &vendors))
+	require.Len(t, vendors, 2)
+	assert.Equal(t, "Burger Bar", vendors[0].Name)
+	assert.Equal(t, "Pizza Place", vendors[1].Name)
+
+	_, body = export("?type=cinema")
+	require.NoError(t, json.Unmarshal([]byte(body), &vendors))
+	require.Len(t, vendors, 1)
+	assert.Equal(t, "Odeon", vendors[0].Name)
+
+	_, body = export("?tags=sushi")
+	assert.Equal(t, "[]\n", body)
+
+	resp, body = export("?format=ndjson&tags=pizza,burger&match=any")
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(body), "\n"), 2)
+
+	resp, body = export("?format=csv&sort=name")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "vendors.csv")
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "_id,cover,type,name,location,phone_numbers,websites,social_networks,media,tags,categories,longitude,latitude", lines[0])
+	assert.Contains(t, lines[3], ",food,Pizza Place,,,,,,pizza|vegan,,13.4,52.5")
+
+	importResp, err := http.Post(server.URL+"/api/vendor/import?key=_id&dry_run=true", "text/csv", strings.NewReader(body))
+	require.NoError(t, err)
+	defer importResp.Body.Close()
+	var report domain.ImportReport
+	require.NoError(t, json.NewDecoder(importResp.Body).Decode(&report))
+	assert.Equal(t, 3, report.Updated)
+	assert.Zero(t, report.Failed)
+
+	for _, query := range []string{"?format=xml", "?sort=colour", "?type=circus", "?match=some"} {
+		resp, _ = export(query)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+func TestVendorMergeEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	send := func(method, path string, payload interface{}, target interface{}) *http.Response {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req, _ := http.NewRequest(method, server.URL+path, &body)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if target != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(target))
+		}
+		return resp
+	}
+
+	create := func(vendor domain.CreateVendorRequest) domain.CreateVendorResponse {
+		t.Helper()
+		var created domain.CreateVendorResponse
+		resp := send(http.MethodPost, "/api/vendor/", vendor, &created)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		return created
+	}
+
+	pizza := create(domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeFood, PhoneNumbers: []string{"+49 30 123456"}, Tags: []string{"pizza"}})
+	pizzaAgain := create(domain.CreateVendorRequest{Name: "Pizzaplace", Type: domain.VendorTypeFood, PhoneNumbers: []string{"030 123456"}, Tags: []string{"vegan"}, Location: "Main Street 1"})
+	odeon := create(domain.CreateVendorRequest{Name: "Odeon", Type: domain.VendorTypeCinema})
+	create(domain.CreateVendorRequest{Name: "Burger Bar", Type: domain.VendorTypeFood})
+
+	var report domain.DuplicateReport
+	resp := send(http.MethodGet, "/api/vendor/duplicates?type=food", nil, &report)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, report.Candidates, 1)
+	assert.Equal(t, pizza.ID, report.Candidates[0].Vendors[0].ID)
+	assert.Equal(t, pizzaAgain.ID, report.Candidates[0].Vendors[1].ID)
+	assert.True(t, report.Candidates[0].Signals.SharedPhone)
+
+	for _, query := range []string{"?threshold=2", "?threshold=high", "?limit=0.5", "?type=circus"} {
+		resp = send(http.MethodGet, "/api/vendor/duplicates"+query, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	path := "/api/vendor/" + pizza.ID.Hex()
+	for _, ids := range [][]string{{}, {pizza.ID.Hex()}, {odeon.ID.Hex()}, {"65a000000000000000000000"}} {
+		resp = send(http.MethodPost, path+"/merge", map[string][]string{"ids": ids}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, ids)
+	}
+
+	var merged domain.UpdateVendorResponse
+	resp = send(http.MethodPost, path+"/merge", map[string][]string{"ids": {pizzaAgain.ID.Hex()}}, &merged)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, pizza.ID, merged.ID)
+	assert.Equal(t, "Pizza Place", merged.Name)
+	assert.Equal(t, "Main Street 1", merged.Location)
+	assert.Equal(t, []string{"+49 30 123456", "030 123456"}, merged.PhoneNumbers)
+	assert.Equal(t, []string{"pizza", "vegan"}, merged.Tags)
+	assert.Equal(t, []primitive.ObjectID{pizzaAgain.ID}, merged.Aliases)
+
+	var resolved domain.GetVendorResponse
+	resp = send(http.MethodGet, "/api/vendor/"+pizzaAgain.ID.Hex(), nil, &resolved)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, pizza.ID, resolved.ID)
+	assert.Equal(t, path, resp.Header.Get("Content-Location"))
+
+	resp = send(http.MethodPut, "/api/vendor/"+pizzaAgain.ID.Hex(), domain.UpdateVendorRequest{Name: "Pizzaplace", Type: domain.VendorTypeFood}, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "aliases only resolve for reading")
+
+	var history struct {
+		History []domain.VendorHistoryEntry `json:"history"`
+	}
+	send(http.MethodGet, path+"/history", nil, &history)
+	require.Len(t, history.History, 2)
+	assert.Equal(t, domain.HistoryActionMerged, history.History[1].Action)
+
+	send(http.MethodGet, "/api/vendor/"+pizzaAgain.ID.Hex()+"/history", nil, &history)
+	require.Len(t, history.History, 2)
+	assert.Equal(t, domain.HistoryActionDeleted, history.History[1].Action)
+	require.NotNil(t, history.History[1].MergedInto)
+	assert.Equal(t, pizza.ID, *history.History[1].MergedInto)
+
+	send(http.MethodGet, "/api/vendor/duplicates?type=food", nil, &report)
+	assert.Empty(t, report.Candidates)
+
+	var again domain.UpdateVendorResponse
+	resp = send(http.MethodPost, path+"/merge", map[string][]string{"ids": {pizzaAgain.ID.Hex()}}, &again)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "sending a finished merge again changes nothing")
+	assert.Equal(t, merged.Version, again.Version)
+}
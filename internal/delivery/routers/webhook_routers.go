@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"vendors/internal/delivery/handlers"
+	"vendors/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func SetupWebhookRouter(webhookRouter *chi.Mux, webhookService *service.WebhookService) {
+	webhookHandler := handlers.WebhookHandler{
+		Router:         webhookRouter,
+		WebhookService: webhookService,
+	}
+
+	webhookRouter.Get("/", webhookHandler.GetWebhooksHandler)
+	webhookRouter.Post("/", webhookHandler.CreateWebhookHandler)
+	webhookRouter.Get("/{id}", webhookHandler.GetWebhookHandler)
+	webhookRouter.Delete("/{id}", webhookHandler.DeleteWebhookHandler)
+	webhookRouter.Get("/{id}/deliveries", webhookHandler.GetDeliveriesHandler)
+	webhookRouter.Post("/{id}/deliveries/{delivery}/retry", webhookHandler.RetryDeliveryHandler)
+}
@@ -0,0 +1,169 @@
+package routers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook endpoint that checks signatures and fails the first
+// failures requests it gets.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	failures int
+
+	mu     sync.Mutex
+	events []domain.VendorEvent
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(rc.t, err)
+
+	unix, err := strconv.ParseInt(r.Header.Get(domain.WebhookTimestampHeader), 10, 64)
+	require.NoError(rc.t, err)
+	assert.Equal(rc.t, domain.SignWebhook(rc.secret, time.Unix(unix, 0), body), r.Header.Get(domain.WebhookSignatureHeader))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var event domain.VendorEvent
+	require.NoError(rc.t, json.Unmarshal(body, &event))
+	assert.Equal(rc.t, string(event.Type), r.Header.Get(domain.WebhookEventHeader))
+	rc.events = append(rc.events, event)
+}
+
+func (rc *receiver) received() []domain.VendorEvent {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]domain.VendorEvent(nil), rc.events...)
+}
+
+func TestWebhookEndToEnd(t *testing.T) {
+	server := newTestServer(t)
+
+	flaky := &receiver{t: t, secret: "flaky-secret", failures: 1}
+	flakyServer := httptest.NewServer(flaky)
+	defer flakyServer.Close()
+
+	down := &receiver{t: t, secret: "down-secret", failures: 3}
+	downServer := httptest.NewServer(down)
+	defer downServer.Close()
+
+	createWebhook := func(request domain.CreateWebhookRequest) domain.Webhook {
+		body, _ := json.Marshal(request)
+		resp, err := http.Post(server.URL+"/api/webhook/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var webhook domain.Webhook
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&webhook))
+		assert.Equal(t, request.Secret, webhook.Secret)
+		return webhook
+	}
+
+	deliveries := func(webhook domain.Webhook, status string) []domain.WebhookDelivery {
+		resp, err := http.Get(server.URL + "/api/webhook/" + webhook.ID.Hex() + "/deliveries?status=" + status)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var payload struct {
+			Deliveries []domain.WebhookDelivery `json:"deliveries"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		return payload.Deliveries
+	}
+
+	flakyHook := createWebhook(domain.CreateWebhookRequest{URL: flakyServer.URL, Secret: flaky.secret})
+	downHook := createWebhook(domain.CreateWebhookRequest{URL: downServer.URL, Secret: down.secret, Events: []domain.VendorEventType{domain.VendorEventCreated}})
+
+	body, _ := json.Marshal(domain.CreateVendorRequest{Name: "Pizza Place", Type: "food"})
+	resp, err := http.Post(server.URL+"/api/vendor/", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	var created domain.CreateVendorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/vendor/"+created.ID.Hex(), nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The flaky receiver gets both events, the first after a retry.
+	require.Eventually(t, func() bool { return len(flaky.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, created.ID, flaky.received()[0].VendorID)
+
+	// The receiver has an event before its delivery is stored as succeeded.
+	var log []domain.WebhookDelivery
+	require.Eventually(t, func() bool {
+		log = deliveries(flakyHook, "succeeded")
+		return len(log) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	attempts := len(log[0].Attempts) + len(log[1].Attempts)
+	assert.Equal(t, 3, attempts)
+
+	// The receiver that is down only subscribed to creations, which end up
+	// in its dead letters and go through once retried.
+	var dead []domain.WebhookDelivery
+	require.Eventually(t, func() bool {
+		dead = deliveries(downHook, "dead")
+		return len(dead) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, dead[0].Attempts, 3)
+	assert.Equal(t, http.StatusServiceUnavailable, dead[0].Attempts[0].StatusCode)
+	assert.Equal(t, domain.VendorEventCreated, dead[0].Event.Type)
+
+	resp, err = http.Post(server.URL+"/api/webhook/"+downHook.ID.Hex()+"/deliveries/"+dead[0].ID.Hex()+"/retry", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Eventually(t, func() bool { return len(down.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, deliveries(downHook, "dead"))
+
+	resp, err = http.Get(server.URL + "/api/webhook/")
+	require.NoError(t, err)
+	var listed struct {
+		Webhooks []domain.Webhook `json:"webhooks"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+	resp.Body.Close()
+	require.Len(t, listed.Webhooks, 2)
+	assert.Empty(t, listed.Webhooks[0].Secret)
+
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/api/webhook/"+downHook.ID.Hex(), nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/webhook/" + downHook.ID.Hex() + "/deliveries")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	body, _ = json.Marshal(domain.CreateWebhookRequest{URL: "ftp://example.com"})
+	resp, err = http.Post(server.URL+"/api/webhook/", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
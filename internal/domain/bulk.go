@@ -0,0 +1,98 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MaxBulkItems caps the number of vendors a single bulk request may carry.
+const MaxBulkItems = 500
+
+type BulkItemStatus string
+
+const (
+	BulkItemOK      BulkItemStatus = "ok"
+	BulkItemFailed  BulkItemStatus = "failed"
+	BulkItemSkipped BulkItemStatus = "skipped"
+)
+
+// BulkItemResult reports what happened to one item of a bulk request. Index
+// is the item's position in the request. Items are skipped when an ordered
+// request stopped at an earlier failure.
+type BulkItemResult struct {
+	Index   int                `json:"index"`
+	ID      primitive.ObjectID `json:"_id,omitempty"`
+	Version int64              `json:"version,omitempty"`
+	Status  BulkItemStatus     `json:"status"`
+	Error   string             `json:"error,omitempty"`
+	Err     error              `json:"-"`
+}
+
+func (r *BulkItemResult) Fail(err error) {
+	r.Status = BulkItemFailed
+	r.Version = 0
+	r.Err = err
+}
+
+func (r *BulkItemResult) Skip() {
+	r.Status = BulkItemSkipped
+	r.Version = 0
+}
+
+// SkipAfterFailure marks every item after the first failed one as skipped,
+// which is what an ordered bulk request reports.
+func SkipAfterFailure(results []BulkItemResult) {
+	failed := false
+	for i := range results {
+		if failed {
+			results[i].Skip()
+		}
+		failed = failed || results[i].Status == BulkItemFailed
+	}
+}
+
+// SkipUnsent marks the items that never reached the store as skipped, which
+// is what an ordered bulk request reports for the items after the one it
+// stopped at. Items the store reported on keep their outcome, since a store
+// may only learn of a failure after it wrote the items that follow it.
+func SkipUnsent(results []BulkItemResult) {
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Skip()
+		}
+	}
+}
+
+// BulkUpdate replaces the vendor with the given ID. Version is the version
+// the write is pinned to; zero means the current version.
+type BulkUpdate struct {
+	ID      primitive.ObjectID
+	Vendor  *UpdateVendorRequest
+	Version int64
+}
+
+// BulkDelete moves the vendor with the given ID to the trash. Version works
+// as for BulkUpdate.
+type BulkDelete struct {
+	ID      primitive.ObjectID
+	Version int64
+}
+
+type BulkResult struct {
+	Results   []BulkItemResult `json:"results"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Skipped   int              `json:"skipped"`
+}
+
+func NewBulkResult(results []BulkItemResult) *BulkResult {
+	result := &BulkResult{Results: results}
+	for _, item := range results {
+		switch item.Status {
+		case BulkItemOK:
+			result.Succeeded++
+		case BulkItemFailed:
+			result.Failed++
+		case BulkItemSkipped:
+			result.Skipped++
+		}
+	}
+	return result
+}
@@ -0,0 +1,493 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidDuplicateQuery = errors.New("invalid duplicate query")
+	// ErrInvalidMerge means the vendors of a merge can't be merged, e.g.
+	// because one of them is missing or of another type.
+	ErrInvalidMerge = errors.New("invalid merge")
+)
+
+// PartialMergeError reports a merge that failed after the vendor took on its
+// duplicates and moved to Version, once Deleted of them were in the trash.
+// Sending the same merge again finishes it.
+type PartialMergeError struct {
+	Deleted []primitive.ObjectID
+	Version int64
+	Err     error
+}
+
+func (e *PartialMergeError) Error() string {
+	return fmt.Sprintf("merge stopped after deleting %d vendors: %v", len(e.Deleted), e.Err)
+}
+
+func (e *PartialMergeError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	// DefaultDuplicateThreshold is the score from which a pair of vendors is
+	// reported as a likely duplicate. Equal names alone reach it.
+	DefaultDuplicateThreshold = 0.5
+	DefaultDuplicateLimit     = 50
+	MaxDuplicateLimit         = 500
+
+	// MaxMergeVendors caps how many vendors a single merge folds into one.
+	MaxMergeVendors = 20
+
+	// MaxDuplicateBlock is the number of vendors from which those sharing a
+	// duplicate key aren't compared on its account. Such keys, like a common
+	// word, say little about a pair.
+	MaxDuplicateBlock = 200
+
+	// SameLocationMeters is how close the coordinates of two vendors must be
+	// for them to count as being at the same location.
+	SameLocationMeters = 50.0
+)
+
+// Weights of the signals a duplicate score is made of. They add up to one, so
+// a pair with equal names, a shared phone number and website at the same
+// location scores one.
+const (
+	nameWeight     = 0.5
+	phoneWeight    = 0.2
+	websiteWeight  = 0.15
+	locationWeight = 0.15
+)
+
+const (
+	// minPhoneDigits is the shortest number compared by its trailing digits,
+	// which lets a number with a country code match one with a trunk prefix.
+	minPhoneDigits = 6
+	// locationCell is the side, in degrees, of the grid cells that block
+	// vendors by coordinates; about 110 meters of latitude.
+	locationCell = 0.001
+)
+
+// nameStopWords are left out when names are compared.
+var nameStopWords = map[string]bool{"the": true, "and": true}
+
+// DuplicateQuery asks for the pairs of vendors, of Type or of any type, that
+// score at least Threshold. Only vendors of the same type are paired.
+type DuplicateQuery struct {
+	Type      string
+	Threshold float64
+	Limit     int
+}
+
+func (q *DuplicateQuery) Validate() error {
+	if q.Threshold == 0 {
+		q.Threshold = DefaultDuplicateThreshold
+	}
+	if q.Threshold < 0 || q.Threshold > 1 || math.IsNaN(q.Threshold) {
+		return fmt.Errorf("%w: threshold must be between 0 and 1", ErrInvalidDuplicateQuery)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultDuplicateLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxDuplicateLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidDuplicateQuery, MaxDuplicateLimit)
+	}
+	return nil
+}
+
+// DuplicateSignals are what two vendors have in common.
+type DuplicateSignals struct {
+	NameSimilarity float64 `json:"name_similarity"`
+	SharedPhone    bool    `json:"shared_phone"`
+	SharedWebsite  bool    `json:"shared_website"`
+	SameLocation   bool    `json:"same_location"`
+}
+
+// Score weighs the signals into a number between zero and one.
+func (s DuplicateSignals) Score() float64 {
+	score := nameWeight * s.NameSimilarity
+	if s.SharedPhone {
+		score += phoneWeight
+	}
+	if s.SharedWebsite {
+		score += websiteWeight
+	}
+	if s.SameLocation {
+		score += locationWeight
+	}
+	return math.Round(score*1000) / 1000
+}
+
+// CompareVendors finds what two vendors have in common.
+func CompareVendors(a, b *GetVendorResponse) DuplicateSignals {
+	return DuplicateSignals{
+		NameSimilarity: NameSimilarity(a.Name, b.Name),
+		SharedPhone:    sharePhone(a.PhoneNumbers, b.PhoneNumbers),
+		SharedWebsite:  shareWebsite(a.Websites, b.Websites),
+		SameLocation:   sameLocation(a, b),
+	}
+}
+
+type DuplicateCandidate struct {
+	Vendors [2]*GetVendorResponse `json:"vendors"`
+	Score   float64               `json:"score"`
+	Signals DuplicateSignals      `json:"signals"`
+}
+
+// DuplicateReport lists likely duplicates, best first. Truncated is set when
+// more pairs scored above the threshold than the limit let through.
+type DuplicateReport struct {
+	Candidates []*DuplicateCandidate `json:"candidates"`
+	Truncated  bool                  `json:"truncated"`
+}
+
+// FindDuplicates pairs up the vendors that score at least the query's
+// threshold. Rather than comparing every pair, only vendors of the same type
+// sharing one of their DuplicateKeys are compared.
+func FindDuplicates(vendors []*GetVendorResponse, query DuplicateQuery) *DuplicateReport {
+	blocks := duplicateBlocks(vendors)
+
+	type pair struct{ a, b int }
+	compared := map[pair]bool{}
+	candidates := []*DuplicateCandidate{}
+
+	for _, block := range blocks {
+		for i, a := range block {
+			for _, b := range block[i+1:] {
+				if a == b || compared[pair{a, b}] {
+					continue
+				}
+				compared[pair{a, b}] = true
+
+				signals := CompareVendors(vendors[a], vendors[b])
+				if score := signals.Score(); score >= query.Threshold {
+					candidates = append(candidates, &DuplicateCandidate{
+						Vendors: [2]*GetVendorResponse{vendors[a], vendors[b]},
+						Score:   score,
+						Signals: signals,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return pairBefore(candidates[i].Vendors, candidates[j].Vendors)
+	})
+
+	report := &DuplicateReport{Candidates: candidates}
+	if query.Limit > 0 && len(candidates) > query.Limit {
+		report.Candidates = candidates[:query.Limit]
+		report.Truncated = true
+	}
+	return report
+}
+
+// pairBefore orders pairs of equal score by their IDs, so a report is stable.
+func pairBefore(a, b [2]*GetVendorResponse) bool {
+	for k := range a {
+		if c := strings.Compare(a[k].ID.Hex(), b[k].ID.Hex()); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// DuplicateCandidates keeps the vendors that FindDuplicates would compare
+// with another one, in their order.
+func DuplicateCandidates(vendors []*GetVendorResponse) []*GetVendorResponse {
+	compared := make([]bool, len(vendors))
+	for _, block := range duplicateBlocks(vendors) {
+		for _, i := range block {
+			compared[i] = true
+		}
+	}
+
+	var candidates []*GetVendorResponse
+	for i, vendor := range vendors {
+		if compared[i] {
+			candidates = append(candidates, vendor)
+		}
+	}
+	return candidates
+}
+
+// duplicateBlocks groups the indexes of vendors by type and duplicate key,
+// keeping the groups of at least two and at most MaxDuplicateBlock vendors.
+func duplicateBlocks(vendors []*GetVendorResponse) map[string][]int {
+	blocks := map[string][]int{}
+	for i, vendor := range vendors {
+		for _, key := range DuplicateKeys(vendor) {
+			key = vendor.Type + "\x00" + key
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	for key, block := range blocks {
+		if len(block) < 2 || len(block) > MaxDuplicateBlock {
+			delete(blocks, key)
+		}
+	}
+	return blocks
+}
+
+// DuplicateKeys lists the blocking keys of a vendor, sorted: the words and
+// the start of its name, its phone numbers, its website domains, its
+// location and a grid cell around its coordinates. Only vendors sharing a key
+// are compared when looking for duplicates.
+func DuplicateKeys(vendor *GetVendorResponse) []string {
+	var keys []string
+
+	words := nameWords(vendor.Name)
+	for _, word := range words {
+		if len(word) >= 3 {
+			keys = append(keys, "word:"+word)
+		}
+	}
+	if compact := strings.Join(words, ""); len(compact) >= 4 {
+		keys = append(keys, "prefix:"+compact[:4])
+	}
+
+	for _, phone := range vendor.PhoneNumbers {
+		if digits := phoneDigits(phone); len(digits) >= minPhoneDigits {
+			keys = append(keys, "phone:"+digits[len(digits)-minPhoneDigits:])
+		}
+	}
+	for _, website := range vendor.Websites {
+		if domain := websiteDomain(website); domain != "" {
+			keys = append(keys, "website:"+domain)
+		}
+	}
+	if location := normalizeLocation(vendor.Location); location != "" {
+		keys = append(keys, "location:"+location)
+	}
+	if point := vendor.Coordinates; point != nil && len(point.Coordinates) == 2 {
+		keys = append(keys, fmt.Sprintf("cell:%d:%d",
+			int(math.Floor(point.Lng()/locationCell)),
+			int(math.Floor(point.Lat()/locationCell))))
+	}
+
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// NormalizeVendorName lowercases a name and reduces it to its words, leaving
+// out punctuation and filler words, so that "The Pizza-Place" and "pizza
+// place" compare equal.
+func NormalizeVendorName(name string) string {
+	return strings.Join(nameWords(name), " ")
+}
+
+func nameWords(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+
+	kept := words[:0]
+	for _, word := range words {
+		if !nameStopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// NameSimilarity compares two names by the letter pairs of their normalized
+// forms, ignoring spaces, with the Sørensen–Dice coefficient. It is one for
+// equal names and zero for names without a letter pair in common.
+func NameSimilarity(a, b string) float64 {
+	x := []rune(strings.Join(nameWords(a), ""))
+	y := []rune(strings.Join(nameWords(b), ""))
+
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	if string(x) == string(y) {
+		return 1
+	}
+	if len(x) < 2 || len(y) < 2 {
+		return 0
+	}
+
+	bigrams := map[[2]rune]int{}
+	for i := 0; i < len(x)-1; i++ {
+		bigrams[[2]rune{x[i], x[i+1]}]++
+	}
+
+	shared := 0
+	for i := 0; i < len(y)-1; i++ {
+		bigram := [2]rune{y[i], y[i+1]}
+		if bigrams[bigram] > 0 {
+			bigrams[bigram]--
+			shared++
+		}
+	}
+
+	return float64(2*shared) / float64(len(x)+len(y)-2)
+}
+
+// phoneDigits keeps the digits of a phone number, without leading zeros.
+func phoneDigits(phone string) string {
+	digits := strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, phone)
+	return strings.TrimLeft(digits, "0")
+}
+
+// samePhone compares numbers by their digits. Long enough numbers also match
+// when one ends in the other, as "+49 30 123456" and "030 123456" do.
+func samePhone(a, b string) bool {
+	x, y := phoneDigits(a), phoneDigits(b)
+	if x == "" || y == "" {
+		return false
+	}
+	if len(x) < len(y) {
+		x, y = y, x
+	}
+	if len(y) < minPhoneDigits {
+		return x == y
+	}
+	return strings.HasSuffix(x, y)
+}
+
+func sharePhone(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if samePhone(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// websiteDomain returns the host of a website, lowercased and without a
+// leading www, or an empty string when it has none.
+func websiteDomain(website string) string {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return ""
+	}
+	if !strings.Contains(website, "://") {
+		website = "http://" + website
+	}
+
+	u, err := url.Parse(website)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func shareWebsite(a, b []string) bool {
+	domains := map[string]bool{}
+	for _, website := range a {
+		if domain := websiteDomain(website); domain != "" {
+			domains[domain] = true
+		}
+	}
+	for _, website := range b {
+		if domains[websiteDomain(website)] {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeLocation(location string) string {
+	return strings.Join(nameWords(location), " ")
+}
+
+// sameLocation holds for vendors whose coordinates are close, or, when either
+// has none, whose locations read the same.
+func sameLocation(a, b *GetVendorResponse) bool {
+	if a.Coordinates != nil && b.Coordinates != nil {
+		return DistanceMeters(a.Coordinates, b.Coordinates) <= SameLocationMeters
+	}
+	location := normalizeLocation(a.Location)
+	return location != "" && location == normalizeLocation(b.Location)
+}
+
+// MergeRequest names the vendors to fold into another one.
+type MergeRequest struct {
+	IDs []primitive.ObjectID `json:"ids"`
+}
+
+// Validate checks the request for a merge into the vendor with the given ID.
+func (r *MergeRequest) Validate(id primitive.ObjectID) error {
+	if len(r.IDs) == 0 {
+		return fmt.Errorf("%w: no vendors to merge", ErrInvalidMerge)
+	}
+	if len(r.IDs) > MaxMergeVendors {
+		return fmt.Errorf("%w: at most %d vendors can be merged at once", ErrInvalidMerge, MaxMergeVendors)
+	}
+
+	seen := map[primitive.ObjectID]bool{}
+	for _, duplicate := range r.IDs {
+		if duplicate == id {
+			return fmt.Errorf("%w: vendor %s can't be merged into itself", ErrInvalidMerge, id.Hex())
+		}
+		if seen[duplicate] {
+			return fmt.Errorf("%w: vendor %s is listed twice", ErrInvalidMerge, duplicate.Hex())
+		}
+		seen[duplicate] = true
+	}
+	return nil
+}
+
+// MergeVendors folds duplicates into vendor. The vendor keeps its own name,
+// type and other single values, and takes those it lacks from the first
+// duplicate that has them. Lists are combined, the vendor's values first,
+// without repeating a value.
+func MergeVendors(vendor CommonVendorRequest, duplicates ...CommonVendorRequest) CommonVendorRequest {
+	merged := vendor
+	for _, duplicate := range duplicates {
+		if merged.Cover == "" {
+			merged.Cover = duplicate.Cover
+		}
+		if merged.Location == "" {
+			merged.Location = duplicate.Location
+		}
+		if merged.Coordinates == nil {
+			merged.Coordinates = duplicate.Coordinates
+		}
+
+		merged.PhoneNumbers = union(merged.PhoneNumbers, duplicate.PhoneNumbers)
+		merged.Websites = union(merged.Websites, duplicate.Websites)
+		merged.SocialNetworks = union(merged.SocialNetworks, duplicate.SocialNetworks)
+		merged.Media = union(merged.Media, duplicate.Media)
+		merged.Tags = union(merged.Tags, duplicate.Tags)
+		merged.Categories = union(merged.Categories, duplicate.Categories)
+	}
+	return merged
+}
+
+func union(values, more []string) []string {
+	if len(more) == 0 {
+		return values
+	}
+
+	seen := make(map[string]bool, len(values)+len(more))
+	combined := make([]string, 0, len(values)+len(more))
+	for _, value := range append(append([]string(nil), values...), more...) {
+		if !seen[value] {
+			seen[value] = true
+			combined = append(combined, value)
+		}
+	}
+	return combined
+}
@@ -0,0 +1,182 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDuplicateQueryValidate(t *testing.T) {
+	query := domain.DuplicateQuery{}
+	require.NoError(t, query.Validate())
+	assert.Equal(t, domain.DefaultDuplicateThreshold, query.Threshold)
+	assert.Equal(t, domain.DefaultDuplicateLimit, query.Limit)
+
+	for _, query := range []domain.DuplicateQuery{
+		{Threshold: -0.1},
+		{Threshold: 1.5},
+		{Limit: -1},
+		{Limit: domain.MaxDuplicateLimit + 1},
+	} {
+		assert.ErrorIs(t, query.Validate(), domain.ErrInvalidDuplicateQuery)
+	}
+}
+
+func TestNormalizeVendorName(t *testing.T) {
+	assert.Equal(t, "pizza place", domain.NormalizeVendorName("The Pizza-Place!"))
+	assert.Equal(t, "fish chips", domain.NormalizeVendorName("Fish & Chips"))
+	assert.Equal(t, "café 21", domain.NormalizeVendorName("  CAFÉ   21 "))
+}
+
+func TestNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, domain.NameSimilarity("The Pizza Place", "pizza-place"))
+	assert.Equal(t, 0.0, domain.NameSimilarity("Odeon", "Burger Bar"))
+	assert.Equal(t, 0.0, domain.NameSimilarity("", "Odeon"))
+	assert.InDelta(t, 0.75, domain.NameSimilarity("Pizza Place", "Pizza Place Berlin"), 0.001)
+
+	similarity := domain.NameSimilarity("Pizza Place", "Piza Place")
+	assert.Greater(t, similarity, 0.8)
+	assert.Less(t, similarity, 1.0)
+}
+
+func TestCompareVendors(t *testing.T) {
+	a := &domain.GetVendorResponse{
+		Name:         "Pizza Place",
+		PhoneNumbers: []string{"+49 30 123456"},
+		Websites:     []string{"https://www.pizza-place.de/menu"},
+		Coordinates:  domain.NewGeoPoint(13.4050, 52.5200),
+	}
+	b := &domain.GetVendorResponse{
+		Name:         "Pizza Place",
+		PhoneNumbers: []string{"030 123456"},
+		Websites:     []string{"pizza-place.de"},
+		Coordinates:  domain.NewGeoPoint(13.4051, 52.5201),
+	}
+
+	signals := domain.CompareVendors(a, b)
+	assert.Equal(t, domain.DuplicateSignals{NameSimilarity: 1, SharedPhone: true, SharedWebsite: true, SameLocation: true}, signals)
+	assert.Equal(t, 1.0, signals.Score())
+
+	b.PhoneNumbers = []string{"030 654321"}
+	b.Websites = []string{"https://pizza-place.com"}
+	b.Coordinates = domain.NewGeoPoint(13.5, 52.5)
+	signals = domain.CompareVendors(a, b)
+	assert.Equal(t, domain.DuplicateSignals{NameSimilarity: 1}, signals)
+	assert.Equal(t, 0.5, signals.Score())
+
+	a.Coordinates, b.Coordinates = nil, nil
+	a.Location, b.Location = "Main Street 1", "main street, 1"
+	assert.True(t, domain.CompareVendors(a, b).SameLocation)
+
+	a.PhoneNumbers, b.PhoneNumbers = []string{"112"}, []string{"0112"}
+	assert.True(t, domain.CompareVendors(a, b).SharedPhone)
+	b.PhoneNumbers = []string{"49112"}
+	assert.False(t, domain.CompareVendors(a, b).SharedPhone, "short numbers must match in full")
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	keys := domain.DuplicateKeys(&domain.GetVendorResponse{
+		Name:         "The Pizza Pizza",
+		PhoneNumbers: []string{"+49 30 123456", "030 123456"},
+		Websites:     []string{"https://www.pizza.de/menu"},
+	})
+	assert.Equal(t, []string{"phone:123456", "prefix:pizz", "website:pizza.de", "word:pizza"}, keys)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	vendor := func(name, vendorType string, phones ...string) *domain.GetVendorResponse {
+		return &domain.GetVendorResponse{ID: primitive.NewObjectID(), Name: name, Type: vendorType, PhoneNumbers: phones}
+	}
+
+	pizza := vendor("Pizza Place", domain.VendorTypeFood, "+49 30 123456")
+	pizzaAgain := vendor("Pizzaplace", domain.VendorTypeFood, "030 123456")
+	pizzaCinema := vendor("Pizza Place", domain.VendorTypeCinema)
+	burger := vendor("Burger Bar", domain.VendorTypeFood)
+	burgerAgain := vendor("Burger Bar", domain.VendorTypeFood)
+	odeon := vendor("Odeon", domain.VendorTypeCinema, "030 123456")
+
+	vendors := []*domain.GetVendorResponse{pizza, pizzaAgain, pizzaCinema, burger, burgerAgain, odeon}
+
+	report := domain.FindDuplicates(vendors, domain.DuplicateQuery{Threshold: domain.DefaultDuplicateThreshold})
+	require.Len(t, report.Candidates, 2, "vendors of different types are never paired")
+	assert.False(t, report.Truncated)
+
+	assert.Equal(t, [2]*domain.GetVendorResponse{pizza, pizzaAgain}, report.Candidates[0].Vendors)
+	assert.Equal(t, 0.7, report.Candidates[0].Score)
+	assert.True(t, report.Candidates[0].Signals.SharedPhone)
+	assert.Equal(t, [2]*domain.GetVendorResponse{burger, burgerAgain}, report.Candidates[1].Vendors)
+	assert.Equal(t, 0.5, report.Candidates[1].Score)
+
+	assert.Equal(t, []*domain.GetVendorResponse{pizza, pizzaAgain, burger, burgerAgain}, domain.DuplicateCandidates(vendors))
+
+	report = domain.FindDuplicates(vendors, domain.DuplicateQuery{Threshold: 0.6, Limit: 1})
+	require.Len(t, report.Candidates, 1)
+	assert.False(t, report.Truncated)
+
+	report = domain.FindDuplicates(vendors, domain.DuplicateQuery{Threshold: 0.5, Limit: 1})
+	require.Len(t, report.Candidates, 1)
+	assert.True(t, report.Truncated)
+}
+
+func TestMergeRequestValidate(t *testing.T) {
+	id := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	assert.NoError(t, (&domain.MergeRequest{IDs: []primitive.ObjectID{other}}).Validate(id))
+
+	tooMany := make([]primitive.ObjectID, domain.MaxMergeVendors+1)
+	for i := range tooMany {
+		tooMany[i] = primitive.NewObjectID()
+	}
+
+	for _, request := range []domain.MergeRequest{
+		{},
+		{IDs: tooMany},
+		{IDs: []primitive.ObjectID{id}},
+		{IDs: []primitive.ObjectID{other, other}},
+	} {
+		assert.ErrorIs(t, request.Validate(id), domain.ErrInvalidMerge)
+	}
+}
+
+func TestMergeVendors(t *testing.T) {
+	vendor := domain.CommonVendorRequest{
+		Type:         domain.VendorTypeFood,
+		Name:         "Pizza Place",
+		PhoneNumbers: []string{"030 123456"},
+		Tags:         []string{"pizza"},
+	}
+	first := domain.CommonVendorRequest{
+		Type:         domain.VendorTypeFood,
+		Name:         "Pizzaplace",
+		Cover:        "first.jpg",
+		Location:     "Main Street 1",
+		PhoneNumbers: []string{"030 123456", "030 654321"},
+		Tags:         []string{"vegan", "pizza"},
+	}
+	second := domain.CommonVendorRequest{
+		Type:        domain.VendorTypeFood,
+		Name:        "Pizza Place Berlin",
+		Cover:       "second.jpg",
+		Websites:    []string{"pizza-place.de"},
+		Tags:        []string{"delivery"},
+		Coordinates: domain.NewGeoPoint(13.4, 52.5),
+	}
+
+	merged := domain.MergeVendors(vendor, first, second)
+
+	assert.Equal(t, domain.CommonVendorRequest{
+		Type:         domain.VendorTypeFood,
+		Name:         "Pizza Place",
+		Cover:        "first.jpg",
+		Location:     "Main Street 1",
+		PhoneNumbers: []string{"030 123456", "030 654321"},
+		Websites:     []string{"pizza-place.de"},
+		Tags:         []string{"pizza", "vegan", "delivery"},
+		Coordinates:  domain.NewGeoPoint(13.4, 52.5),
+	}, merged)
+	assert.Equal(t, []string{"pizza"}, vendor.Tags, "the vendor's lists must not be modified")
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidEventID = errors.New("invalid event id")
+	// ErrEventsExpired means the events after a given id are no longer kept,
+	// so a stream can't be resumed from it without missing changes.
+	ErrEventsExpired = errors.New("events expired")
+)
+
+type VendorEventType string
+
+const (
+	VendorEventCreated VendorEventType = "created"
+	VendorEventUpdated VendorEventType = "updated"
+	VendorEventDeleted VendorEventType = "deleted"
+)
+
+// VendorEvent announces a change to a vendor. ID is opaque and is what a
+// client hands back to resume a stream after it. Vendor holds the editable
+// fields as they were right after the change.
+type VendorEvent struct {
+	ID        string              `json:"id"`
+	Type      VendorEventType     `json:"type"`
+	VendorID  primitive.ObjectID  `json:"vendor_id"`
+	Revision  int64               `json:"revision"`
+	Timestamp time.Time           `json:"timestamp"`
+	Vendor    CommonVendorRequest `json:"vendor"`
+}
+
+// EventTypeOf maps a history action to the event announcing it. A restored
+// vendor shows up again in listings, so it is announced as created.
+func EventTypeOf(action HistoryAction) VendorEventType {
+	switch action {
+	case HistoryActionCreated, HistoryActionRestored:
+		return VendorEventCreated
+	case HistoryActionDeleted:
+		return VendorEventDeleted
+	default:
+		return VendorEventUpdated
+	}
+}
+
+// MatchesEvent reports whether the vendor an event carries, as the change
+// left it, satisfies the filter.
+func (f *VendorFilter) MatchesEvent(event *VendorEvent) bool {
+	vendor := event.Vendor
+	return f.Matches(&GetVendorResponse{
+		ID:             event.VendorID,
+		Cover:          vendor.Cover,
+		Type:           vendor.Type,
+		Name:           vendor.Name,
+		Location:       vendor.Location,
+		PhoneNumbers:   vendor.PhoneNumbers,
+		Websites:       vendor.Websites,
+		SocialNetworks: vendor.SocialNetworks,
+		Media:          vendor.Media,
+		Tags:           vendor.Tags,
+		Categories:     vendor.Categories,
+		Coordinates:    vendor.Coordinates,
+	})
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTypeOf(t *testing.T) {
+	assert.Equal(t, domain.VendorEventCreated, domain.EventTypeOf(domain.HistoryActionCreated))
+	assert.Equal(t, domain.VendorEventCreated, domain.EventTypeOf(domain.HistoryActionRestored))
+	assert.Equal(t, domain.VendorEventUpdated, domain.EventTypeOf(domain.HistoryActionReverted))
+	assert.Equal(t, domain.VendorEventDeleted, domain.EventTypeOf(domain.HistoryActionDeleted))
+}
+
+func TestVendorFilterMatchesEvent(t *testing.T) {
+	event := &domain.VendorEvent{Vendor: domain.CommonVendorRequest{
+		Type:       "food",
+		Name:       "Pizza Place",
+		Cover:      "cover.jpg",
+		Tags:       []string{"pizza", "vegan"},
+		Categories: []string{"restaurant"},
+	}}
+
+	for _, filter := range []domain.VendorFilter{
+		{},
+		{Type: "food", Tags: domain.ValueMatch{Values: []string{"pizza"}}},
+		{Categories: domain.ValueMatch{Values: []string{"restaurant"}}},
+		{Query: "place"},
+		{Presence: map[string]bool{"cover": true, "websites": false}},
+	} {
+		assert.True(t, filter.MatchesEvent(event), "%+v", filter)
+	}
+
+	for _, filter := range []domain.VendorFilter{
+		{Type: "cinema"},
+		{Tags: domain.ValueMatch{Exclude: []string{"vegan"}}},
+		{Categories: domain.ValueMatch{Exclude: []string{"restaurant"}}},
+		{Query: "burger"},
+		{Presence: map[string]bool{"websites": true}},
+	} {
+		assert.False(t, filter.MatchesEvent(event), "%+v", filter)
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidExport = errors.New("invalid export")
+
+type ExportFormat string
+
+const (
+	ExportJSON   ExportFormat = "json"
+	ExportNDJSON ExportFormat = "ndjson"
+	ExportCSV    ExportFormat = "csv"
+)
+
+// ExportOptions controls an export. Format defaults to JSON. In CSV, list
+// fields are joined by ListDelimiter like an import expects them, so an
+// exported file can be imported again.
+type ExportOptions struct {
+	Format        ExportFormat
+	ListDelimiter string
+}
+
+func (o *ExportOptions) Validate() error {
+	switch o.Format {
+	case "":
+		o.Format = ExportJSON
+	case ExportJSON, ExportNDJSON, ExportCSV:
+	default:
+		return fmt.Errorf("%w: format must be %s, %s or %s", ErrInvalidExport, ExportJSON, ExportNDJSON, ExportCSV)
+	}
+	if o.ListDelimiter == "" {
+		o.ListDelimiter = DefaultListDelimiter
+	}
+	return nil
+}
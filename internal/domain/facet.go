@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidFacet = errors.New("invalid facet")
+
+// Fields vendors can be counted by.
+const (
+	FacetTags       = "tags"
+	FacetCategories = "categories"
+	FacetType       = "type"
+)
+
+// FacetFields is the whitelist of fields a facet may name.
+var FacetFields = []string{FacetTags, FacetCategories, FacetType}
+
+// MaxFacetValues bounds the number of values returned per facet. The most
+// common values are kept.
+const MaxFacetValues = 100
+
+// AllFacetValues is the facet limit that keeps every value. Counts that are
+// merged with others must be complete, since a value cut from one part may
+// still rank among the most common once the parts are added up.
+const AllFacetValues = -1
+
+type FacetCount struct {
+	Value string `json:"value" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+// Facets holds the value counts of each requested facet field, most common
+// value first.
+type Facets map[string][]FacetCount
+
+// ParseFacets reads a comma separated list of facet fields. The empty string
+// requests no facets.
+func ParseFacets(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(FacetFields, field) || slices.Contains(fields, field) {
+			return nil, ErrInvalidFacet
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// CountFacets counts the values of each field over vendors and keeps the
+// limit most common, MaxFacetValues if limit is zero.
+func CountFacets(fields []string, vendors []*GetVendorResponse, limit int) Facets {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	facets := make(Facets, len(fields))
+	for _, field := range fields {
+		counts := map[string]int{}
+		for _, vendor := range vendors {
+			for _, value := range facetValues(vendor, field) {
+				counts[value]++
+			}
+		}
+		facets[field] = rankFacet(counts, limit)
+	}
+	return facets
+}
+
+// MergeFacets adds up facets counted over disjoint sets of vendors and keeps
+// the limit most common values like CountFacets. The parts must have been
+// counted with AllFacetValues for the totals to be exact.
+func MergeFacets(limit int, parts ...Facets) Facets {
+	var fields []string
+	totals := map[string]map[string]int{}
+	for _, part := range parts {
+		for field, counts := range part {
+			if _, ok := totals[field]; !ok {
+				fields = append(fields, field)
+				totals[field] = map[string]int{}
+			}
+			for _, count := range counts {
+				totals[field][count.Value] += count.Count
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	facets := make(Facets, len(fields))
+	for _, field := range fields {
+		facets[field] = rankFacet(totals[field], limit)
+	}
+	return facets
+}
+
+// rankFacet orders counts by count and then by value, and keeps the first
+// limit.
+func rankFacet(counts map[string]int, limit int) []FacetCount {
+	ranked := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		ranked = append(ranked, FacetCount{Value: value, Count: count})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Value < ranked[j].Value
+	})
+
+	if limit = FacetLimit(limit); limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// FacetLimit resolves a facet limit, where zero stands for MaxFacetValues.
+func FacetLimit(limit int) int {
+	if limit == 0 {
+		return MaxFacetValues
+	}
+	return limit
+}
+
+func facetValues(vendor *GetVendorResponse, field string) []string {
+	switch field {
+	case FacetTags:
+		return vendor.Tags
+	case FacetCategories:
+		return vendor.Categories
+	case FacetType:
+		return []string{vendor.Type}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFacets(t *testing.T) {
+	fields, err := domain.ParseFacets("tags, type")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tags", "type"}, fields)
+
+	fields, err = domain.ParseFacets("")
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	_, err = domain.ParseFacets("name")
+	assert.ErrorIs(t, err, domain.ErrInvalidFacet)
+
+	_, err = domain.ParseFacets("tags,tags")
+	assert.ErrorIs(t, err, domain.ErrInvalidFacet)
+}
+
+func TestCountFacets(t *testing.T) {
+	food := []*domain.GetVendorResponse{
+		{Type: domain.VendorTypeFood, Tags: []string{"pizza", "vegan"}},
+		{Type: domain.VendorTypeFood, Tags: []string{"vegan"}},
+	}
+	cinemas := []*domain.GetVendorResponse{
+		{Type: domain.VendorTypeCinema, Tags: []string{"imax", "pizza"}},
+	}
+
+	fields := []string{domain.FacetTags, domain.FacetType}
+	facets := domain.MergeFacets(0, domain.CountFacets(fields, food, domain.AllFacetValues), domain.CountFacets(fields, cinemas, domain.AllFacetValues))
+
+	assert.Equal(t, domain.Facets{
+		domain.FacetTags: {{Value: "pizza", Count: 2}, {Value: "vegan", Count: 2}, {Value: "imax", Count: 1}},
+		domain.FacetType: {{Value: domain.VendorTypeFood, Count: 2}, {Value: domain.VendorTypeCinema, Count: 1}},
+	}, facets)
+
+	assert.Equal(t, []domain.FacetCount{{Value: "vegan", Count: 2}}, domain.CountFacets(fields, food, 1)[domain.FacetTags])
+
+	assert.Nil(t, domain.CountFacets(nil, food, 0))
+	assert.Nil(t, domain.MergeFacets(0, nil, nil))
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// PresenceFields lists the vendor fields a filter can require to be set or
+// unset. A string is set when it isn't empty, a list when it has an element.
+var PresenceFields = []string{"cover", "location", "phone_numbers", "websites", "social_networks", "media", "coordinates"}
+
+// MatchMode says how the values of a ValueMatch are matched against a list
+// field.
+type MatchMode string
+
+const (
+	// MatchAll requires every value, MatchAny at least one and MatchNone none
+	// of them.
+	MatchAll  MatchMode = "all"
+	MatchAny  MatchMode = "any"
+	MatchNone MatchMode = "none"
+)
+
+// ParseMatchMode reads a match mode, defaulting to MatchAll.
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch mode := MatchMode(s); mode {
+	case "":
+		return MatchAll, nil
+	case MatchAll, MatchAny, MatchNone:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%w: match must be all, any or none", ErrInvalidFilter)
+}
+
+// ValueMatch selects vendors by the values of a list field such as tags.
+// Values are matched according to Mode; a vendor having any of Exclude never
+// matches. The zero value matches every vendor.
+type ValueMatch struct {
+	Values  []string
+	Mode    MatchMode
+	Exclude []string
+}
+
+// Empty reports whether m restricts nothing.
+func (m ValueMatch) Empty() bool {
+	return len(m.Values) == 0 && len(m.Exclude) == 0
+}
+
+// Matches reports whether a list field holding values satisfies m.
+func (m ValueMatch) Matches(values []string) bool {
+	if containsAny(values, m.Exclude) {
+		return false
+	}
+	if len(m.Values) == 0 {
+		return true
+	}
+
+	switch m.Mode {
+	case MatchAny:
+		return containsAny(values, m.Values)
+	case MatchNone:
+		return !containsAny(values, m.Values)
+	default:
+		return !slices.ContainsFunc(m.Values, func(value string) bool {
+			return !slices.Contains(values, value)
+		})
+	}
+}
+
+func containsAny(values, wanted []string) bool {
+	return slices.ContainsFunc(wanted, func(value string) bool {
+		return slices.Contains(values, value)
+	})
+}
+
+// VendorFilter selects live vendors by several criteria at once; a vendor
+// must satisfy all of them. Zero values don't restrict anything.
+type VendorFilter struct {
+	Tags       ValueMatch
+	Categories ValueMatch
+	Type       string
+	// Query is matched like a text search.
+	Query string
+	// Presence maps fields to whether they must be set or unset.
+	Presence map[string]bool
+}
+
+func (f *VendorFilter) Validate() error {
+	for _, mode := range []MatchMode{f.Tags.Mode, f.Categories.Mode} {
+		if _, err := ParseMatchMode(string(mode)); err != nil {
+			return err
+		}
+	}
+	if utf8.RuneCountInString(f.Query) > MaxSearchQueryLength {
+		return fmt.Errorf("%w: query must be at most %d characters", ErrInvalidFilter, MaxSearchQueryLength)
+	}
+	for field := range f.Presence {
+		if !slices.Contains(PresenceFields, field) {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, field)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether vendor satisfies every criterion of the filter. The
+// query matches when any of its words occurs in a searchable field.
+func (f *VendorFilter) Matches(vendor *GetVendorResponse) bool {
+	if !f.Tags.Matches(vendor.Tags) || !f.Categories.Matches(vendor.Categories) {
+		return false
+	}
+	if f.Type != "" && vendor.Type != f.Type {
+		return false
+	}
+	if terms := SearchTerms(f.Query); len(terms) > 0 && TextScore(vendor, terms) == 0 {
+		return false
+	}
+	for field, present := range f.Presence {
+		if HasField(vendor, field) != present {
+			return false
+		}
+	}
+	return true
+}
+
+// HasField reports whether the named presence field of vendor is set.
+func HasField(vendor *GetVendorResponse, field string) bool {
+	switch field {
+	case "cover":
+		return vendor.Cover != ""
+	case "location":
+		return vendor.Location != ""
+	case "phone_numbers":
+		return len(vendor.PhoneNumbers) > 0
+	case "websites":
+		return len(vendor.Websites) > 0
+	case "social_networks":
+		return len(vendor.SocialNetworks) > 0
+	case "media":
+		return len(vendor.Media) > 0
+	case "coordinates":
+		return vendor.Coordinates != nil
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMatchMode(t *testing.T) {
+	mode, err := domain.ParseMatchMode("")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchAll, mode)
+
+	mode, err = domain.ParseMatchMode("none")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchNone, mode)
+
+	_, err = domain.ParseMatchMode("some")
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+}
+
+func TestValueMatchMatches(t *testing.T) {
+	tags := []string{"vegan", "pizza"}
+
+	tests := []struct {
+		name  string
+		match domain.ValueMatch
+		want  bool
+	}{
+		{name: "Empty", match: domain.ValueMatch{}, want: true},
+		{name: "All", match: domain.ValueMatch{Values: []string{"vegan", "pizza"}, Mode: domain.MatchAll}, want: true},
+		{name: "All missing one", match: domain.ValueMatch{Values: []string{"vegan", "sushi"}, Mode: domain.MatchAll}, want: false},
+		{name: "Any", match: domain.ValueMatch{Values: []string{"vegan", "sushi"}, Mode: domain.MatchAny}, want: true},
+		{name: "Any of none", match: domain.ValueMatch{Values: []string{"sushi"}, Mode: domain.MatchAny}, want: false},
+		{name: "None", match: domain.ValueMatch{Values: []string{"sushi"}, Mode: domain.MatchNone}, want: true},
+		{name: "None having one", match: domain.ValueMatch{Values: []string{"pizza"}, Mode: domain.MatchNone}, want: false},
+		{name: "Excluded", match: domain.ValueMatch{Values: []string{"vegan"}, Exclude: []string{"pizza"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.match.Matches(tags))
+		})
+	}
+}
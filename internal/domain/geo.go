@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+const (
+	GeoJSONPoint = "Point"
+
+	// EarthRadiusMeters is the mean radius MongoDB uses for spherical
+	// geometry, so distances computed here line up with $geoNear.
+	EarthRadiusMeters = 6378100.0
+
+	// MaxNearRadiusMeters caps how far a "near me" query may reach.
+	MaxNearRadiusMeters = 50000.0
+)
+
+// GeoPoint is a GeoJSON point. Coordinates are [longitude, latitude], the
+// order MongoDB's 2dsphere index expects.
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+func NewGeoPoint(lng, lat float64) *GeoPoint {
+	return &GeoPoint{Type: GeoJSONPoint, Coordinates: []float64{lng, lat}}
+}
+
+func (p *GeoPoint) Lng() float64 { return p.Coordinates[0] }
+
+func (p *GeoPoint) Lat() float64 { return p.Coordinates[1] }
+
+// Validate accepts a nil point, since coordinates are optional on a vendor.
+func (p *GeoPoint) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Type != GeoJSONPoint || len(p.Coordinates) != 2 {
+		return ErrInvalidCoordinates
+	}
+	return ValidateLngLat(p.Lng(), p.Lat())
+}
+
+func ValidateLngLat(lng, lat float64) error {
+	if math.IsNaN(lng) || math.IsNaN(lat) || lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// DistanceMeters returns the great-circle distance between two points.
+func DistanceMeters(a, b *GeoPoint) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	lat1, lat2 := toRadians(a.Lat()), toRadians(b.Lat())
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Lng() - a.Lng())
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+type NearbyVendorResponse struct {
+	GetVendorResponse `bson:",inline"`
+	Distance          float64 `json:"distance" bson:"distance"`
+}
+
+type NearbyVendorList struct {
+	Vendors    []*NearbyVendorResponse
+	NextCursor string
+}
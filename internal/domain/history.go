@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+type HistoryAction string
+
+const (
+	HistoryActionCreated  HistoryAction = "created"
+	HistoryActionUpdated  HistoryAction = "updated"
+	HistoryActionDeleted  HistoryAction = "deleted"
+	HistoryActionRestored HistoryAction = "restored"
+	HistoryActionReverted HistoryAction = "reverted"
+	HistoryActionMerged   HistoryAction = "merged"
+)
+
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	Old   interface{} `json:"old" bson:"old"`
+	New   interface{} `json:"new" bson:"new"`
+}
+
+// VendorHistoryEntry records one change to a vendor. Revision is the vendor
+// version the change produced and Snapshot holds the editable fields as they
+// were right after it, which is what a revert goes back to. A vendor deleted
+// by a merge names the vendor it was merged into in MergedInto.
+type VendorHistoryEntry struct {
+	ID         primitive.ObjectID  `json:"_id" bson:"_id,omitempty"`
+	VendorID   primitive.ObjectID  `json:"vendor_id" bson:"vendor_id"`
+	Revision   int64               `json:"revision" bson:"revision"`
+	Action     HistoryAction       `json:"action" bson:"action"`
+	Actor      string              `json:"actor" bson:"actor"`
+	Timestamp  time.Time           `json:"timestamp" bson:"timestamp"`
+	Changes    []FieldChange       `json:"changes" bson:"changes"`
+	Snapshot   CommonVendorRequest `json:"snapshot" bson:"snapshot"`
+	RevertedTo int64               `json:"reverted_to,omitempty" bson:"reverted_to,omitempty"`
+	MergedInto *primitive.ObjectID `json:"merged_into,omitempty" bson:"merged_into,omitempty"`
+}
+
+// SnapshotOf extracts the editable fields of a stored vendor.
+func SnapshotOf(vendor CommonVendorResponse) CommonVendorRequest {
+	return CommonVendorRequest{
+		Cover:          vendor.Cover,
+		Type:           vendor.Type,
+		Name:           vendor.Name,
+		Location:       vendor.Location,
+		PhoneNumbers:   vendor.PhoneNumbers,
+		Websites:       vendor.Websites,
+		SocialNetworks: vendor.SocialNetworks,
+		Media:          vendor.Media,
+		Tags:           vendor.Tags,
+		Categories:     vendor.Categories,
+		Coordinates:    vendor.Coordinates,
+	}
+}
+
+// DiffVendors lists the fields that differ between two snapshots, keyed by
+// their JSON names. Nil and empty lists are treated as equal.
+func DiffVendors(before, after CommonVendorRequest) []FieldChange {
+	changes := []FieldChange{}
+
+	beforeValue := reflect.ValueOf(before)
+	afterValue := reflect.ValueOf(after)
+	fields := beforeValue.Type()
+
+	for i := 0; i < fields.NumField(); i++ {
+		oldValue := beforeValue.Field(i).Interface()
+		newValue := afterValue.Field(i).Interface()
+
+		if equalFieldValues(oldValue, newValue) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{
+			Field: jsonName(fields.Field(i)),
+			Old:   oldValue,
+			New:   newValue,
+		})
+	}
+
+	return changes
+}
+
+func equalFieldValues(a, b interface{}) bool {
+	aList, aIsList := a.([]string)
+	bList, bIsList := b.([]string)
+	if aIsList && bIsList {
+		if len(aList) != len(bList) {
+			return false
+		}
+		for i := range aList {
+			if aList[i] != bList[i] {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+type actorKey struct{}
+
+const anonymousActor = "anonymous"
+
+// ContextWithActor attaches the identity of whoever is making a change, so
+// that the service can record it in the vendor history.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return anonymousActor
+}
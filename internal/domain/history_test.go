@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffVendors(t *testing.T) {
+	before := domain.CommonVendorRequest{
+		Name:         "Pizza Place",
+		PhoneNumbers: []string{"111"},
+		Tags:         nil,
+	}
+	after := domain.CommonVendorRequest{
+		Name:         "Pizza Palace",
+		PhoneNumbers: []string{"111", "222"},
+		Tags:         []string{},
+	}
+
+	changes := domain.DiffVendors(before, after)
+
+	assert.Equal(t, []domain.FieldChange{
+		{Field: "name", Old: "Pizza Place", New: "Pizza Palace"},
+		{Field: "phone_numbers", Old: []string{"111"}, New: []string{"111", "222"}},
+	}, changes)
+
+	assert.Empty(t, domain.DiffVendors(after, after))
+}
+
+func TestActorFromContext(t *testing.T) {
+	assert.Equal(t, "anonymous", domain.ActorFromContext(context.Background()))
+
+	ctx := domain.ContextWithActor(context.Background(), "admin@example.com")
+	assert.Equal(t, "admin@example.com", domain.ActorFromContext(ctx))
+}
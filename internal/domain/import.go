@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrInvalidImport means the import as a whole can't be read, e.g. for an
+	// unknown column or option. No row of it is written.
+	ErrInvalidImport = errors.New("invalid import")
+	// ErrInvalidImportRow means a single row can't be read. The other rows
+	// are still imported.
+	ErrInvalidImportRow = errors.New("invalid import row")
+	// ErrDuplicateImportKey means an earlier row of the same import has the
+	// same key.
+	ErrDuplicateImportKey = errors.New("duplicate import key")
+	// ErrAmbiguousImportKey means more than one vendor has the row's key.
+	ErrAmbiguousImportKey = errors.New("ambiguous import key")
+)
+
+// MaxImportRows caps the number of rows a single import may carry.
+const MaxImportRows = 10000
+
+type ImportFormat string
+
+const (
+	ImportCSV    ImportFormat = "csv"
+	ImportNDJSON ImportFormat = "ndjson"
+)
+
+// Keys an import can match existing vendors by.
+const (
+	ImportKeyID   = "_id"
+	ImportKeyName = "name"
+)
+
+// DefaultListDelimiter joins the values of a list column in CSV.
+const DefaultListDelimiter = "|"
+
+// ImportOptions controls an import. Rows whose Key matches an existing vendor
+// update it and the others create vendors; without a Key every row creates
+// one. A DryRun checks every row and reports what would be written without
+// writing anything.
+type ImportOptions struct {
+	Format        ImportFormat
+	Key           string
+	DryRun        bool
+	ListDelimiter string
+}
+
+func (o *ImportOptions) Validate() error {
+	switch o.Format {
+	case ImportCSV, ImportNDJSON:
+	default:
+		return fmt.Errorf("%w: format must be %s or %s", ErrInvalidImport, ImportCSV, ImportNDJSON)
+	}
+	switch o.Key {
+	case "", ImportKeyID, ImportKeyName:
+	default:
+		return fmt.Errorf("%w: key must be %s or %s", ErrInvalidImport, ImportKeyID, ImportKeyName)
+	}
+	if o.ListDelimiter == "" {
+		o.ListDelimiter = DefaultListDelimiter
+	}
+	return nil
+}
+
+// ImportRow is one vendor read from an import. Line is where the row starts
+// in the source. ID is the row's _id, if it has one. Fields lists the JSON
+// names of the vendor fields the row carries: the columns of a CSV header or
+// the keys of an NDJSON object. Err is set when the row couldn't be read.
+type ImportRow struct {
+	Line   int
+	ID     string
+	Vendor CreateVendorRequest
+	Fields []string
+	Err    error
+}
+
+// Has reports whether the row carries the vendor field with the given JSON
+// name.
+func (r *ImportRow) Has(field string) bool {
+	return slices.Contains(r.Fields, field)
+}
+
+// Apply returns vendor with the fields the row carries taken from the row.
+// The fields the row leaves out keep their value, so an update doesn't wipe
+// the columns an import doesn't have.
+func (r *ImportRow) Apply(vendor CommonVendorRequest) CommonVendorRequest {
+	row := reflect.ValueOf(CommonVendorRequest(r.Vendor))
+	target := reflect.ValueOf(&vendor).Elem()
+	for i := 0; i < row.NumField(); i++ {
+		if r.Has(jsonName(row.Type().Field(i))) {
+			target.Field(i).Set(row.Field(i))
+		}
+	}
+	return vendor
+}
+
+// KeyValue returns the value of the row that key matches vendors by.
+func (r *ImportRow) KeyValue(key string) string {
+	switch key {
+	case ImportKeyID:
+		return r.ID
+	case ImportKeyName:
+		return r.Vendor.Name
+	default:
+		return ""
+	}
+}
+
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportFailed  ImportAction = "failed"
+)
+
+// ImportRowResult reports what happened to one row. In a dry run the action
+// is the one that would have been taken, and created rows have no ID yet.
+type ImportRowResult struct {
+	Line   int                `json:"line"`
+	Action ImportAction       `json:"action"`
+	ID     primitive.ObjectID `json:"_id,omitempty"`
+	Error  string             `json:"error,omitempty"`
+	Err    error              `json:"-"`
+}
+
+func (r *ImportRowResult) Fail(err error) {
+	r.Action = ImportFailed
+	r.Err = err
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Rows    []ImportRowResult `json:"rows"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+}
+
+func NewImportReport(dryRun bool, rows []ImportRowResult) *ImportReport {
+	report := &ImportReport{DryRun: dryRun, Rows: rows}
+	for _, row := range rows {
+		switch row.Action {
+		case ImportCreated:
+			report.Created++
+		case ImportUpdated:
+			report.Updated++
+		case ImportFailed:
+			report.Failed++
+		}
+	}
+	return report
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidMapArea = errors.New("invalid map area")
+
+const (
+	GeoJSONPolygon = "Polygon"
+
+	// MaxMapVendors caps how many pins a single map query returns. Views
+	// with more vendors than this should ask for clusters instead.
+	MaxMapVendors = 1000
+
+	// MaxMapGrid caps the number of clustering cells along a viewport side.
+	MaxMapGrid = 64
+
+	// strictWindingCRS lets MongoDB accept polygons larger than a hemisphere,
+	// such as the viewport of a fully zoomed out map. Rings must then be
+	// counter-clockwise.
+	strictWindingCRS = "urn:x-mongodb:crs:strictwinding:EPSG:4326"
+)
+
+type GeoCRS struct {
+	Type       string `json:"type" bson:"type"`
+	Properties struct {
+		Name string `json:"name" bson:"name"`
+	} `json:"properties" bson:"properties"`
+}
+
+// GeoPolygon is a GeoJSON polygon. The first ring is the outer boundary and
+// any further rings are holes.
+type GeoPolygon struct {
+	Type        string        `json:"type" bson:"type"`
+	Coordinates [][][]float64 `json:"coordinates" bson:"coordinates"`
+	CRS         *GeoCRS       `json:"crs,omitempty" bson:"crs,omitempty"`
+}
+
+// BoundingBoxPolygon returns the rectangle between the south-west and
+// north-east corners as a counter-clockwise polygon.
+func BoundingBoxPolygon(minLng, minLat, maxLng, maxLat float64) (*GeoPolygon, error) {
+	if ValidateLngLat(minLng, minLat) != nil || ValidateLngLat(maxLng, maxLat) != nil || minLng >= maxLng || minLat >= maxLat {
+		return nil, ErrInvalidMapArea
+	}
+
+	crs := &GeoCRS{Type: "name"}
+	crs.Properties.Name = strictWindingCRS
+
+	return &GeoPolygon{
+		Type: GeoJSONPolygon,
+		Coordinates: [][][]float64{{
+			{minLng, minLat},
+			{maxLng, minLat},
+			{maxLng, maxLat},
+			{minLng, maxLat},
+			{minLng, minLat},
+		}},
+		CRS: crs,
+	}, nil
+}
+
+func (p *GeoPolygon) Validate() error {
+	if p == nil || p.Type != GeoJSONPolygon || len(p.Coordinates) == 0 {
+		return ErrInvalidMapArea
+	}
+	for _, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return ErrInvalidMapArea
+		}
+		for _, position := range ring {
+			if len(position) != 2 || ValidateLngLat(position[0], position[1]) != nil {
+				return ErrInvalidMapArea
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return ErrInvalidMapArea
+		}
+	}
+	return nil
+}
+
+// Contains reports whether point lies inside the outer ring and outside every
+// hole. Edges are treated as straight lines in longitude/latitude space.
+func (p *GeoPolygon) Contains(point *GeoPoint) bool {
+	if !ringContains(p.Coordinates[0], point.Lng(), point.Lat()) {
+		return false
+	}
+	for _, hole := range p.Coordinates[1:] {
+		if ringContains(hole, point.Lng(), point.Lat()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the south-west and north-east corners of the outer ring.
+func (p *GeoPolygon) Bounds() (minLng, minLat, maxLng, maxLat float64) {
+	minLng, minLat = math.Inf(1), math.Inf(1)
+	maxLng, maxLat = math.Inf(-1), math.Inf(-1)
+	for _, position := range p.Coordinates[0] {
+		minLng, maxLng = math.Min(minLng, position[0]), math.Max(maxLng, position[0])
+		minLat, maxLat = math.Min(minLat, position[1]), math.Max(maxLat, position[1])
+	}
+	return minLng, minLat, maxLng, maxLat
+}
+
+func ringContains(ring [][]float64, lng, lat float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// MapQuery selects the vendors inside Area. A positive Grid asks for the
+// area to be split into Grid cells along its longer side and for vendors to
+// be clustered per cell.
+type MapQuery struct {
+	Area *GeoPolygon
+	Grid int
+}
+
+func (q MapQuery) Validate() error {
+	if err := q.Area.Validate(); err != nil {
+		return err
+	}
+	if q.Grid < 0 || q.Grid > MaxMapGrid {
+		return ErrInvalidMapArea
+	}
+	return nil
+}
+
+// CellSize returns the clustering cell size in degrees.
+func (q MapQuery) CellSize() float64 {
+	minLng, minLat, maxLng, maxLat := q.Area.Bounds()
+	return math.Max(maxLng-minLng, maxLat-minLat) / float64(q.Grid)
+}
+
+// MapVendor is the lightweight projection of a vendor used to draw a pin.
+type MapVendor struct {
+	ID          primitive.ObjectID `json:"_id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Type        string             `json:"type" bson:"type"`
+	Cover       string             `json:"cover" bson:"cover"`
+	Coordinates *GeoPoint          `json:"coordinates" bson:"coordinates"`
+}
+
+// MapCluster summarises the vendors in one grid cell. Vendor holds one of
+// them, which is enough to draw a pin when Count is 1.
+type MapCluster struct {
+	Count       int        `json:"count"`
+	Coordinates *GeoPoint  `json:"coordinates"`
+	Vendor      *MapVendor `json:"-"`
+}
+
+type VendorMap struct {
+	Vendors   []*MapVendor  `json:"vendors"`
+	Clusters  []*MapCluster `json:"clusters"`
+	Truncated bool          `json:"truncated"`
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoPolygonContains(t *testing.T) {
+	square := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	hole := [][]float64{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}
+	polygon := &domain.GeoPolygon{Type: domain.GeoJSONPolygon, Coordinates: [][][]float64{square, hole}}
+	require.NoError(t, polygon.Validate())
+
+	tests := []struct {
+		name  string
+		point *domain.GeoPoint
+		want  bool
+	}{
+		{name: "Inside", point: domain.NewGeoPoint(2, 2), want: true},
+		{name: "Outside", point: domain.NewGeoPoint(12, 2), want: false},
+		{name: "In hole", point: domain.NewGeoPoint(5, 5), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, polygon.Contains(tt.point))
+		})
+	}
+}
+
+func TestBoundingBoxPolygon(t *testing.T) {
+	box, err := domain.BoundingBoxPolygon(-180, -90, 180, 90)
+	require.NoError(t, err)
+	require.NoError(t, box.Validate())
+	assert.True(t, box.Contains(domain.NewGeoPoint(13.4, 52.5)))
+
+	minLng, minLat, maxLng, maxLat := box.Bounds()
+	assert.Equal(t, []float64{-180, -90, 180, 90}, []float64{minLng, minLat, maxLng, maxLat})
+
+	_, err = domain.BoundingBoxPolygon(10, 0, 0, 10)
+	assert.ErrorIs(t, err, domain.ErrInvalidMapArea)
+}
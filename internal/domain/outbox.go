@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxRecord holds an event written in the same transaction as the vendor
+// change it announces, until it has been handed to every publisher. A record
+// is only removed once that succeeded, so an event may be published more than
+// once but is never lost. Consumers tell repeats apart by vendor and revision.
+type OutboxRecord struct {
+	ID          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Event       VendorEvent        `json:"event" bson:"event"`
+	Attempts    int                `json:"attempts" bson:"attempts"`
+	LastError   string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	AvailableAt time.Time          `json:"available_at" bson:"available_at"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewOutboxRecord returns the record announcing a change that left vendor as
+// given. The change is dated by the vendor's updated_at, which every write
+// sets.
+func NewOutboxRecord(eventType VendorEventType, vendor CommonVendorResponse) *OutboxRecord {
+	return &OutboxRecord{
+		Event: VendorEvent{
+			Type:      eventType,
+			VendorID:  vendor.ID,
+			Revision:  vendor.Version,
+			Timestamp: vendor.UpdatedAt,
+			Vendor:    SnapshotOf(vendor),
+		},
+		AvailableAt: vendor.UpdatedAt,
+		CreatedAt:   vendor.UpdatedAt,
+	}
+}
@@ -0,0 +1,33 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewOutboxRecord(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	vendor := domain.CommonVendorResponse{
+		ID:        primitive.NewObjectID(),
+		Type:      "food",
+		Name:      "Pizzeria",
+		Tags:      []string{"pizza"},
+		Version:   4,
+		CreatedAt: updatedAt.Add(-time.Hour),
+		UpdatedAt: updatedAt,
+	}
+
+	record := domain.NewOutboxRecord(domain.VendorEventUpdated, vendor)
+
+	assert.Equal(t, domain.VendorEventUpdated, record.Event.Type)
+	assert.Equal(t, vendor.ID, record.Event.VendorID)
+	assert.Equal(t, int64(4), record.Event.Revision)
+	assert.Equal(t, updatedAt, record.Event.Timestamp)
+	assert.Equal(t, domain.SnapshotOf(vendor), record.Event.Vendor)
+	assert.Equal(t, updatedAt, record.AvailableAt)
+	assert.Zero(t, record.Attempts)
+}
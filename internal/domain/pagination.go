@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrPageTooDeep   = errors.New("page too deep")
+)
+
+// MaxPageDepth bounds how far into a listing a page number may reach, counted
+// in items up to the end of the page. Deeper pages are served by cursor only,
+// since reaching them by number means reading every item before them.
+const MaxPageDepth = 10000
+
+// ListOptions selects a page of results either by page number or, when Cursor
+// is set, by continuing after the position the cursor was issued for. A
+// non-empty Type restricts the results to vendors of that type, and a
+// non-empty Sort overrides the default order of the listing. Facets names the
+// fields to count over every result, not just the page, and FacetLimit how
+// many values of each to keep, MaxFacetValues if zero.
+type ListOptions struct {
+	Page       int
+	PageSize   int
+	Cursor     string
+	Type       string
+	Sort       Sort
+	Facets     []string
+	FacetLimit int
+}
+
+// Cursor marks the last item of a page. Values holds that item's sort key
+// values in sort order; ID breaks ties between items with equal keys.
+type Cursor struct {
+	Values []interface{}      `json:"v,omitempty"`
+	ID     primitive.ObjectID `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque cursor and checks that it carries the number
+// of sort key values the caller orders by.
+func DecodeCursor(encoded string, keys int) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() || len(cursor.Values) != keys {
+		return cursor, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+type VendorList struct {
+	Vendors    []*GetVendorResponse
+	NextCursor string
+	Facets     Facets
+}
+
+type SearchVendorList struct {
+	Vendors    []*SearchVendorResponse
+	NextCursor string
+	Facets     Facets
+}
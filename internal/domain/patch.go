@@ -0,0 +1,556 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrPatchTestFailed = errors.New("patch test failed")
+)
+
+type PatchFormat string
+
+const (
+	// PatchFormatMerge is a JSON Merge Patch (RFC 7396).
+	PatchFormatMerge PatchFormat = "merge"
+	// PatchFormatJSON is a JSON Patch (RFC 6902).
+	PatchFormatJSON PatchFormat = "json"
+)
+
+// ApplyPatch applies a patch document to a vendor and returns the patched
+// vendor. The patch works on the vendor's JSON form, where lists are never
+// null, so operations such as appending with /tags/- work on empty lists.
+func ApplyPatch(vendor CommonVendorRequest, format PatchFormat, patch []byte) (CommonVendorRequest, error) {
+	document, err := vendorDocument(vendor)
+	if err != nil {
+		return vendor, err
+	}
+
+	var patched interface{}
+	switch format {
+	case PatchFormatMerge:
+		patched, err = applyMergePatch(document, patch)
+	case PatchFormatJSON:
+		patched, err = applyJSONPatch(document, patch)
+	default:
+		return vendor, fmt.Errorf("%w: unknown format %q", ErrInvalidPatch, format)
+	}
+	if err != nil {
+		return vendor, err
+	}
+
+	return documentVendor(patched)
+}
+
+func vendorDocument(vendor CommonVendorRequest) (map[string]interface{}, error) {
+	data, err := json.Marshal(vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	fields := reflect.TypeOf(vendor)
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonName(fields.Field(i))
+		if fields.Field(i).Type.Kind() == reflect.Slice && document[name] == nil {
+			document[name] = []interface{}{}
+		}
+	}
+	return document, nil
+}
+
+// documentVendor converts a patched document back, rejecting fields a vendor
+// doesn't have and values of the wrong type. Empty lists become nil again.
+func documentVendor(document interface{}) (CommonVendorRequest, error) {
+	var vendor CommonVendorRequest
+
+	if _, ok := document.(map[string]interface{}); !ok {
+		return vendor, fmt.Errorf("%w: a vendor must be a JSON object", ErrInvalidPatch)
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return vendor, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&vendor); err != nil {
+		return vendor, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	fields := reflect.ValueOf(&vendor).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		if field := fields.Field(i); field.Kind() == reflect.Slice && field.Len() == 0 {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+	return vendor, nil
+}
+
+func applyMergePatch(document interface{}, patch []byte) (interface{}, error) {
+	var merge interface{}
+	if err := json.Unmarshal(patch, &merge); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return mergeValue(document, merge), nil
+}
+
+// mergeValue implements the MergePatch function of RFC 7396.
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergeValue(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func applyJSONPatch(document interface{}, patch []byte) (interface{}, error) {
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	for i, operation := range operations {
+		var err error
+		document, err = applyJSONPatchOperation(document, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return document, nil
+}
+
+func applyJSONPatchOperation(document interface{}, operation jsonPatchOperation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	value := func() (interface{}, error) {
+		if operation.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		var value interface{}
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return value, nil
+	}
+
+	from := func() ([]string, error) {
+		if operation.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		return parsePointer(*operation.From)
+	}
+
+	switch operation.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, path, v)
+	case "remove":
+		document, _, err := removeValue(document, path)
+		return document, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		document, _, err = removeValue(document, path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, path, v)
+	case "move":
+		source, err := from()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(source) && reflect.DeepEqual(path[:len(source)], source) {
+			return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+		}
+		document, moved, err := removeValue(document, source)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, path, moved)
+	case "copy":
+		source, err := from()
+		if err != nil {
+			return nil, err
+		}
+		copied, err := getValue(document, source)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, path, deepCopy(copied))
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := getValue(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, v) {
+			return nil, fmt.Errorf("%w: value at %s differs", ErrPatchTestFailed, *operation.Path)
+		}
+		return document, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, operation.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array reference token. The index may equal the array
+// length only where appending is allowed, which "-" also stands for.
+func arrayIndex(token string, length int, appending bool) (int, error) {
+	if token == "-" && appending {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || strconv.Itoa(index) != token {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+
+	limit := length - 1
+	if appending {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrInvalidPatch, index)
+	}
+	return index, nil
+}
+
+func getValue(document interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := document.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+			}
+			document = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			document = container[index]
+		default:
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+		}
+	}
+	return document, nil
+}
+
+// addValue adds value at path and returns the updated document. Arrays are
+// returned anew because inserting may reallocate them.
+func addValue(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token, rest := path[0], path[1:]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+		}
+		updated, err := addValue(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container), len(rest) == 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		}
+		updated, err := addValue(container[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+	}
+}
+
+// removeValue removes the value at path and returns the updated document
+// together with the removed value.
+func removeValue(document interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole vendor", ErrInvalidPatch)
+	}
+
+	token, rest := path[0], path[1:]
+	switch container := document.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, child, nil
+		}
+		updated, removed, err := removeValue(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = updated
+		return container, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := container[index]
+			return append(container[:index], container[index+1:]...), removed, nil
+		}
+		updated, removed, err := removeValue(container[index], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = updated
+		return container, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+	}
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for name, child := range v {
+			c[name] = deepCopy(child)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, child := range v {
+			c[i] = deepCopy(child)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// VendorPatch is the smallest set of field operations that turns one vendor
+// state into another. Fields are named as they are stored, which is also
+// their JSON name. Each field appears in at most one of the operations, so a
+// document store can apply them in a single update.
+type VendorPatch struct {
+	Set   map[string]interface{}
+	Unset []string
+	Push  map[string][]string
+	Pull  map[string][]string
+}
+
+// NewVendorPatch compares two vendor states field by field. A list that only
+// grew at the end becomes a push of the new values, and a list that only lost
+// every occurrence of some values becomes a pull of those values. Any other
+// change overwrites the field, and a removed location is unset.
+func NewVendorPatch(before, after CommonVendorRequest) *VendorPatch {
+	patch := &VendorPatch{
+		Set:  map[string]interface{}{},
+		Push: map[string][]string{},
+		Pull: map[string][]string{},
+	}
+
+	beforeValue := reflect.ValueOf(before)
+	afterValue := reflect.ValueOf(after)
+	fields := beforeValue.Type()
+
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonName(fields.Field(i))
+		oldValue := beforeValue.Field(i).Interface()
+		newValue := afterValue.Field(i).Interface()
+
+		if equalFieldValues(oldValue, newValue) {
+			continue
+		}
+
+		if oldList, ok := oldValue.([]string); ok {
+			newList := newValue.([]string)
+			if appended, ok := appendedValues(oldList, newList); ok {
+				patch.Push[name] = appended
+				continue
+			}
+			if pulled, ok := pulledValues(oldList, newList); ok {
+				patch.Pull[name] = pulled
+				continue
+			}
+		}
+
+		if afterValue.Field(i).Kind() == reflect.Pointer && afterValue.Field(i).IsNil() {
+			patch.Unset = append(patch.Unset, name)
+			continue
+		}
+
+		patch.Set[name] = newValue
+	}
+
+	return patch
+}
+
+func (p *VendorPatch) Empty() bool {
+	return len(p.Set) == 0 && len(p.Unset) == 0 && len(p.Push) == 0 && len(p.Pull) == 0
+}
+
+// Apply returns vendor with the patch applied, sharing no lists with it.
+func (p *VendorPatch) Apply(vendor CommonVendorRequest) CommonVendorRequest {
+	patched := reflect.ValueOf(&vendor).Elem()
+	fields := patched.Type()
+
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonName(fields.Field(i))
+		field := patched.Field(i)
+
+		if list, ok := field.Interface().([]string); ok {
+			field.Set(reflect.ValueOf(append([]string(nil), list...)))
+		}
+
+		if value, ok := p.Set[name]; ok {
+			field.Set(reflect.ValueOf(value))
+		}
+		for _, unset := range p.Unset {
+			if unset == name {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+		if values, ok := p.Push[name]; ok {
+			field.Set(reflect.ValueOf(append(field.Interface().([]string), values...)))
+		}
+		if values, ok := p.Pull[name]; ok {
+			field.Set(reflect.ValueOf(withoutValues(field.Interface().([]string), values)))
+		}
+	}
+
+	return vendor
+}
+
+// appendedValues reports the values added to the end of a non-empty list. An
+// empty list may be stored as null, which can't be pushed to.
+func appendedValues(before, after []string) ([]string, bool) {
+	if len(before) == 0 || len(after) <= len(before) {
+		return nil, false
+	}
+	for i := range before {
+		if before[i] != after[i] {
+			return nil, false
+		}
+	}
+	return append([]string(nil), after[len(before):]...), true
+}
+
+// pulledValues reports the values whose every occurrence was removed from a
+// list, provided nothing else changed.
+func pulledValues(before, after []string) ([]string, bool) {
+	kept := make(map[string]bool, len(after))
+	for _, value := range after {
+		kept[value] = true
+	}
+
+	var pulled []string
+	seen := map[string]bool{}
+	for _, value := range before {
+		if !kept[value] && !seen[value] {
+			pulled = append(pulled, value)
+			seen[value] = true
+		}
+	}
+
+	if len(pulled) == 0 || !equalFieldValues(withoutValues(before, pulled), after) {
+		return nil, false
+	}
+	return pulled, true
+}
+
+func withoutValues(list, values []string) []string {
+	remove := make(map[string]bool, len(values))
+	for _, value := range values {
+		remove[value] = true
+	}
+
+	var kept []string
+	for _, value := range list {
+		if !remove[value] {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
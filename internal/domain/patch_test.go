@@ -0,0 +1,121 @@
+package domain_test
+
+import (
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	vendor := domain.CommonVendorRequest{
+		Name:        "Pizza Place",
+		Location:    "Main St",
+		Tags:        []string{"pizza"},
+		Coordinates: domain.NewGeoPoint(10, 20),
+	}
+
+	patched, err := domain.ApplyPatch(vendor, domain.PatchFormatMerge, []byte(`{"name":"Pizza Palace","location":null,"coordinates":null,"tags":["pizza","pasta"]}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.CommonVendorRequest{
+		Name: "Pizza Palace",
+		Tags: []string{"pizza", "pasta"},
+	}, patched)
+
+	_, err = domain.ApplyPatch(vendor, domain.PatchFormatMerge, []byte(`{"rating":5}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidPatch)
+
+	_, err = domain.ApplyPatch(vendor, domain.PatchFormatMerge, []byte(`["name"]`))
+	assert.ErrorIs(t, err, domain.ErrInvalidPatch)
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	vendor := domain.CommonVendorRequest{
+		Name:  "Pizza Place",
+		Tags:  []string{"pizza", "pasta"},
+		Media: nil,
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    domain.CommonVendorRequest
+		wantErr error
+	}{
+		{
+			name:  "Append and insert",
+			patch: `[{"op":"add","path":"/tags/-","value":"salad"},{"op":"add","path":"/tags/0","value":"italian"},{"op":"add","path":"/media/-","value":"a.png"}]`,
+			want:  domain.CommonVendorRequest{Name: "Pizza Place", Tags: []string{"italian", "pizza", "pasta", "salad"}, Media: []string{"a.png"}},
+		},
+		{
+			name:  "Remove and replace",
+			patch: `[{"op":"remove","path":"/tags/0"},{"op":"replace","path":"/name","value":"Pasta Place"}]`,
+			want:  domain.CommonVendorRequest{Name: "Pasta Place", Tags: []string{"pasta"}},
+		},
+		{
+			name:  "Move and copy",
+			patch: `[{"op":"copy","from":"/tags/1","path":"/media/0"},{"op":"move","from":"/tags/0","path":"/tags/1"}]`,
+			want:  domain.CommonVendorRequest{Name: "Pizza Place", Tags: []string{"pasta", "pizza"}, Media: []string{"pasta"}},
+		},
+		{
+			name:    "Failed test",
+			patch:   `[{"op":"test","path":"/name","value":"Sushi Bar"},{"op":"replace","path":"/name","value":"x"}]`,
+			wantErr: domain.ErrPatchTestFailed,
+		},
+		{
+			name:    "Missing path",
+			patch:   `[{"op":"remove","path":"/tags/5"}]`,
+			wantErr: domain.ErrInvalidPatch,
+		},
+		{
+			name:    "Wrong type",
+			patch:   `[{"op":"replace","path":"/name","value":5}]`,
+			wantErr: domain.ErrInvalidPatch,
+		},
+		{
+			name:    "Unknown op",
+			patch:   `[{"op":"increment","path":"/name"}]`,
+			wantErr: domain.ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := domain.ApplyPatch(vendor, domain.PatchFormatJSON, []byte(tt.patch))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, patched)
+		})
+	}
+}
+
+func TestNewVendorPatch(t *testing.T) {
+	before := domain.CommonVendorRequest{
+		Name:        "Pizza Place",
+		Tags:        []string{"pizza", "pasta", "pizza"},
+		Media:       []string{"a.png"},
+		Categories:  nil,
+		Coordinates: domain.NewGeoPoint(10, 20),
+	}
+	after := domain.CommonVendorRequest{
+		Name:       "Pizza Palace",
+		Tags:       []string{"pasta"},
+		Media:      []string{"a.png", "b.png"},
+		Categories: []string{"food"},
+	}
+
+	patch := domain.NewVendorPatch(before, after)
+
+	assert.Equal(t, map[string]interface{}{"name": "Pizza Palace", "categories": []string{"food"}}, patch.Set)
+	assert.Equal(t, []string{"coordinates"}, patch.Unset)
+	assert.Equal(t, map[string][]string{"media": {"b.png"}}, patch.Push)
+	assert.Equal(t, map[string][]string{"tags": {"pizza"}}, patch.Pull)
+	assert.Equal(t, after, patch.Apply(before))
+
+	assert.True(t, domain.NewVendorPatch(before, before).Empty())
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextSearchWeights sets how much a match in each field contributes to a
+// vendor's relevance score in full-text search.
+var TextSearchWeights = map[string]int32{
+	"name":       10,
+	"tags":       5,
+	"categories": 5,
+	"location":   3,
+}
+
+// SearchTerms splits text into the lowercased words a text search matches on.
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+}
+
+// TextScore adds up the weights of the fields of vendor in which each of terms
+// occurs as a word, once per occurrence. It is zero when no term occurs.
+func TextScore(vendor *GetVendorResponse, terms []string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	fields := map[string][]string{
+		"name":       {vendor.Name},
+		"location":   {vendor.Location},
+		"tags":       vendor.Tags,
+		"categories": vendor.Categories,
+	}
+
+	var score float64
+	for field, values := range fields {
+		weight := float64(TextSearchWeights[field])
+		for _, value := range values {
+			for _, token := range SearchTerms(value) {
+				for _, term := range terms {
+					if token == term {
+						score += weight
+					}
+				}
+			}
+		}
+	}
+	return score
+}
+
+// MaxSearchQueryLength caps the number of characters accepted in a search
+// query, regardless of mode.
+const MaxSearchQueryLength = 100
+
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+type SearchMode string
+
+const (
+	SearchModeText     SearchMode = "text"
+	SearchModePrefix   SearchMode = "prefix"
+	SearchModeContains SearchMode = "contains"
+	SearchModeExact    SearchMode = "exact"
+	SearchModeRegex    SearchMode = "regex"
+)
+
+// SearchQuery is a user supplied search term together with how it should be
+// matched. Every mode except SearchModeRegex treats the term literally.
+type SearchQuery struct {
+	Text string
+	Mode SearchMode
+}
+
+// Validate rejects queries that are empty, too long, use an unknown mode or,
+// in regex mode, do not compile. Patterns are checked with Go's RE2 engine,
+// which rules out constructs such as backreferences. That doesn't make them
+// cheap to match: the database runs them with a backtracking engine, so
+// stores bound the time a regex search may take.
+func (q *SearchQuery) Validate() error {
+	if q.Mode == "" {
+		q.Mode = SearchModeText
+	}
+
+	if strings.TrimSpace(q.Text) == "" {
+		return fmt.Errorf("%w: query must not be empty", ErrInvalidSearchQuery)
+	}
+
+	if utf8.RuneCountInString(q.Text) > MaxSearchQueryLength {
+		return fmt.Errorf("%w: query must be at most %d characters", ErrInvalidSearchQuery, MaxSearchQueryLength)
+	}
+
+	switch q.Mode {
+	case SearchModeText, SearchModePrefix, SearchModeContains, SearchModeExact:
+		return nil
+	case SearchModeRegex:
+		if _, err := regexp.Compile(q.Text); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSearchQuery, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidSearchQuery, q.Mode)
+	}
+}
+
+// Pattern returns the regular expression for the pattern based modes. The
+// result is meant to be matched case-insensitively.
+func (q *SearchQuery) Pattern() string {
+	escaped := regexp.QuoteMeta(q.Text)
+
+	switch q.Mode {
+	case SearchModePrefix:
+		return "^" + escaped
+	case SearchModeExact:
+		return "^" + escaped + "$"
+	case SearchModeRegex:
+		return q.Text
+	default:
+		return escaped
+	}
+}
+
+type SearchVendorResponse struct {
+	GetVendorResponse `bson:",inline"`
+	Score             float64 `json:"score,omitempty" bson:"score,omitempty"`
+}
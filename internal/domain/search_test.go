@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQueryValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    domain.SearchQuery
+		wantErr  bool
+		wantMode domain.SearchMode
+	}{
+		{name: "Defaults to text", query: domain.SearchQuery{Text: "pizza"}, wantMode: domain.SearchModeText},
+		{name: "Prefix", query: domain.SearchQuery{Text: "piz", Mode: domain.SearchModePrefix}, wantMode: domain.SearchModePrefix},
+		{name: "Metacharacters allowed outside regex mode", query: domain.SearchQuery{Text: "(", Mode: domain.SearchModeContains}, wantMode: domain.SearchModeContains},
+		{name: "Empty", query: domain.SearchQuery{Text: "  "}, wantErr: true},
+		{name: "Too long", query: domain.SearchQuery{Text: strings.Repeat("a", domain.MaxSearchQueryLength+1)}, wantErr: true},
+		{name: "Unknown mode", query: domain.SearchQuery{Text: "pizza", Mode: "fuzzy"}, wantErr: true},
+		{name: "Invalid regex", query: domain.SearchQuery{Text: "(", Mode: domain.SearchModeRegex}, wantErr: true},
+		{name: "Backreference", query: domain.SearchQuery{Text: `(a)\1`, Mode: domain.SearchModeRegex}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidSearchQuery)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMode, tt.query.Mode)
+		})
+	}
+}
+
+func TestSearchQueryPattern(t *testing.T) {
+	tests := []struct {
+		mode domain.SearchMode
+		text string
+		want string
+	}{
+		{mode: domain.SearchModeContains, text: "a.b(", want: `a\.b\(`},
+		{mode: domain.SearchModePrefix, text: "a.b", want: `^a\.b`},
+		{mode: domain.SearchModeExact, text: "a.b", want: `^a\.b$`},
+		{mode: domain.SearchModeRegex, text: "a.b", want: `a.b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			query := domain.SearchQuery{Text: tt.text, Mode: tt.mode}
+			assert.Equal(t, tt.want, query.Pattern())
+		})
+	}
+}
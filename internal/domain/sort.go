@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// Fields vendors can be sorted by. Each is backed by an index.
+const (
+	SortFieldName      = "name"
+	SortFieldType      = "type"
+	SortFieldCreatedAt = "created_at"
+	SortFieldUpdatedAt = "updated_at"
+)
+
+// SortableFields is the whitelist of fields a sort may name.
+var SortableFields = []string{SortFieldName, SortFieldType, SortFieldCreatedAt, SortFieldUpdatedAt}
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// Sort orders results by its fields in turn. Vendors with equal values are
+// ordered by _id, in the direction of the last field, so that a sort on a
+// single field can walk its index in either direction. An empty Sort orders
+// by _id alone.
+type Sort []SortField
+
+// ParseSort reads a comma separated list of sortable fields, each optionally
+// prefixed with - for descending order, such as "-created_at,name".
+func ParseSort(s string) (Sort, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var sort Sort
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		field := SortField{Field: strings.TrimSpace(part)}
+		if rest, ok := strings.CutPrefix(field.Field, "-"); ok {
+			field = SortField{Field: rest, Descending: true}
+		}
+		if !slices.Contains(SortableFields, field.Field) || seen[field.Field] {
+			return nil, ErrInvalidSort
+		}
+		seen[field.Field] = true
+		sort = append(sort, field)
+	}
+	return sort, nil
+}
+
+// DescendingID reports the direction of the _id tiebreaker.
+func (s Sort) DescendingID() bool {
+	return len(s) > 0 && s[len(s)-1].Descending
+}
+
+// Values returns the sort key values of a vendor, as stored in a cursor.
+func (s Sort) Values(vendor *GetVendorResponse) []interface{} {
+	values := make([]interface{}, len(s))
+	for i, field := range s {
+		values[i] = sortValue(vendor, field.Field)
+	}
+	return values
+}
+
+// DecodeCursor parses a cursor issued for this sort. Its values come back as
+// the types they are compared with; timestamps travel as strings in JSON.
+func (s Sort) DecodeCursor(encoded string) (Cursor, error) {
+	cursor, err := DecodeCursor(encoded, len(s))
+	if err != nil {
+		return cursor, err
+	}
+
+	for i, field := range s {
+		text, ok := cursor.Values[i].(string)
+		if !ok {
+			return cursor, ErrInvalidCursor
+		}
+		if field.Field == SortFieldCreatedAt || field.Field == SortFieldUpdatedAt {
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return cursor, ErrInvalidCursor
+			}
+			cursor.Values[i] = t
+		}
+	}
+	return cursor, nil
+}
+
+// Compare orders two vendors, returning a negative number when a comes first.
+func (s Sort) Compare(a, b *GetVendorResponse) int {
+	if c := s.compareValues(s.Values(a), s.Values(b)); c != 0 {
+		return c
+	}
+	return s.compareID(a, b.ID[:])
+}
+
+// After reports whether a vendor sorts after the position of a cursor read
+// with DecodeCursor.
+func (s Sort) After(vendor *GetVendorResponse, cursor Cursor) bool {
+	if c := s.compareValues(s.Values(vendor), cursor.Values); c != 0 {
+		return c > 0
+	}
+	return s.compareID(vendor, cursor.ID[:]) > 0
+}
+
+func (s Sort) compareValues(a, b []interface{}) int {
+	for i, field := range s {
+		var c int
+		switch x := a[i].(type) {
+		case time.Time:
+			c = x.Compare(b[i].(time.Time))
+		case string:
+			c = strings.Compare(x, b[i].(string))
+		}
+		if field.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (s Sort) compareID(vendor *GetVendorResponse, id []byte) int {
+	c := bytes.Compare(vendor.ID[:], id)
+	if s.DescendingID() {
+		c = -c
+	}
+	return c
+}
+
+func sortValue(vendor *GetVendorResponse, field string) interface{} {
+	switch field {
+	case SortFieldName:
+		return vendor.Name
+	case SortFieldType:
+		return vendor.Type
+	case SortFieldCreatedAt:
+		return vendor.CreatedAt
+	case SortFieldUpdatedAt:
+		return vendor.UpdatedAt
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    domain.Sort
+		wantErr bool
+	}{
+		{name: "Empty", sort: ""},
+		{name: "Mixed directions", sort: "-created_at,name", want: domain.Sort{{Field: "created_at", Descending: true}, {Field: "name"}}},
+		{name: "Unknown field", sort: "phone_numbers", wantErr: true},
+		{name: "Repeated field", sort: "name,-name", wantErr: true},
+		{name: "Empty field", sort: "name,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := domain.ParseSort(tt.sort)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidSort)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sort)
+		})
+	}
+}
+
+func TestSortCursor(t *testing.T) {
+	sort, err := domain.ParseSort("-created_at,name")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newer := &domain.GetVendorResponse{ID: primitive.NewObjectID(), Name: "b", CreatedAt: now.Add(time.Hour)}
+	older := &domain.GetVendorResponse{ID: primitive.NewObjectID(), Name: "a", CreatedAt: now}
+	sameTime := &domain.GetVendorResponse{ID: primitive.NewObjectID(), Name: "c", CreatedAt: now}
+
+	assert.Negative(t, sort.Compare(newer, older))
+	assert.Negative(t, sort.Compare(older, sameTime))
+
+	cursor, err := sort.DecodeCursor(domain.EncodeCursor(domain.Cursor{Values: sort.Values(older), ID: older.ID}))
+	require.NoError(t, err)
+	assert.Equal(t, now, cursor.Values[0])
+
+	assert.False(t, sort.After(newer, cursor))
+	assert.False(t, sort.After(older, cursor))
+	assert.True(t, sort.After(sameTime, cursor))
+
+	_, err = sort.DecodeCursor(domain.EncodeCursor(domain.Cursor{Values: []interface{}{"yesterday", "a"}, ID: older.ID}))
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
@@ -1,32 +1,61 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrVendorNotFound    = errors.New("vendor not found")
+	ErrVersionConflict   = errors.New("vendor version conflict")
+	ErrUnknownVendorType = errors.New("unknown vendor type")
+	ErrVendorTypeChanged = errors.New("vendor type cannot be changed")
+)
+
+// Vendor types. Each type is stored in its own collection.
+const (
+	VendorTypeCinema  = "cinema"
+	VendorTypeTheatre = "theatre"
+	VendorTypeFood    = "food"
+)
+
+// VendorTypes lists every vendor type, in the order they are set up.
+var VendorTypes = []string{VendorTypeCinema, VendorTypeTheatre, VendorTypeFood}
 
 type CommonVendorRequest struct {
-	Cover          string   `json:"cover" bson:"cover"`
-	Type           string   `json:"type" bson:"type"`
-	Name           string   `json:"name" bson:"name"`
-	Location       string   `json:"location" bson:"location"`
-	PhoneNumbers   []string `json:"phone_numbers" bson:"phone_numbers"`
-	Websites       []string `json:"websites" bson:"websites"`
-	SocialNetworks []string `json:"social_networks" bson:"social_networks"`
-	Media          []string `json:"media" bson:"media"`
-	Tags           []string `json:"tags" bson:"tags"`
-	Categories     []string `json:"categories" bson:"categories"`
+	Cover          string    `json:"cover" bson:"cover"`
+	Type           string    `json:"type" bson:"type"`
+	Name           string    `json:"name" bson:"name"`
+	Location       string    `json:"location" bson:"location"`
+	PhoneNumbers   []string  `json:"phone_numbers" bson:"phone_numbers"`
+	Websites       []string  `json:"websites" bson:"websites"`
+	SocialNetworks []string  `json:"social_networks" bson:"social_networks"`
+	Media          []string  `json:"media" bson:"media"`
+	Tags           []string  `json:"tags" bson:"tags"`
+	Categories     []string  `json:"categories" bson:"categories"`
+	Coordinates    *GeoPoint `json:"coordinates,omitempty" bson:"coordinates,omitempty"`
 }
 
 type CommonVendorResponse struct {
-	ID             primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Cover          string             `json:"cover" bson:"cover"`
-	Type           string             `json:"type" bson:"type"`
-	Name           string             `json:"name" bson:"name"`
-	Location       string             `json:"location" bson:"location"`
-	PhoneNumbers   []string           `json:"phone_numbers" bson:"phone_numbers"`
-	Websites       []string           `json:"websites" bson:"websites"`
-	SocialNetworks []string           `json:"social_networks" bson:"social_networks"`
-	Media          []string           `json:"media" bson:"media"`
-	Tags           []string           `json:"tags" bson:"tags"`
-	Categories     []string           `json:"categories" bson:"categories"`
+	ID             primitive.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
+	Cover          string               `json:"cover" bson:"cover"`
+	Type           string               `json:"type" bson:"type"`
+	Name           string               `json:"name" bson:"name"`
+	Location       string               `json:"location" bson:"location"`
+	PhoneNumbers   []string             `json:"phone_numbers" bson:"phone_numbers"`
+	Websites       []string             `json:"websites" bson:"websites"`
+	SocialNetworks []string             `json:"social_networks" bson:"social_networks"`
+	Media          []string             `json:"media" bson:"media"`
+	Tags           []string             `json:"tags" bson:"tags"`
+	Categories     []string             `json:"categories" bson:"categories"`
+	Coordinates    *GeoPoint            `json:"coordinates,omitempty" bson:"coordinates,omitempty"`
+	Aliases        []primitive.ObjectID `json:"aliases,omitempty" bson:"aliases,omitempty"`
+	Version        int64                `json:"version" bson:"version"`
+	CreatedAt      time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" bson:"updated_at"`
+	DeletedAt      *time.Time           `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 type GetVendorResponse CommonVendorResponse
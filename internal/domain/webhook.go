@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+)
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// VendorEventTypes lists the events a webhook can subscribe to.
+var VendorEventTypes = []VendorEventType{VendorEventCreated, VendorEventUpdated, VendorEventDeleted}
+
+// Webhook subscribes a URL to vendor events. Secret signs every delivery and
+// is only returned when the webhook is created. No events means all events.
+type Webhook struct {
+	ID        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	URL       string             `json:"url" bson:"url"`
+	Secret    string             `json:"secret,omitempty" bson:"secret"`
+	Events    []VendorEventType  `json:"events" bson:"events"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string            `json:"url"`
+	Secret string            `json:"secret"`
+	Events []VendorEventType `json:"events"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	target, err := url.Parse(r.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	for _, event := range r.Events {
+		if !slices.Contains(VendorEventTypes, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// Subscribed reports whether the webhook wants events of eventType.
+func (w *Webhook) Subscribed(eventType VendorEventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+type DeliveryStatus string
+
+// A delivery is pending until the receiver accepts it, or until it has failed
+// too often and is moved to the dead letters, from where it can be retried.
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event on its way to one webhook, together with the
+// log of every attempt to send it.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	WebhookID     primitive.ObjectID `json:"webhook_id" bson:"webhook_id"`
+	Event         VendorEvent        `json:"event" bson:"event"`
+	Status        DeliveryStatus     `json:"status" bson:"status"`
+	Attempts      []DeliveryAttempt  `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// DeliveryAttempt records how one attempt to send a delivery went. StatusCode
+// is zero when no response was received.
+type DeliveryAttempt struct {
+	At         time.Time     `json:"at" bson:"at"`
+	StatusCode int           `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty" bson:"error,omitempty"`
+	Duration   time.Duration `json:"duration" bson:"duration"`
+}
+
+// SignWebhook returns the signature of a delivery body sent at timestamp:
+// the hex HMAC-SHA256, keyed with the webhook secret, of the Unix timestamp,
+// a dot and the body. Receivers recompute it to check the sender and reject
+// stale timestamps to prevent replays.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
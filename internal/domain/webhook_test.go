@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhookRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domain.CreateWebhookRequest
+		valid   bool
+	}{
+		{name: "All events", request: domain.CreateWebhookRequest{URL: "https://example.com/hook"}, valid: true},
+		{name: "Some events", request: domain.CreateWebhookRequest{URL: "http://localhost:8080", Events: []domain.VendorEventType{domain.VendorEventDeleted}}, valid: true},
+		{name: "Relative URL", request: domain.CreateWebhookRequest{URL: "/hook"}},
+		{name: "Other scheme", request: domain.CreateWebhookRequest{URL: "ftp://example.com"}},
+		{name: "Unknown event", request: domain.CreateWebhookRequest{URL: "https://example.com", Events: []domain.VendorEventType{"renamed"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+			}
+		})
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"created"}`)
+
+	signature := domain.SignWebhook("secret", timestamp, body)
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Len(t, signature, 7+64)
+	assert.Equal(t, signature, domain.SignWebhook("secret", timestamp, body))
+	assert.NotEqual(t, signature, domain.SignWebhook("other", timestamp, body))
+	assert.NotEqual(t, signature, domain.SignWebhook("secret", timestamp.Add(time.Second), body))
+}
@@ -0,0 +1,309 @@
+package migrations
+
+import (
+	"context"
+	"sort"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// legacyVendorCollection is where every vendor was stored before vendors were
+// split into one collection per type.
+const legacyVendorCollection = "vendors"
+
+// All returns the migrations of this service in the order they are applied.
+// Applied migrations must never be edited or reordered; add a new version
+// instead.
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "move vendors from the legacy collection into per-type collections",
+			Up:          splitLegacyVendors,
+		},
+		{
+			Version:     2,
+			Description: "backfill the version of vendors created before versioning",
+			Up:          backfillVendorVersions,
+		},
+		{
+			Version:     3,
+			Description: "create text search and location indexes on vendors",
+			Up:          createVendorSearchIndexes,
+		},
+		{
+			Version:     4,
+			Description: "create tag, category and trash indexes on vendors",
+			Up:          createVendorFilterIndexes,
+		},
+		{
+			Version:     5,
+			Description: "create the vendor history lookup index",
+			Up:          createHistoryIndexes,
+		},
+		{
+			Version:     6,
+			Description: "backfill vendor timestamps and create sort indexes",
+			Up:          createVendorSortIndexes,
+		},
+		{
+			Version:     7,
+			Description: "create webhook delivery queue and log indexes",
+			Up:          createDeliveryIndexes,
+		},
+		{
+			Version:     8,
+			Description: "create the vendor outbox and its relay index",
+			Up:          createOutboxIndexes,
+		},
+		{
+			Version:     9,
+			Description: "create the vendor alias index",
+			Up:          createVendorAliasIndexes,
+		},
+		{
+			Version:     10,
+			Description: "backfill and index the duplicate keys of vendors",
+			Up:          createVendorDuplicateKeyIndexes,
+		},
+	}
+}
+
+// splitLegacyVendors copies each vendor of a known type into the collection
+// of its type and then removes it from the legacy collection. Vendors whose
+// type is unknown stay where they are. A vendor already present in its new
+// collection is kept, so the migration can be rerun after a failure.
+func splitLegacyVendors(ctx context.Context, target Target) error {
+	legacy := target.DB.Collection(legacyVendorCollection)
+
+	types := make([]string, 0, len(target.VendorCollections))
+	for vendorType := range target.VendorCollections {
+		types = append(types, vendorType)
+	}
+	sort.Strings(types)
+
+	for _, vendorType := range types {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"type": vendorType}}},
+			{{Key: "$merge", Value: bson.M{
+				"into":           target.VendorCollections[vendorType],
+				"on":             "_id",
+				"whenMatched":    "keepExisting",
+				"whenNotMatched": "insert",
+			}}},
+		}
+
+		cursor, err := legacy.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		cursor.Close(ctx)
+
+		if _, err := legacy.DeleteMany(ctx, bson.M{"type": vendorType}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func backfillVendorVersions(ctx context.Context, target Target) error {
+	for _, collection := range target.Vendors() {
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"version": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"version": 1}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createVendorSearchIndexes(ctx context.Context, target Target) error {
+	textKeys := bson.D{}
+	weights := bson.D{}
+	for _, field := range []string{"name", "location", "tags", "categories"} {
+		textKeys = append(textKeys, bson.E{Key: field, Value: "text"})
+		weights = append(weights, bson.E{Key: field, Value: domain.TextSearchWeights[field]})
+	}
+
+	return createIndexes(ctx, target.Vendors(), []mongo.IndexModel{
+		{
+			Keys:    textKeys,
+			Options: options.Index().SetName("vendor_text_search").SetWeights(weights),
+		},
+		{
+			Keys:    bson.D{{Key: "coordinates", Value: "2dsphere"}},
+			Options: options.Index().SetName("vendor_coordinates"),
+		},
+	})
+}
+
+func createVendorFilterIndexes(ctx context.Context, target Target) error {
+	return createIndexes(ctx, target.Vendors(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("vendor_tags"),
+		},
+		{
+			Keys:    bson.D{{Key: "categories", Value: 1}},
+			Options: options.Index().SetName("vendor_categories"),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName("vendor_deleted_at"),
+		},
+	})
+}
+
+func createHistoryIndexes(ctx context.Context, target Target) error {
+	return createIndexes(ctx, []*mongo.Collection{target.DB.Collection(target.HistoryCollection)}, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "vendor_id", Value: 1}, {Key: "revision", Value: 1}},
+		},
+	})
+}
+
+// createVendorSortIndexes dates vendors created before timestamps were kept
+// by the creation time in their ObjectID, then indexes every sortable field.
+// Each index ends in _id, the tiebreaker of every sort, and can be walked in
+// either direction for a sort on its field alone.
+func createVendorSortIndexes(ctx context.Context, target Target) error {
+	for _, collection := range target.Vendors() {
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"created_at": bson.M{"$exists": false}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$_id"}}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = collection.UpdateMany(ctx,
+			bson.M{"updated_at": bson.M{"$exists": false}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"updated_at": "$created_at"}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	indexes := make([]mongo.IndexModel, len(domain.SortableFields))
+	for i, field := range domain.SortableFields {
+		indexes[i] = mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("vendor_sort_" + field),
+		}
+	}
+
+	return createIndexes(ctx, target.Vendors(), indexes)
+}
+
+// createDeliveryIndexes indexes webhook deliveries for the dispatcher, which
+// looks for pending deliveries by due time, and for the delivery log of each
+// webhook, newest first.
+func createDeliveryIndexes(ctx context.Context, target Target) error {
+	return createIndexes(ctx, []*mongo.Collection{target.DB.Collection(target.DeliveryCollection)}, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "status", Value: 1}, {Key: "_id", Value: -1}},
+		},
+	})
+}
+
+// createOutboxIndexes indexes the outbox for the relay, which looks for
+// available records. Creating the index also creates the collection, which
+// older servers can't do inside the transactions that write to it.
+func createOutboxIndexes(ctx context.Context, target Target) error {
+	return createIndexes(ctx, []*mongo.Collection{target.DB.Collection(target.OutboxCollection)}, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "available_at", Value: 1}, {Key: "_id", Value: 1}},
+		},
+	})
+}
+
+// createVendorAliasIndexes indexes the IDs of merged vendors, which a lookup
+// by ID falls back to. Only merged into vendors have aliases, so the index is
+// sparse.
+func createVendorAliasIndexes(ctx context.Context, target Target) error {
+	return createIndexes(ctx, target.Vendors(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "aliases", Value: 1}},
+			Options: options.Index().SetName("vendor_aliases").SetSparse(true),
+		},
+	})
+}
+
+// duplicateKeyBatch is how many vendors get their duplicate keys written in
+// one bulk write.
+const duplicateKeyBatch = 500
+
+// createVendorDuplicateKeyIndexes stores the duplicate keys of every vendor
+// that lacks them, then indexes the keys so that vendors sharing one can be
+// grouped without reading the rest.
+func createVendorDuplicateKeyIndexes(ctx context.Context, target Target) error {
+	for _, collection := range target.Vendors() {
+		cursor, err := collection.Find(ctx, bson.M{"duplicate_keys": bson.M{"$exists": false}})
+		if err != nil {
+			return err
+		}
+
+		var models []mongo.WriteModel
+		flush := func() error {
+			if len(models) == 0 {
+				return nil
+			}
+			_, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+			models = models[:0]
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var vendor domain.GetVendorResponse
+			if err := cursor.Decode(&vendor); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": vendor.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"duplicate_keys": domain.DuplicateKeys(&vendor)}}))
+			if len(models) == duplicateKeyBatch {
+				if err := flush(); err != nil {
+					cursor.Close(ctx)
+					return err
+				}
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	return createIndexes(ctx, target.Vendors(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "duplicate_keys", Value: 1}},
+			Options: options.Index().SetName("vendor_duplicate_keys"),
+		},
+	})
+}
+
+// createIndexes creates the same indexes on every collection. Creating an
+// index that already exists with the same definition is a no-op.
+func createIndexes(ctx context.Context, collections []*mongo.Collection, indexes []mongo.IndexModel) error {
+	for _, collection := range collections {
+		if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
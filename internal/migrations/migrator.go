@@ -0,0 +1,341 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+	"vendors/internal/config"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// MetadataCollection records one document per applied migration.
+	MetadataCollection = "migrations"
+
+	// LockCollection holds the single document that marks an instance as
+	// migrating.
+	LockCollection = "migrations_lock"
+
+	lockID            = "migrations"
+	lockRetryInterval = time.Second
+)
+
+var (
+	ErrLocked            = errors.New("migrations are locked by another instance")
+	ErrLockLost          = errors.New("migration lock lost")
+	ErrInvalidMigrations = errors.New("invalid migration list")
+)
+
+// Target is the database a migration runs against, with the collection names
+// taken from the configuration.
+type Target struct {
+	DB *mongo.Database
+
+	// VendorCollections maps each vendor type to its collection.
+	VendorCollections  map[string]string
+	HistoryCollection  string
+	DeliveryCollection string
+	OutboxCollection   string
+}
+
+func NewTarget(db *mongo.Database, cfg config.MongoDB) Target {
+	return Target{
+		DB:                 db,
+		VendorCollections:  cfg.VendorCollections(),
+		HistoryCollection:  cfg.HistoryCollection,
+		DeliveryCollection: cfg.DeliveryCollection,
+		OutboxCollection:   cfg.OutboxCollection,
+	}
+}
+
+// Vendors returns the collection of every vendor type, ordered by type.
+func (t Target) Vendors() []*mongo.Collection {
+	types := make([]string, 0, len(t.VendorCollections))
+	for vendorType := range t.VendorCollections {
+		types = append(types, vendorType)
+	}
+	sort.Strings(types)
+
+	collections := make([]*mongo.Collection, 0, len(types))
+	for _, vendorType := range types {
+		collections = append(collections, t.DB.Collection(t.VendorCollections[vendorType]))
+	}
+	return collections
+}
+
+// Migration is one versioned change to the database. Up must be safe to run
+// again if an earlier attempt failed halfway, because the version is only
+// recorded once Up has returned successfully.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, target Target) error
+}
+
+// Record is the metadata stored for an applied migration.
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Status describes a known migration and, if it has run, when.
+type Status struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+type Migrator struct {
+	target     Target
+	migrations []Migration
+	settings   config.Migrations
+	metadata   *mongo.Collection
+	locks      *mongo.Collection
+	owner      string
+}
+
+func NewMigrator(target Target, migrations []Migration, settings config.Migrations) *Migrator {
+	hostname, _ := os.Hostname()
+
+	return &Migrator{
+		target:     target,
+		migrations: migrations,
+		settings:   settings,
+		metadata:   target.DB.Collection(MetadataCollection),
+		locks:      target.DB.Collection(LockCollection),
+		owner:      fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()),
+	}
+}
+
+// Run applies every pending migration in version order while holding the
+// migration lock, and returns the migrations it applied. The lock is renewed
+// while migrations run; if it can't be, the run stops with ErrLockLost before
+// another instance could take the lock over.
+func (m *Migrator) Run(ctx context.Context) ([]Migration, error) {
+	if err := Validate(m.migrations); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(context.WithoutCancel(ctx))
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	renewing := make(chan struct{})
+	go func() {
+		defer close(renewing)
+		m.keepLock(ctx, expiresAt, cancel)
+	}()
+	defer func() {
+		cancel(nil)
+		<-renewing
+	}()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, migration := range Pending(m.migrations, applied) {
+		slog.Info("applying migration", slog.Int("version", migration.Version), slog.String("description", migration.Description))
+
+		start := time.Now()
+		if err := migration.Up(ctx, m.target); err != nil {
+			if errors.Is(context.Cause(ctx), ErrLockLost) {
+				err = ErrLockLost
+			}
+			slog.Error("migration failed", slog.Int("version", migration.Version), utils.Err(err))
+			return ran, fmt.Errorf("migration %d: %w", migration.Version, err)
+		}
+
+		if err := context.Cause(ctx); err != nil {
+			return ran, fmt.Errorf("migration %d: %w", migration.Version, err)
+		}
+
+		record := Record{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now().UTC()}
+		if _, err := m.metadata.InsertOne(ctx, record); err != nil {
+			slog.Error("error recording migration", slog.Int("version", migration.Version), utils.Err(err))
+			return ran, err
+		}
+
+		slog.Info("migration applied", slog.Int("version", migration.Version), slog.Duration("took", time.Since(start)))
+		ran = append(ran, migration)
+	}
+
+	return ran, nil
+}
+
+// Status lists every known migration with the time it was applied, if any.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := m.metadata.Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("error reading applied migrations", utils.Err(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// lock takes the migration lock, waiting up to the configured time for
+// another instance to release it. A lock whose holder died is taken over once
+// it has expired. The upsert only matches a free or expired lock, so while
+// another instance holds it the insert fails on the duplicate _id. It returns
+// when the lock expires unless renewed.
+func (m *Migrator) lock(ctx context.Context) (time.Time, error) {
+	deadline := time.Now().Add(m.settings.LockWait)
+
+	for {
+		now := time.Now().UTC()
+		filter := bson.M{
+			"_id": lockID,
+			"$or": []bson.M{
+				{"expires_at": bson.M{"$lte": now}},
+				{"owner": m.owner},
+			},
+		}
+		expiresAt := now.Add(m.settings.LockTTL)
+		update := bson.M{"$set": bson.M{
+			"owner":       m.owner,
+			"acquired_at": now,
+			"expires_at":  expiresAt,
+		}}
+
+		_, err := m.locks.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err == nil {
+			return expiresAt, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			slog.Error("error taking migration lock", utils.Err(err))
+			return time.Time{}, err
+		}
+		if time.Now().After(deadline) {
+			return time.Time{}, ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Time{}, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// keepLock extends the lock by its TTL every third of the TTL until ctx is
+// done. Failed renewals are retried on the next tick; once the lock has
+// expired without being renewed, or another instance has taken it, the run is
+// cancelled with ErrLockLost.
+func (m *Migrator) keepLock(ctx context.Context, expiresAt time.Time, cancel context.CancelCauseFunc) {
+	interval := m.settings.LockTTL / 3
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now().UTC()
+		renewCtx, cancelRenew := context.WithDeadline(ctx, expiresAt)
+		result, err := m.locks.UpdateOne(renewCtx,
+			bson.M{"_id": lockID, "owner": m.owner},
+			bson.M{"$set": bson.M{"expires_at": now.Add(m.settings.LockTTL)}},
+		)
+		cancelRenew()
+		switch {
+		case err == nil && result.MatchedCount == 1:
+			expiresAt = now.Add(m.settings.LockTTL)
+		case err == nil:
+			slog.Error("migration lock was taken over by another instance")
+			cancel(ErrLockLost)
+			return
+		case ctx.Err() != nil:
+			return
+		case !time.Now().Before(expiresAt):
+			slog.Error("migration lock expired before it could be renewed", utils.Err(err))
+			cancel(ErrLockLost)
+			return
+		default:
+			slog.Warn("error renewing migration lock", utils.Err(err))
+		}
+	}
+}
+
+func (m *Migrator) unlock(ctx context.Context) {
+	if _, err := m.locks.DeleteOne(ctx, bson.M{"_id": lockID, "owner": m.owner}); err != nil {
+		slog.Error("error releasing migration lock", utils.Err(err))
+	}
+}
+
+// Validate checks that versions are positive and strictly increasing, so the
+// list order is the order migrations are applied in.
+func Validate(migrations []Migration) error {
+	previous := 0
+	for _, migration := range migrations {
+		if migration.Version <= previous {
+			return fmt.Errorf("%w: version %d follows %d", ErrInvalidMigrations, migration.Version, previous)
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("%w: version %d has no Up", ErrInvalidMigrations, migration.Version)
+		}
+		previous = migration.Version
+	}
+	return nil
+}
+
+// Pending returns the migrations that have not been applied yet, in order.
+func Pending(migrations []Migration, applied map[int]Record) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,99 @@
+package migrations_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func noop(context.Context, migrations.Target) error { return nil }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []migrations.Migration
+		wantErr    bool
+	}{
+		{name: "Registered migrations", migrations: migrations.All()},
+		{name: "Empty", migrations: nil},
+		{name: "Increasing", migrations: []migrations.Migration{{Version: 1, Up: noop}, {Version: 3, Up: noop}}},
+		{name: "Duplicate version", migrations: []migrations.Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}}, wantErr: true},
+		{name: "Out of order", migrations: []migrations.Migration{{Version: 2, Up: noop}, {Version: 1, Up: noop}}, wantErr: true},
+		{name: "Zero version", migrations: []migrations.Migration{{Version: 0, Up: noop}}, wantErr: true},
+		{name: "Missing Up", migrations: []migrations.Migration{{Version: 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrations.Validate(tt.migrations)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, migrations.ErrInvalidMigrations)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	all := []migrations.Migration{{Version: 1, Up: noop}, {Version: 2, Up: noop}, {Version: 3, Up: noop}}
+
+	pending := migrations.Pending(all, map[int]migrations.Record{2: {Version: 2}})
+
+	var versions []int
+	for _, migration := range pending {
+		versions = append(versions, migration.Version)
+	}
+	assert.Equal(t, []int{1, 3}, versions)
+	assert.Empty(t, migrations.Pending(all, map[int]migrations.Record{1: {}, 2: {}, 3: {}}))
+}
+
+func TestRunRenewsLock(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	lockSettings := config.Migrations{LockTTL: 30 * time.Millisecond, LockWait: time.Second}
+	updated := func(n int) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+	noneApplied := mtest.CreateCursorResponse(0, "test.migrations", mtest.FirstBatch)
+
+	mt.Run("Outlasting the TTL", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(1), noneApplied)
+		for i := 0; i < 20; i++ {
+			mt.AddMockResponses(updated(1))
+		}
+
+		slow := migrations.Migration{Version: 1, Up: func(ctx context.Context, _ migrations.Target) error {
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}}
+
+		ran, err := migrations.NewMigrator(migrations.Target{DB: mt.DB}, []migrations.Migration{slow}, lockSettings).Run(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, ran, 1)
+	})
+
+	mt.Run("Taken over", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(1), noneApplied, updated(0), updated(0))
+
+		blocking := migrations.Migration{Version: 1, Up: func(ctx context.Context, _ migrations.Target) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}}
+
+		ran, err := migrations.NewMigrator(migrations.Target{DB: mt.DB}, []migrations.Migration{blocking}, lockSettings).Run(context.Background())
+		assert.ErrorIs(t, err, migrations.ErrLockLost)
+		assert.Empty(t, ran)
+	})
+}
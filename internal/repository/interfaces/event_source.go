@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"vendors/internal/domain"
+)
+
+//go:generate mockgen -source=event_source.go -destination=../mocks/event_source_mock.go
+
+// VendorEventSource streams changes to vendors as they happen.
+type VendorEventSource interface {
+	// Subscribe delivers the events that follow lastEventID, or only new
+	// events when it is empty, until ctx is done. The channel is closed when
+	// the subscription ends, which may happen early when a subscriber can't
+	// keep up; it can then subscribe again from the last event it received.
+	Subscribe(ctx context.Context, lastEventID string) (<-chan *domain.VendorEvent, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=history_repository.go -destination=../mocks/history_repository_mock.go
+
+type HistoryRepository interface {
+	AddEntry(ctx context.Context, entry *domain.VendorHistoryEntry) error
+	GetEntries(ctx context.Context, vendorID primitive.ObjectID) ([]*domain.VendorHistoryEntry, error)
+	GetEntry(ctx context.Context, vendorID primitive.ObjectID, revision int64) (*domain.VendorHistoryEntry, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=outbox_repository.go -destination=../mocks/outbox_repository_mock.go
+
+// OutboxRepository reads the outbox that vendor writes add their events to.
+type OutboxRepository interface {
+	// ClaimOutboxRecords returns up to limit records available by now, oldest
+	// first, and postpones them by lease, so that no other relay picks them
+	// up while they are being published.
+	ClaimOutboxRecords(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxRecord, error)
+	// DeleteOutboxRecord removes a record whose event has been published.
+	DeleteOutboxRecord(ctx context.Context, id primitive.ObjectID) error
+	// ReleaseOutboxRecord stores the attempts, last error and next available
+	// time of a record whose event could not be published.
+	ReleaseOutboxRecord(ctx context.Context, record *domain.OutboxRecord) error
+}
@@ -1,20 +1,54 @@
 package repository
 
 import (
+	"context"
+	"time"
 	"vendors/internal/domain"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-//go:generate mockgen -source=vendor_repository.go -destination=mocks/vendor_repository_mock.go
+//go:generate mockgen -source=vendor_repository.go -destination=../mocks/vendor_repository_mock.go
 
 type VendorRepository interface {
-	GetAllVendors(page, pageSize int) ([]*domain.GetVendorResponse, error)
-	GetTotalVendorsCount() (int, error)
-	GetVendorByID(id primitive.ObjectID) (*domain.GetVendorResponse, error)
-	CreateVendor(request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error)
-	UpdateVendor(id primitive.ObjectID, request *domain.UpdateVendorRequest) (*domain.UpdateVendorResponse, error)
-	DeleteVendor(id primitive.ObjectID) error
-	SearchVendors(query string, page int, pageSize int) ([]*domain.GetVendorResponse, error)
-	FilterVendorsByTags(tags []string, page int, pageSize int) ([]*domain.GetVendorResponse, error)
+	GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error)
+	GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error)
+	GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error)
+	// GetVendorByAlias returns the live vendor that the vendor with the given
+	// ID was merged into, or nil when there is none.
+	GetVendorByAlias(ctx context.Context, alias primitive.ObjectID) (*domain.GetVendorResponse, error)
+	CreateVendor(ctx context.Context, request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error)
+	UpdateVendor(ctx context.Context, id primitive.ObjectID, request *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	PatchVendor(ctx context.Context, id primitive.ObjectID, patch *domain.VendorPatch, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	// MergeVendor overwrites the vendor with the merge of it and its
+	// duplicates and adds their IDs to its aliases, in one write.
+	MergeVendor(ctx context.Context, id primitive.ObjectID, merged *domain.UpdateVendorRequest, aliases []primitive.ObjectID, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	// FindDuplicateCandidates returns the live vendors, of vendorType or of
+	// any type, that domain.FindDuplicates would compare with another one:
+	// those sharing a duplicate key with other vendors of their type.
+	FindDuplicateCandidates(ctx context.Context, vendorType string) ([]*domain.GetVendorResponse, error)
+	DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error
+	GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error)
+	GetDeletedVendorsCount(ctx context.Context) (int, error)
+	RestoreVendor(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedVendors(ctx context.Context, deletedBefore time.Time) (int, error)
+	SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, error)
+	CountSearchVendors(ctx context.Context, query domain.SearchQuery, vendorType string) (int, error)
+	FindVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, error)
+	CountVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) (int, error)
+	FindVendorsInArea(ctx context.Context, area *domain.GeoPolygon, limit int) ([]*domain.MapVendor, error)
+	ClusterVendorsInArea(ctx context.Context, area *domain.GeoPolygon, cellSize float64) ([]*domain.MapCluster, error)
+	GetVendorsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, error)
+	GetVendorsByNames(ctx context.Context, names []string) ([]*domain.GetVendorResponse, error)
+	BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) ([]domain.BulkItemResult, error)
+	BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) ([]domain.BulkItemResult, error)
+	BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) ([]domain.BulkItemResult, error)
+	GetVendorFacets(ctx context.Context, fields []string, vendorType string, limit int) (domain.Facets, error)
+	FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, error)
+	CountFilteredVendors(ctx context.Context, filter domain.VendorFilter) (int, error)
+	// ExportVendors hands the live vendors matching filter to emit one at a
+	// time, in sort order, and stops at the first error emit returns.
+	ExportVendors(ctx context.Context, filter domain.VendorFilter, order domain.Sort, emit func(*domain.GetVendorResponse) error) error
+	FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, error)
+	CountVendorsByTags(ctx context.Context, tags domain.ValueMatch, vendorType string) (int, error)
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=webhook_repository.go -destination=../mocks/webhook_repository_mock.go
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	GetWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	GetWebhook(ctx context.Context, id primitive.ObjectID) (*domain.Webhook, error)
+	// DeleteWebhook removes a webhook together with its deliveries.
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) error
+
+	AddDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries due by now
+	// and postpones them by lease, so that no other dispatcher picks them up
+	// while they are being sent.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*domain.WebhookDelivery, error)
+	// GetDeliveries returns the newest deliveries of a webhook, optionally
+	// only those with the given status.
+	GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r *MemoryVendorRepository) GetVendorsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var vendors []*domain.GetVendorResponse
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		vendor, ok := r.vendors[id]
+		if !ok || vendor.DeletedAt != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		vendors = append(vendors, copyVendor(vendor))
+	}
+	return vendors, nil
+}
+
+func (r *MemoryVendorRepository) GetVendorsByNames(ctx context.Context, names []string) ([]*domain.GetVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var vendors []*domain.GetVendorResponse
+	for _, vendor := range r.vendors {
+		if vendor.DeletedAt == nil && wanted[vendor.Name] {
+			vendors = append(vendors, copyVendor(vendor))
+		}
+	}
+	return vendors, nil
+}
+
+// BulkCreateVendors stores every vendor under a single lock. Inserts can't
+// fail in memory, so every item succeeds.
+func (r *MemoryVendorRepository) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) ([]domain.BulkItemResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.BulkItemResult, len(vendors))
+	for i, vendor := range vendors {
+		stored := r.insert(vendor)
+		results[i] = domain.BulkItemResult{Index: i, ID: stored.ID, Version: stored.Version, Status: domain.BulkItemOK}
+	}
+	return results, nil
+}
+
+func (r *MemoryVendorRepository) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) ([]domain.BulkItemResult, error) {
+	ids := make([]primitive.ObjectID, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+	}
+
+	return r.bulk(ctx, ids, ordered, func(i int) error {
+		vendor, err := r.live(updates[i].ID, updates[i].Version)
+		if err != nil {
+			return err
+		}
+		replace(vendor, updates[i].Vendor)
+		return nil
+	})
+}
+
+func (r *MemoryVendorRepository) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) ([]domain.BulkItemResult, error) {
+	ids := make([]primitive.ObjectID, len(deletes))
+	for i, del := range deletes {
+		ids[i] = del.ID
+	}
+
+	now := time.Now().UTC()
+	return r.bulk(ctx, ids, ordered, func(i int) error {
+		vendor, err := r.live(deletes[i].ID, deletes[i].Version)
+		if err != nil {
+			return err
+		}
+		trash(vendor, now)
+		return nil
+	})
+}
+
+// bulk applies write to the vendor of every item under a single lock,
+// stopping at the first failure when ordered.
+func (r *MemoryVendorRepository) bulk(ctx context.Context, ids []primitive.ObjectID, ordered bool, write func(i int) error) ([]domain.BulkItemResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.BulkItemResult, len(ids))
+	for i, id := range ids {
+		results[i] = domain.BulkItemResult{Index: i, ID: id, Status: domain.BulkItemOK}
+		if ordered && i > 0 && results[i-1].Status != domain.BulkItemOK {
+			results[i].Skip()
+			continue
+		}
+
+		if err := write(i); err != nil {
+			results[i].Fail(err)
+			continue
+		}
+		results[i].Version = r.vendors[id].Version
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MemoryHistoryRepository struct {
+	mu      sync.RWMutex
+	entries map[primitive.ObjectID][]*domain.VendorHistoryEntry
+}
+
+func NewMemoryHistoryRepository() *MemoryHistoryRepository {
+	return &MemoryHistoryRepository{
+		entries: make(map[primitive.ObjectID][]*domain.VendorHistoryEntry),
+	}
+}
+
+func (r *MemoryHistoryRepository) AddEntry(ctx context.Context, entry *domain.VendorHistoryEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored := *entry
+	stored.ID = primitive.NewObjectID()
+	entry.ID = stored.ID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := append(r.entries[entry.VendorID], &stored)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Revision < entries[j].Revision
+	})
+	r.entries[entry.VendorID] = entries
+
+	return nil
+}
+
+func (r *MemoryHistoryRepository) GetEntries(ctx context.Context, vendorID primitive.ObjectID) ([]*domain.VendorHistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []*domain.VendorHistoryEntry
+	for _, entry := range r.entries[vendorID] {
+		c := *entry
+		entries = append(entries, &c)
+	}
+	return entries, nil
+}
+
+func (r *MemoryHistoryRepository) GetEntry(ctx context.Context, vendorID primitive.ObjectID, revision int64) (*domain.VendorHistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.entries[vendorID] {
+		if entry.Revision == revision {
+			c := *entry
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrRevisionNotFound
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r *MemoryVendorRepository) GetVendorByAlias(ctx context.Context, alias primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt == nil && slices.Contains(vendor.Aliases, alias) {
+			return copyVendor(vendor), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryVendorRepository) MergeVendor(ctx context.Context, id primitive.ObjectID, merged *domain.UpdateVendorRequest, aliases []primitive.ObjectID, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vendor, err := r.live(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	replace(vendor, merged)
+	for _, alias := range aliases {
+		if !slices.Contains(vendor.Aliases, alias) {
+			vendor.Aliases = append(vendor.Aliases, alias)
+		}
+	}
+
+	u := domain.UpdateVendorResponse(*copyVendor(vendor))
+	return &u, nil
+}
+
+func (r *MemoryVendorRepository) FindDuplicateCandidates(ctx context.Context, vendorType string) ([]*domain.GetVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var vendors []*domain.GetVendorResponse
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt == nil && (vendorType == "" || vendor.Type == vendorType) {
+			vendors = append(vendors, vendor)
+		}
+	}
+
+	candidates := domain.DuplicateCandidates(vendors)
+	for i, vendor := range candidates {
+		candidates[i] = copyVendor(vendor)
+	}
+	return candidates, nil
+}
@@ -0,0 +1,714 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryVendorRepository keeps vendors in process memory. It mirrors the
+// behaviour of the MongoDB repository closely enough to run the API locally
+// or in tests without a database.
+type MemoryVendorRepository struct {
+	mu      sync.RWMutex
+	order   []primitive.ObjectID
+	vendors map[primitive.ObjectID]*domain.GetVendorResponse
+}
+
+func NewMemoryVendorRepository() *MemoryVendorRepository {
+	return &MemoryVendorRepository{
+		vendors: make(map[primitive.ObjectID]*domain.GetVendorResponse),
+	}
+}
+
+func (r *MemoryVendorRepository) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.find(ctx, opts, func(*domain.GetVendorResponse) bool { return true })
+}
+
+func (r *MemoryVendorRepository) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
+	return r.count(ctx, vendorType, func(*domain.GetVendorResponse) bool { return true })
+}
+
+func (r *MemoryVendorRepository) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vendor, ok := r.vendors[id]
+	if !ok || vendor.DeletedAt != nil {
+		return nil, nil
+	}
+	return copyVendor(vendor), nil
+}
+
+func (r *MemoryVendorRepository) CreateVendor(ctx context.Context, vendor *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	stored := r.insert(vendor)
+	r.mu.Unlock()
+
+	c := domain.CreateVendorResponse(*copyVendor(stored))
+	return &c, nil
+}
+
+// insert stores a new vendor. Callers must hold the write lock.
+func (r *MemoryVendorRepository) insert(vendor *domain.CreateVendorRequest) *domain.GetVendorResponse {
+	now := time.Now().UTC()
+	stored := &domain.GetVendorResponse{
+		ID:             primitive.NewObjectID(),
+		Cover:          vendor.Cover,
+		Type:           vendor.Type,
+		Name:           vendor.Name,
+		Location:       vendor.Location,
+		PhoneNumbers:   copyStrings(vendor.PhoneNumbers),
+		Websites:       copyStrings(vendor.Websites),
+		SocialNetworks: copyStrings(vendor.SocialNetworks),
+		Media:          copyStrings(vendor.Media),
+		Tags:           copyStrings(vendor.Tags),
+		Categories:     copyStrings(vendor.Categories),
+		Coordinates:    copyPoint(vendor.Coordinates),
+		Version:        1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	r.vendors[stored.ID] = stored
+	r.order = append(r.order, stored.ID)
+	return stored
+}
+
+func (r *MemoryVendorRepository) UpdateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vendor, err := r.live(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	replace(vendor, update)
+
+	u := domain.UpdateVendorResponse(*copyVendor(vendor))
+	return &u, nil
+}
+
+func (r *MemoryVendorRepository) PatchVendor(ctx context.Context, id primitive.ObjectID, patch *domain.VendorPatch, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vendor, err := r.live(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := domain.UpdateVendorRequest(patch.Apply(domain.SnapshotOf(domain.CommonVendorResponse(*vendor))))
+	replace(vendor, &patched)
+
+	u := domain.UpdateVendorResponse(*copyVendor(vendor))
+	return &u, nil
+}
+
+// replace overwrites every field of a stored vendor and bumps its version.
+// Callers must hold the write lock.
+func replace(vendor *domain.GetVendorResponse, update *domain.UpdateVendorRequest) {
+	vendor.Cover = update.Cover
+	vendor.Type = update.Type
+	vendor.Name = update.Name
+	vendor.Location = update.Location
+	vendor.PhoneNumbers = copyStrings(update.PhoneNumbers)
+	vendor.Websites = copyStrings(update.Websites)
+	vendor.SocialNetworks = copyStrings(update.SocialNetworks)
+	vendor.Media = copyStrings(update.Media)
+	vendor.Tags = copyStrings(update.Tags)
+	vendor.Categories = copyStrings(update.Categories)
+	vendor.Coordinates = copyPoint(update.Coordinates)
+	vendor.Version++
+	vendor.UpdatedAt = time.Now().UTC()
+}
+
+func (r *MemoryVendorRepository) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vendor, err := r.live(id, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	trash(vendor, time.Now().UTC())
+
+	return nil
+}
+
+// trash moves a stored vendor to the trash and bumps its version. Callers
+// must hold the write lock.
+func trash(vendor *domain.GetVendorResponse, now time.Time) {
+	vendor.DeletedAt = &now
+	vendor.Version++
+	vendor.UpdatedAt = now
+}
+
+// live returns the stored vendor with the given ID if it is not in the trash
+// and, unless expectedVersion is zero, is still at that version. Callers must
+// hold the write lock.
+func (r *MemoryVendorRepository) live(id primitive.ObjectID, expectedVersion int64) (*domain.GetVendorResponse, error) {
+	vendor, ok := r.vendors[id]
+	if !ok || vendor.DeletedAt != nil {
+		return nil, domain.ErrVendorNotFound
+	}
+
+	if expectedVersion > 0 && vendor.Version != expectedVersion {
+		return nil, domain.ErrVersionConflict
+	}
+
+	return vendor, nil
+}
+
+func (r *MemoryVendorRepository) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.scan(ctx, opts, func(vendor *domain.GetVendorResponse) bool {
+		return vendor.DeletedAt != nil
+	})
+}
+
+func (r *MemoryVendorRepository) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := 0
+	for _, vendor := range r.vendors {
+		if vendor.DeletedAt != nil {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (r *MemoryVendorRepository) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vendor, ok := r.vendors[id]
+	if !ok || vendor.DeletedAt == nil {
+		return domain.ErrVendorNotFound
+	}
+
+	vendor.DeletedAt = nil
+	vendor.Version++
+	vendor.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *MemoryVendorRepository) PurgeDeletedVendors(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	order := r.order[:0]
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt != nil && !vendor.DeletedAt.After(deletedBefore) {
+			delete(r.vendors, id)
+			purged++
+			continue
+		}
+		order = append(order, id)
+	}
+	r.order = order
+
+	return purged, nil
+}
+
+// SearchVendors approximates MongoDB's $text search in text mode: a vendor
+// matches when any query term appears as a whole word in one of the indexed
+// fields, and its score is the weighted number of matches. Stemming and stop
+// words are not emulated. The pattern modes match the name like $regex does.
+func (r *MemoryVendorRepository) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if query.Mode != domain.SearchModeText {
+		match, err := searchMatch(query)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := r.find(ctx, opts, match)
+		if err != nil {
+			return nil, err
+		}
+
+		results := &domain.SearchVendorList{NextCursor: list.NextCursor, Facets: list.Facets}
+		for _, vendor := range list.Vendors {
+			results.Vendors = append(results.Vendors, &domain.SearchVendorResponse{GetVendorResponse: *vendor})
+		}
+		return results, nil
+	}
+
+	terms := domain.SearchTerms(query.Text)
+
+	r.mu.RLock()
+	var matches []*domain.SearchVendorResponse
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt != nil || (opts.Type != "" && vendor.Type != opts.Type) {
+			continue
+		}
+		score := domain.TextScore(vendor, terms)
+		if score == 0 {
+			continue
+		}
+		matches = append(matches, &domain.SearchVendorResponse{
+			GetVendorResponse: *copyVendor(vendor),
+			Score:             score,
+		})
+	}
+	r.mu.RUnlock()
+
+	if len(opts.Sort) > 0 {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return opts.Sort.Compare(&matches[i].GetVendorResponse, &matches[j].GetVendorResponse) < 0
+		})
+	} else {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Score > matches[j].Score
+		})
+	}
+
+	decode, after := decodeKeys(1), func(vendor *domain.SearchVendorResponse, cursor domain.Cursor) bool {
+		score, _ := cursor.Values[0].(float64)
+		return vendor.Score < score || (vendor.Score == score && afterID(vendor.ID, cursor.ID))
+	}
+	if len(opts.Sort) > 0 {
+		decode, after = opts.Sort.DecodeCursor, func(vendor *domain.SearchVendorResponse, cursor domain.Cursor) bool {
+			return opts.Sort.After(&vendor.GetVendorResponse, cursor)
+		}
+	}
+
+	page, more, err := window(matches, opts, decode, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var facets domain.Facets
+	if len(opts.Facets) > 0 {
+		vendors := make([]*domain.GetVendorResponse, len(matches))
+		for i, match := range matches {
+			vendors[i] = &match.GetVendorResponse
+		}
+		facets = domain.CountFacets(opts.Facets, vendors, opts.FacetLimit)
+	}
+
+	results := &domain.SearchVendorList{Vendors: page, Facets: facets}
+	if more {
+		last := page[len(page)-1]
+		values := []interface{}{last.Score}
+		if len(opts.Sort) > 0 {
+			values = opts.Sort.Values(&last.GetVendorResponse)
+		}
+		results.NextCursor = domain.EncodeCursor(domain.Cursor{Values: values, ID: last.ID})
+	}
+	return results, nil
+}
+
+func (r *MemoryVendorRepository) FindVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, error) {
+	matches, err := r.near(ctx, point, radiusMeters)
+	if err != nil {
+		return nil, err
+	}
+
+	page, more, err := window(matches, opts, decodeKeys(1), func(vendor *domain.NearbyVendorResponse, cursor domain.Cursor) bool {
+		distance, _ := cursor.Values[0].(float64)
+		return vendor.Distance > distance || (vendor.Distance == distance && afterID(vendor.ID, cursor.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := &domain.NearbyVendorList{Vendors: page}
+	if more {
+		last := page[len(page)-1]
+		results.NextCursor = domain.EncodeCursor(domain.Cursor{Values: []interface{}{last.Distance}, ID: last.ID})
+	}
+	return results, nil
+}
+
+func (r *MemoryVendorRepository) CountSearchVendors(ctx context.Context, query domain.SearchQuery, vendorType string) (int, error) {
+	match, err := searchMatch(query)
+	if err != nil {
+		return 0, err
+	}
+	return r.count(ctx, vendorType, match)
+}
+
+// searchMatch reports whether a vendor matches a search query. Text queries
+// match vendors with a positive score, the other modes match the name.
+func searchMatch(query domain.SearchQuery) (func(*domain.GetVendorResponse) bool, error) {
+	if query.Mode == domain.SearchModeText {
+		terms := domain.SearchTerms(query.Text)
+		return func(vendor *domain.GetVendorResponse) bool {
+			return domain.TextScore(vendor, terms) > 0
+		}, nil
+	}
+
+	pattern, err := regexp.Compile("(?i)" + query.Pattern())
+	if err != nil {
+		return nil, err
+	}
+	return func(vendor *domain.GetVendorResponse) bool {
+		return pattern.MatchString(vendor.Name)
+	}, nil
+}
+
+func (r *MemoryVendorRepository) CountVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) (int, error) {
+	matches, err := r.near(ctx, point, radiusMeters)
+	if err != nil {
+		return 0, err
+	}
+	return len(matches), nil
+}
+
+// near returns the live vendors within radiusMeters of point, closest first.
+func (r *MemoryVendorRepository) near(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) ([]*domain.NearbyVendorResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	var matches []*domain.NearbyVendorResponse
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt != nil || vendor.Coordinates == nil {
+			continue
+		}
+		distance := domain.DistanceMeters(point, vendor.Coordinates)
+		if distance > radiusMeters {
+			continue
+		}
+		matches = append(matches, &domain.NearbyVendorResponse{
+			GetVendorResponse: *copyVendor(vendor),
+			Distance:          distance,
+		})
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	return matches, nil
+}
+
+func (r *MemoryVendorRepository) FindVendorsInArea(ctx context.Context, area *domain.GeoPolygon, limit int) ([]*domain.MapVendor, error) {
+	vendors, err := r.inArea(ctx, area)
+	if err != nil {
+		return nil, err
+	}
+	if len(vendors) > limit {
+		vendors = vendors[:limit]
+	}
+	return vendors, nil
+}
+
+func (r *MemoryVendorRepository) ClusterVendorsInArea(ctx context.Context, area *domain.GeoPolygon, cellSize float64) ([]*domain.MapCluster, error) {
+	vendors, err := r.inArea(ctx, area)
+	if err != nil {
+		return nil, err
+	}
+
+	type cell struct{ x, y float64 }
+	type sums struct {
+		cluster  *domain.MapCluster
+		lng, lat float64
+	}
+
+	var cells []cell
+	byCell := make(map[cell]*sums)
+	for _, vendor := range vendors {
+		key := cell{math.Floor(vendor.Coordinates.Lng() / cellSize), math.Floor(vendor.Coordinates.Lat() / cellSize)}
+		group, ok := byCell[key]
+		if !ok {
+			group = &sums{cluster: &domain.MapCluster{Vendor: vendor}}
+			byCell[key] = group
+			cells = append(cells, key)
+		}
+		group.cluster.Count++
+		group.lng += vendor.Coordinates.Lng()
+		group.lat += vendor.Coordinates.Lat()
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].y != cells[j].y {
+			return cells[i].y < cells[j].y
+		}
+		return cells[i].x < cells[j].x
+	})
+
+	clusters := make([]*domain.MapCluster, 0, len(cells))
+	for _, key := range cells {
+		group := byCell[key]
+		count := float64(group.cluster.Count)
+		group.cluster.Coordinates = domain.NewGeoPoint(group.lng/count, group.lat/count)
+		clusters = append(clusters, group.cluster)
+	}
+
+	return clusters, nil
+}
+
+// inArea returns the map projection of every live vendor inside area, in id
+// order.
+func (r *MemoryVendorRepository) inArea(ctx context.Context, area *domain.GeoPolygon) ([]*domain.MapVendor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var vendors []*domain.MapVendor
+	for _, id := range r.order {
+		vendor := r.vendors[id]
+		if vendor.DeletedAt != nil || vendor.Coordinates == nil || !area.Contains(vendor.Coordinates) {
+			continue
+		}
+		vendors = append(vendors, &domain.MapVendor{
+			ID:          vendor.ID,
+			Name:        vendor.Name,
+			Type:        vendor.Type,
+			Cover:       vendor.Cover,
+			Coordinates: copyPoint(vendor.Coordinates),
+		})
+	}
+
+	return vendors, nil
+}
+
+func (r *MemoryVendorRepository) GetVendorFacets(ctx context.Context, fields []string, vendorType string, limit int) (domain.Facets, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var vendors []*domain.GetVendorResponse
+	for _, vendor := range r.vendors {
+		if vendor.DeletedAt == nil && (vendorType == "" || vendor.Type == vendorType) {
+			vendors = append(vendors, vendor)
+		}
+	}
+
+	return domain.CountFacets(fields, vendors, limit), nil
+}
+
+func (r *MemoryVendorRepository) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.find(ctx, opts, filter.Matches)
+}
+
+func (r *MemoryVendorRepository) CountFilteredVendors(ctx context.Context, filter domain.VendorFilter) (int, error) {
+	return r.count(ctx, "", filter.Matches)
+}
+
+// ExportVendors emits copies taken under the lock, so emit may take its time
+// without holding up writers.
+func (r *MemoryVendorRepository) ExportVendors(ctx context.Context, filter domain.VendorFilter, order domain.Sort, emit func(*domain.GetVendorResponse) error) error {
+	list, err := r.find(ctx, domain.ListOptions{Page: 1, PageSize: math.MaxInt32, Sort: order}, filter.Matches)
+	if err != nil {
+		return err
+	}
+
+	for _, vendor := range list.Vendors {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(vendor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryVendorRepository) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.find(ctx, opts, func(vendor *domain.GetVendorResponse) bool {
+		return tags.Matches(vendor.Tags)
+	})
+}
+
+func (r *MemoryVendorRepository) CountVendorsByTags(ctx context.Context, tags domain.ValueMatch, vendorType string) (int, error) {
+	return r.count(ctx, vendorType, func(vendor *domain.GetVendorResponse) bool {
+		return tags.Matches(vendor.Tags)
+	})
+}
+
+// count returns the number of matching vendors of vendorType, or of any type
+// when it is empty, that are not in the trash.
+func (r *MemoryVendorRepository) count(ctx context.Context, vendorType string, match func(*domain.GetVendorResponse) bool) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := 0
+	for _, vendor := range r.vendors {
+		if vendor.DeletedAt == nil && (vendorType == "" || vendor.Type == vendorType) && match(vendor) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// find returns a page of the matching vendors that are not in the trash.
+func (r *MemoryVendorRepository) find(ctx context.Context, opts domain.ListOptions, match func(*domain.GetVendorResponse) bool) (*domain.VendorList, error) {
+	return r.scan(ctx, opts, func(vendor *domain.GetVendorResponse) bool {
+		return vendor.DeletedAt == nil && match(vendor)
+	})
+}
+
+// scan returns a page of the matching vendors in the order of opts.Sort, which
+// defaults to _id order. Object IDs are generated in increasing order, so that
+// is also the insertion order.
+func (r *MemoryVendorRepository) scan(ctx context.Context, opts domain.ListOptions, match func(*domain.GetVendorResponse) bool) (*domain.VendorList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	var matches []*domain.GetVendorResponse
+	for _, id := range r.order {
+		if vendor := r.vendors[id]; (opts.Type == "" || vendor.Type == opts.Type) && match(vendor) {
+			matches = append(matches, copyVendor(vendor))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return opts.Sort.Compare(matches[i], matches[j]) < 0
+	})
+
+	page, more, err := window(matches, opts, opts.Sort.DecodeCursor, opts.Sort.After)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &domain.VendorList{Vendors: page, Facets: domain.CountFacets(opts.Facets, matches, opts.FacetLimit)}
+	if more {
+		last := page[len(page)-1]
+		list.NextCursor = domain.EncodeCursor(domain.Cursor{Values: opts.Sort.Values(last), ID: last.ID})
+	}
+	return list, nil
+}
+
+// window cuts the page selected by opts out of items, which must already be in
+// result order. decode reads the cursor and after reports whether an item
+// sorts after its position. The returned flag tells whether more items follow
+// the page.
+func window[T any](items []T, opts domain.ListOptions, decode func(string) (domain.Cursor, error), after func(T, domain.Cursor) bool) ([]T, bool, error) {
+	if opts.Cursor != "" {
+		cursor, err := decode(opts.Cursor)
+		if err != nil {
+			return nil, false, err
+		}
+
+		start := len(items)
+		for i, item := range items {
+			if after(item, cursor) {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	} else {
+		skip := (opts.Page - 1) * opts.PageSize
+		if skip > len(items) {
+			skip = len(items)
+		}
+		items = items[skip:]
+	}
+
+	if len(items) > opts.PageSize {
+		return items[:opts.PageSize], true, nil
+	}
+	return items, false, nil
+}
+
+// decodeKeys reads a cursor that holds the given number of sort key values.
+func decodeKeys(keys int) func(string) (domain.Cursor, error) {
+	return func(encoded string) (domain.Cursor, error) {
+		return domain.DecodeCursor(encoded, keys)
+	}
+}
+
+func afterID(id, cursorID primitive.ObjectID) bool {
+	return bytes.Compare(id[:], cursorID[:]) > 0
+}
+
+func copyVendor(vendor *domain.GetVendorResponse) *domain.GetVendorResponse {
+	c := *vendor
+	if vendor.DeletedAt != nil {
+		deletedAt := *vendor.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	c.PhoneNumbers = copyStrings(vendor.PhoneNumbers)
+	c.Websites = copyStrings(vendor.Websites)
+	c.SocialNetworks = copyStrings(vendor.SocialNetworks)
+	c.Media = copyStrings(vendor.Media)
+	c.Tags = copyStrings(vendor.Tags)
+	c.Categories = copyStrings(vendor.Categories)
+	c.Coordinates = copyPoint(vendor.Coordinates)
+	c.Aliases = append([]primitive.ObjectID(nil), vendor.Aliases...)
+	return &c
+}
+
+func copyPoint(point *domain.GeoPoint) *domain.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Type: point.Type, Coordinates: append([]float64(nil), point.Coordinates...)}
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
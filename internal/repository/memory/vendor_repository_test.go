@@ -0,0 +1,547 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func seedVendors(t *testing.T, repo *repository.MemoryVendorRepository, requests ...*domain.CreateVendorRequest) []*domain.CreateVendorResponse {
+	t.Helper()
+
+	var created []*domain.CreateVendorResponse
+	for _, request := range requests {
+		vendor, err := repo.CreateVendor(context.Background(), request)
+		require.NoError(t, err)
+		created = append(created, vendor)
+	}
+	return created
+}
+
+func TestMemoryGetAllVendors(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	for i := 0; i < 25; i++ {
+		seedVendors(t, repo, &domain.CreateVendorRequest{Name: fmt.Sprintf("vendor %d", i)})
+	}
+
+	tests := []struct {
+		name      string
+		page      int
+		wantCount int
+		wantFirst string
+	}{
+		{name: "First page", page: 1, wantCount: 10, wantFirst: "vendor 0"},
+		{name: "Last page", page: 3, wantCount: 5, wantFirst: "vendor 20"},
+		{name: "Past the end", page: 4, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.GetAllVendors(ctx, domain.ListOptions{Page: tt.page, PageSize: 10})
+			assert.NoError(t, err)
+			assert.Len(t, list.Vendors, tt.wantCount)
+			if tt.wantCount > 0 {
+				assert.Equal(t, tt.wantFirst, list.Vendors[0].Name)
+			}
+		})
+	}
+
+	total, err := repo.GetTotalVendorsCount(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 25, total)
+}
+
+func TestMemoryCursorPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	for i := 0; i < 25; i++ {
+		seedVendors(t, repo, &domain.CreateVendorRequest{Name: fmt.Sprintf("vendor %d", i), Tags: []string{"pizza"}})
+	}
+
+	var names []string
+	opts := domain.ListOptions{Page: 1, PageSize: 10}
+	for {
+		list, err := repo.GetAllVendors(ctx, opts)
+		require.NoError(t, err)
+		for _, vendor := range list.Vendors {
+			names = append(names, vendor.Name)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+
+		// A vendor created mid-walk sorts after everything seen so far and
+		// must neither shift nor repeat the remaining rows.
+		if len(names) == 10 {
+			seedVendors(t, repo, &domain.CreateVendorRequest{Name: "late"})
+		}
+	}
+	assert.Len(t, names, 26)
+	assert.Equal(t, "vendor 10", names[10])
+	assert.Equal(t, "late", names[25])
+
+	var scored int
+	opts = domain.ListOptions{Page: 1, PageSize: 4}
+	query := domain.SearchQuery{Text: "pizza", Mode: domain.SearchModeText}
+	for {
+		list, err := repo.SearchVendors(ctx, query, opts)
+		require.NoError(t, err)
+		scored += len(list.Vendors)
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+	assert.Equal(t, 25, scored)
+
+	_, err := repo.GetAllVendors(ctx, domain.ListOptions{PageSize: 10, Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestMemorySortedPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	for _, name := range []string{"delta", "alpha", "charlie", "bravo", "alpha"} {
+		seedVendors(t, repo, &domain.CreateVendorRequest{Name: name, Tags: []string{"pizza"}})
+	}
+
+	sort, err := domain.ParseSort("-name")
+	require.NoError(t, err)
+
+	var names []string
+	opts := domain.ListOptions{Page: 1, PageSize: 2, Sort: sort}
+	for {
+		list, err := repo.GetAllVendors(ctx, opts)
+		require.NoError(t, err)
+		for _, vendor := range list.Vendors {
+			names = append(names, vendor.Name)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+	assert.Equal(t, []string{"delta", "charlie", "bravo", "alpha", "alpha"}, names)
+
+	sort, err = domain.ParseSort("-updated_at")
+	require.NoError(t, err)
+
+	vendors, err := repo.GetAllVendors(ctx, domain.ListOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	first := vendors.Vendors[0]
+	assert.False(t, first.CreatedAt.IsZero())
+	assert.Equal(t, first.CreatedAt, first.UpdatedAt)
+
+	updated, err := repo.UpdateVendor(ctx, first.ID, &domain.UpdateVendorRequest{Name: "echo", Tags: []string{"pizza"}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, first.CreatedAt, updated.CreatedAt)
+	assert.True(t, updated.UpdatedAt.After(first.UpdatedAt))
+
+	results, err := repo.SearchVendors(ctx, domain.SearchQuery{Text: "pizza", Mode: domain.SearchModeText}, domain.ListOptions{Page: 1, PageSize: 10, Sort: sort})
+	require.NoError(t, err)
+	assert.Equal(t, "echo", results.Vendors[0].Name)
+}
+
+func TestMemoryVendorLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	created := seedVendors(t, repo, &domain.CreateVendorRequest{
+		Name: "Pizza Place",
+		Tags: []string{"pizza"},
+	})[0]
+
+	vendor, err := repo.GetVendorByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pizza Place", vendor.Name)
+
+	vendor.Tags[0] = "mutated"
+	stored, _ := repo.GetVendorByID(ctx, created.ID)
+	assert.Equal(t, []string{"pizza"}, stored.Tags, "returned vendors must not alias stored state")
+
+	updated, err := repo.UpdateVendor(ctx, created.ID, &domain.UpdateVendorRequest{Name: "Pasta Place"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pasta Place", updated.Name)
+	assert.Nil(t, updated.Tags)
+
+	assert.NoError(t, repo.DeleteVendor(ctx, created.ID, 0))
+
+	missing, err := repo.GetVendorByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	assert.EqualError(t, repo.DeleteVendor(ctx, created.ID, 0), "vendor not found")
+
+	_, err = repo.UpdateVendor(ctx, primitive.NewObjectID(), &domain.UpdateVendorRequest{}, 0)
+	assert.EqualError(t, err, "vendor not found")
+}
+
+func TestMemorySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	created := seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "Pizza Place", Tags: []string{"pizza"}},
+		&domain.CreateVendorRequest{Name: "Cinema City"},
+	)
+	trashed := created[0].ID
+
+	require.NoError(t, repo.DeleteVendor(ctx, trashed, 0))
+
+	vendor, err := repo.GetVendorByID(ctx, trashed)
+	assert.NoError(t, err)
+	assert.Nil(t, vendor)
+
+	total, _ := repo.GetTotalVendorsCount(ctx, "")
+	assert.Equal(t, 1, total)
+
+	search, _ := repo.SearchVendors(ctx, domain.SearchQuery{Text: "pizza", Mode: domain.SearchModeText}, domain.ListOptions{Page: 1, PageSize: 10})
+	assert.Empty(t, search.Vendors)
+
+	filtered, _ := repo.FilterVendorsByTags(ctx, domain.ValueMatch{Values: []string{"pizza"}}, domain.ListOptions{Page: 1, PageSize: 10})
+	assert.Empty(t, filtered.Vendors)
+
+	_, err = repo.UpdateVendor(ctx, trashed, &domain.UpdateVendorRequest{Name: "x"}, 0)
+	assert.ErrorIs(t, err, domain.ErrVendorNotFound)
+
+	trash, err := repo.GetDeletedVendors(ctx, domain.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	require.Len(t, trash.Vendors, 1)
+	assert.NotNil(t, trash.Vendors[0].DeletedAt)
+
+	require.NoError(t, repo.RestoreVendor(ctx, trashed))
+	assert.ErrorIs(t, repo.RestoreVendor(ctx, trashed), domain.ErrVendorNotFound)
+
+	vendor, _ = repo.GetVendorByID(ctx, trashed)
+	assert.NotNil(t, vendor)
+
+	require.NoError(t, repo.DeleteVendor(ctx, trashed, 0))
+
+	purged, err := repo.PurgeDeletedVendors(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged, "vendors inside the retention period are kept")
+
+	purged, err = repo.PurgeDeletedVendors(ctx, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	assert.ErrorIs(t, repo.RestoreVendor(ctx, trashed), domain.ErrVendorNotFound)
+	total, _ = repo.GetDeletedVendorsCount(ctx)
+	assert.Zero(t, total)
+}
+
+func TestMemoryMergeVendor(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	created := seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "Pizza Place", Tags: []string{"pizza"}},
+		&domain.CreateVendorRequest{Name: "Pizzaplace"},
+	)
+	survivor, duplicate := created[0], created[1]
+
+	merged := &domain.UpdateVendorRequest{Name: "Pizza Place", Tags: []string{"pizza", "vegan"}}
+	_, err := repo.MergeVendor(ctx, survivor.ID, merged, []primitive.ObjectID{duplicate.ID}, survivor.Version+1)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+
+	updated, err := repo.MergeVendor(ctx, survivor.ID, merged, []primitive.ObjectID{duplicate.ID}, survivor.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pizza", "vegan"}, updated.Tags)
+	assert.Equal(t, []primitive.ObjectID{duplicate.ID}, updated.Aliases)
+	assert.Equal(t, survivor.Version+1, updated.Version)
+
+	_, err = repo.MergeVendor(ctx, survivor.ID, merged, []primitive.ObjectID{duplicate.ID}, 0)
+	require.NoError(t, err)
+
+	updated, err = repo.UpdateVendor(ctx, survivor.ID, &domain.UpdateVendorRequest{Name: "Pizza Place"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{duplicate.ID}, updated.Aliases, "aliases survive updates and are never repeated")
+
+	resolved, err := repo.GetVendorByAlias(ctx, duplicate.ID)
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, survivor.ID, resolved.ID)
+
+	missing, err := repo.GetVendorByAlias(ctx, survivor.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, repo.DeleteVendor(ctx, survivor.ID, 0))
+	missing, err = repo.GetVendorByAlias(ctx, duplicate.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, missing, "vendors in the trash don't resolve aliases")
+}
+
+func TestMemoryFindDuplicateCandidates(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	created := seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeFood},
+		&domain.CreateVendorRequest{Name: "Odeon", Type: domain.VendorTypeCinema, PhoneNumbers: []string{"030 123456"}},
+		&domain.CreateVendorRequest{Name: "Pizzaplace", Type: domain.VendorTypeFood},
+		&domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeCinema},
+		&domain.CreateVendorRequest{Name: "Globe", Type: domain.VendorTypeCinema, PhoneNumbers: []string{"+49 30 123456"}},
+	)
+
+	ids := func(vendors []*domain.GetVendorResponse) []primitive.ObjectID {
+		var ids []primitive.ObjectID
+		for _, vendor := range vendors {
+			ids = append(ids, vendor.ID)
+		}
+		return ids
+	}
+
+	candidates, err := repo.FindDuplicateCandidates(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{created[0].ID, created[1].ID, created[2].ID, created[4].ID}, ids(candidates),
+		"vendors sharing no key with another of their type are left out")
+
+	candidates, err = repo.FindDuplicateCandidates(ctx, domain.VendorTypeCinema)
+	require.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{created[1].ID, created[4].ID}, ids(candidates))
+
+	require.NoError(t, repo.DeleteVendor(ctx, created[4].ID, 0))
+	candidates, err = repo.FindDuplicateCandidates(ctx, domain.VendorTypeCinema)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+func TestMemoryOptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	created := seedVendors(t, repo, &domain.CreateVendorRequest{Name: "Pizza Place"})[0]
+	assert.Equal(t, int64(1), created.Version)
+
+	tests := []struct {
+		name            string
+		expectedVersion int64
+		wantVersion     int64
+		wantErr         error
+	}{
+		{name: "Matching version", expectedVersion: 1, wantVersion: 2},
+		{name: "Stale version", expectedVersion: 1, wantErr: domain.ErrVersionConflict},
+		{name: "Unconditional", expectedVersion: 0, wantVersion: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := repo.UpdateVendor(ctx, created.ID, &domain.UpdateVendorRequest{Name: tt.name}, tt.expectedVersion)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, updated.Version)
+		})
+	}
+
+	assert.ErrorIs(t, repo.DeleteVendor(ctx, created.ID, 2), domain.ErrVersionConflict)
+	assert.NoError(t, repo.DeleteVendor(ctx, created.ID, 3))
+}
+
+func TestMemorySearchVendors(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "Napoli", Tags: []string{"pizza"}},
+		&domain.CreateVendorRequest{Name: "Pizza Place", Location: "Old Town"},
+		&domain.CreateVendorRequest{Name: "Cinema City", Location: "Old Town"},
+	)
+
+	tests := []struct {
+		name      string
+		query     domain.SearchQuery
+		wantNames []string
+	}{
+		{name: "Name outranks tags", query: domain.SearchQuery{Text: "PIZZA", Mode: domain.SearchModeText}, wantNames: []string{"Pizza Place", "Napoli"}},
+		{name: "Location", query: domain.SearchQuery{Text: "town", Mode: domain.SearchModeText}, wantNames: []string{"Pizza Place", "Cinema City"}},
+		{name: "Any term matches", query: domain.SearchQuery{Text: "napoli cinema", Mode: domain.SearchModeText}, wantNames: []string{"Napoli", "Cinema City"}},
+		{name: "No match", query: domain.SearchQuery{Text: "theatre", Mode: domain.SearchModeText}},
+		{name: "Prefix", query: domain.SearchQuery{Text: "pi", Mode: domain.SearchModePrefix}, wantNames: []string{"Pizza Place"}},
+		{name: "Contains", query: domain.SearchQuery{Text: "CITY", Mode: domain.SearchModeContains}, wantNames: []string{"Cinema City"}},
+		{name: "Exact", query: domain.SearchQuery{Text: "pizza", Mode: domain.SearchModeExact}},
+		{name: "Literal metacharacters", query: domain.SearchQuery{Text: ".*", Mode: domain.SearchModeContains}},
+		{name: "Regex", query: domain.SearchQuery{Text: "^(napoli|cinema)", Mode: domain.SearchModeRegex}, wantNames: []string{"Napoli", "Cinema City"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.SearchVendors(ctx, tt.query, domain.ListOptions{Page: 1, PageSize: 10})
+			assert.NoError(t, err)
+
+			var names []string
+			for _, vendor := range list.Vendors {
+				names = append(names, vendor.Name)
+				if tt.query.Mode == domain.SearchModeText {
+					assert.Positive(t, vendor.Score)
+				}
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestMemoryFilterVendorsByTags(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "a", Tags: []string{"vegan", "pizza"}},
+		&domain.CreateVendorRequest{Name: "b", Tags: []string{"vegan"}},
+		&domain.CreateVendorRequest{Name: "c", Tags: []string{"pizza"}},
+	)
+
+	tests := []struct {
+		name string
+		tags domain.ValueMatch
+		want []string
+	}{
+		{name: "All", tags: domain.ValueMatch{Values: []string{"vegan", "pizza"}}, want: []string{"a"}},
+		{name: "Single", tags: domain.ValueMatch{Values: []string{"vegan"}, Mode: domain.MatchAll}, want: []string{"a", "b"}},
+		{name: "Any", tags: domain.ValueMatch{Values: []string{"vegan", "pizza"}, Mode: domain.MatchAny}, want: []string{"a", "b", "c"}},
+		{name: "None", tags: domain.ValueMatch{Values: []string{"pizza"}, Mode: domain.MatchNone}, want: []string{"b"}},
+		{name: "Exclude", tags: domain.ValueMatch{Values: []string{"vegan"}, Exclude: []string{"pizza"}}, want: []string{"b"}},
+		{name: "Exclude only", tags: domain.ValueMatch{Exclude: []string{"vegan"}}, want: []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.FilterVendorsByTags(ctx, tt.tags, domain.ListOptions{Page: 1, PageSize: 10})
+			require.NoError(t, err)
+
+			var names []string
+			for _, vendor := range list.Vendors {
+				names = append(names, vendor.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestMemoryFilterVendors(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "Green Pizza", Type: "food", Tags: []string{"vegan", "pizza"}, Cover: "green.png", Websites: []string{"green.example"}},
+		&domain.CreateVendorRequest{Name: "Pizza Express", Type: "food", Tags: []string{"pizza"}, Categories: []string{"takeaway"}},
+		&domain.CreateVendorRequest{Name: "Vegan Cinema", Type: "cinema", Tags: []string{"vegan"}, Websites: []string{"cinema.example"}},
+	)
+
+	tests := []struct {
+		name   string
+		filter domain.VendorFilter
+		want   []string
+	}{
+		{name: "No criteria", filter: domain.VendorFilter{}, want: []string{"Green Pizza", "Pizza Express", "Vegan Cinema"}},
+		{name: "Tags and type", filter: domain.VendorFilter{Tags: domain.ValueMatch{Values: []string{"vegan"}}, Type: "food"}, want: []string{"Green Pizza"}},
+		{name: "Categories", filter: domain.VendorFilter{Categories: domain.ValueMatch{Values: []string{"takeaway"}}}, want: []string{"Pizza Express"}},
+		{name: "Text and presence", filter: domain.VendorFilter{Query: "pizza", Presence: map[string]bool{"websites": true}}, want: []string{"Green Pizza"}},
+		{name: "Any category", filter: domain.VendorFilter{Categories: domain.ValueMatch{Values: []string{"takeaway", "cinema"}, Mode: domain.MatchAny}}, want: []string{"Pizza Express"}},
+		{name: "Excluded category", filter: domain.VendorFilter{Tags: domain.ValueMatch{Values: []string{"pizza"}}, Categories: domain.ValueMatch{Exclude: []string{"takeaway"}}}, want: []string{"Green Pizza"}},
+		{name: "Missing cover", filter: domain.VendorFilter{Presence: map[string]bool{"cover": false, "websites": true}}, want: []string{"Vegan Cinema"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.FilterVendors(ctx, tt.filter, domain.ListOptions{Page: 1, PageSize: 10})
+			require.NoError(t, err)
+
+			var names []string
+			for _, vendor := range list.Vendors {
+				names = append(names, vendor.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestMemoryFindVendorsNear(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	// Roughly 111m per 0.001 degrees of latitude.
+	vendors := seedVendors(t, repo,
+		&domain.CreateVendorRequest{Name: "far", Coordinates: domain.NewGeoPoint(0, 0.008)},
+		&domain.CreateVendorRequest{Name: "nearest", Coordinates: domain.NewGeoPoint(0, 0.001)},
+		&domain.CreateVendorRequest{Name: "nowhere"},
+		&domain.CreateVendorRequest{Name: "middle", Coordinates: domain.NewGeoPoint(0, 0.004)},
+		&domain.CreateVendorRequest{Name: "deleted", Coordinates: domain.NewGeoPoint(0, 0)},
+	)
+	require.NoError(t, repo.DeleteVendor(ctx, vendors[4].ID, 0))
+
+	origin := domain.NewGeoPoint(0, 0)
+
+	list, err := repo.FindVendorsNear(ctx, origin, 1000, domain.ListOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Vendors, 3)
+	assert.Equal(t, "nearest", list.Vendors[0].Name)
+	assert.Equal(t, "middle", list.Vendors[1].Name)
+	assert.Equal(t, "far", list.Vendors[2].Name)
+	assert.InDelta(t, 111, list.Vendors[0].Distance, 1)
+
+	total, err := repo.CountVendorsNear(ctx, origin, 500)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	first, err := repo.FindVendorsNear(ctx, origin, 1000, domain.ListOptions{Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := repo.FindVendorsNear(ctx, origin, 1000, domain.ListOptions{PageSize: 2, Cursor: first.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, second.Vendors, 1)
+	assert.Equal(t, "far", second.Vendors[0].Name)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestMemoryConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryVendorRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: fmt.Sprintf("vendor %d", i)})
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := repo.GetAllVendors(ctx, domain.ListOptions{Page: 1, PageSize: 10})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	total, err := repo.GetTotalVendorsCount(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 50, total)
+}
+
+func TestMemoryCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo := repository.NewMemoryVendorRepository()
+
+	_, err := repo.GetAllVendors(ctx, domain.ListOptions{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MemoryWebhookRepository struct {
+	mu         sync.RWMutex
+	webhooks   map[primitive.ObjectID]*domain.Webhook
+	deliveries map[primitive.ObjectID]*domain.WebhookDelivery
+}
+
+func NewMemoryWebhookRepository() *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		webhooks:   make(map[primitive.ObjectID]*domain.Webhook),
+		deliveries: make(map[primitive.ObjectID]*domain.WebhookDelivery),
+	}
+}
+
+func (r *MemoryWebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	webhook.ID = primitive.NewObjectID()
+	stored := *webhook
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.webhooks[stored.ID] = &stored
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*domain.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		c := *webhook
+		webhooks = append(webhooks, &c)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return afterID(webhooks[j].ID, webhooks[i].ID)
+	})
+	return webhooks, nil
+}
+
+func (r *MemoryWebhookRepository) GetWebhook(ctx context.Context, id primitive.ObjectID) (*domain.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+	c := *webhook
+	return &c, nil
+}
+
+func (r *MemoryWebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	delete(r.webhooks, id)
+
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.WebhookID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		delivery.ID = primitive.NewObjectID()
+		r.deliveries[delivery.ID] = copyDelivery(delivery)
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*domain.WebhookDelivery, len(due))
+	for i, delivery := range due {
+		delivery.NextAttemptAt = now.Add(lease)
+		claimed[i] = copyDelivery(delivery)
+	}
+	return claimed, nil
+}
+
+func (r *MemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return domain.ErrDeliveryNotFound
+	}
+	r.deliveries[delivery.ID] = copyDelivery(delivery)
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.WebhookID != webhookID {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	return copyDelivery(delivery), nil
+}
+
+func (r *MemoryWebhookRepository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []*domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return afterID(deliveries[i].ID, deliveries[j].ID)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func copyDelivery(delivery *domain.WebhookDelivery) *domain.WebhookDelivery {
+	c := *delivery
+	c.Attempts = append([]domain.DeliveryAttempt(nil), delivery.Attempts...)
+	return &c
+}
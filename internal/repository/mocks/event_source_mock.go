@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: event_source.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "vendors/internal/domain"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVendorEventSource is a mock of VendorEventSource interface.
+type MockVendorEventSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockVendorEventSourceMockRecorder
+}
+
+// MockVendorEventSourceMockRecorder is the mock recorder for MockVendorEventSource.
+type MockVendorEventSourceMockRecorder struct {
+	mock *MockVendorEventSource
+}
+
+// NewMockVendorEventSource creates a new mock instance.
+func NewMockVendorEventSource(ctrl *gomock.Controller) *MockVendorEventSource {
+	mock := &MockVendorEventSource{ctrl: ctrl}
+	mock.recorder = &MockVendorEventSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVendorEventSource) EXPECT() *MockVendorEventSourceMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockVendorEventSource) Subscribe(ctx context.Context, lastEventID string) (<-chan *domain.VendorEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, lastEventID)
+	ret0, _ := ret[0].(<-chan *domain.VendorEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockVendorEventSourceMockRecorder) Subscribe(ctx, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockVendorEventSource)(nil).Subscribe), ctx, lastEventID)
+}
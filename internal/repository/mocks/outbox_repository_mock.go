@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "vendors/internal/domain"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxRecords mocks base method.
+func (m *MockOutboxRepository) ClaimOutboxRecords(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxRecords", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]*domain.OutboxRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxRecords indicates an expected call of ClaimOutboxRecords.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimOutboxRecords(ctx, now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxRecords", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimOutboxRecords), ctx, now, lease, limit)
+}
+
+// DeleteOutboxRecord mocks base method.
+func (m *MockOutboxRepository) DeleteOutboxRecord(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutboxRecord", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutboxRecord indicates an expected call of DeleteOutboxRecord.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteOutboxRecord(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutboxRecord", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteOutboxRecord), ctx, id)
+}
+
+// ReleaseOutboxRecord mocks base method.
+func (m *MockOutboxRepository) ReleaseOutboxRecord(ctx context.Context, record *domain.OutboxRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOutboxRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseOutboxRecord indicates an expected call of ReleaseOutboxRecord.
+func (mr *MockOutboxRepositoryMockRecorder) ReleaseOutboxRecord(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOutboxRecord", reflect.TypeOf((*MockOutboxRepository)(nil).ReleaseOutboxRecord), ctx, record)
+}
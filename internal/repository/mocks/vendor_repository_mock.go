@@ -5,7 +5,9 @@
 package mock_repository
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 	domain "vendors/internal/domain"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,121 +37,464 @@ func (m *MockVendorRepository) EXPECT() *MockVendorRepositoryMockRecorder {
 	return m.recorder
 }
 
+// BulkCreateVendors mocks base method.
+func (m *MockVendorRepository) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) ([]domain.BulkItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkCreateVendors", ctx, vendors, ordered)
+	ret0, _ := ret[0].([]domain.BulkItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkCreateVendors indicates an expected call of BulkCreateVendors.
+func (mr *MockVendorRepositoryMockRecorder) BulkCreateVendors(ctx, vendors, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateVendors", reflect.TypeOf((*MockVendorRepository)(nil).BulkCreateVendors), ctx, vendors, ordered)
+}
+
+// BulkDeleteVendors mocks base method.
+func (m *MockVendorRepository) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) ([]domain.BulkItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDeleteVendors", ctx, deletes, ordered)
+	ret0, _ := ret[0].([]domain.BulkItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkDeleteVendors indicates an expected call of BulkDeleteVendors.
+func (mr *MockVendorRepositoryMockRecorder) BulkDeleteVendors(ctx, deletes, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteVendors", reflect.TypeOf((*MockVendorRepository)(nil).BulkDeleteVendors), ctx, deletes, ordered)
+}
+
+// BulkUpdateVendors mocks base method.
+func (m *MockVendorRepository) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) ([]domain.BulkItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateVendors", ctx, updates, ordered)
+	ret0, _ := ret[0].([]domain.BulkItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateVendors indicates an expected call of BulkUpdateVendors.
+func (mr *MockVendorRepositoryMockRecorder) BulkUpdateVendors(ctx, updates, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateVendors", reflect.TypeOf((*MockVendorRepository)(nil).BulkUpdateVendors), ctx, updates, ordered)
+}
+
+// ClusterVendorsInArea mocks base method.
+func (m *MockVendorRepository) ClusterVendorsInArea(ctx context.Context, area *domain.GeoPolygon, cellSize float64) ([]*domain.MapCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterVendorsInArea", ctx, area, cellSize)
+	ret0, _ := ret[0].([]*domain.MapCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClusterVendorsInArea indicates an expected call of ClusterVendorsInArea.
+func (mr *MockVendorRepositoryMockRecorder) ClusterVendorsInArea(ctx, area, cellSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterVendorsInArea", reflect.TypeOf((*MockVendorRepository)(nil).ClusterVendorsInArea), ctx, area, cellSize)
+}
+
+// CountFilteredVendors mocks base method.
+func (m *MockVendorRepository) CountFilteredVendors(ctx context.Context, filter domain.VendorFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilteredVendors", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilteredVendors indicates an expected call of CountFilteredVendors.
+func (mr *MockVendorRepositoryMockRecorder) CountFilteredVendors(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilteredVendors", reflect.TypeOf((*MockVendorRepository)(nil).CountFilteredVendors), ctx, filter)
+}
+
+// CountSearchVendors mocks base method.
+func (m *MockVendorRepository) CountSearchVendors(ctx context.Context, query domain.SearchQuery, vendorType string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchVendors", ctx, query, vendorType)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchVendors indicates an expected call of CountSearchVendors.
+func (mr *MockVendorRepositoryMockRecorder) CountSearchVendors(ctx, query, vendorType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchVendors", reflect.TypeOf((*MockVendorRepository)(nil).CountSearchVendors), ctx, query, vendorType)
+}
+
+// CountVendorsByTags mocks base method.
+func (m *MockVendorRepository) CountVendorsByTags(ctx context.Context, tags domain.ValueMatch, vendorType string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVendorsByTags", ctx, tags, vendorType)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountVendorsByTags indicates an expected call of CountVendorsByTags.
+func (mr *MockVendorRepositoryMockRecorder) CountVendorsByTags(ctx, tags, vendorType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVendorsByTags", reflect.TypeOf((*MockVendorRepository)(nil).CountVendorsByTags), ctx, tags, vendorType)
+}
+
+// CountVendorsNear mocks base method.
+func (m *MockVendorRepository) CountVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVendorsNear", ctx, point, radiusMeters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountVendorsNear indicates an expected call of CountVendorsNear.
+func (mr *MockVendorRepositoryMockRecorder) CountVendorsNear(ctx, point, radiusMeters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVendorsNear", reflect.TypeOf((*MockVendorRepository)(nil).CountVendorsNear), ctx, point, radiusMeters)
+}
+
 // CreateVendor mocks base method.
-func (m *MockVendorRepository) CreateVendor(request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+func (m *MockVendorRepository) CreateVendor(ctx context.Context, request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVendor", request)
+	ret := m.ctrl.Call(m, "CreateVendor", ctx, request)
 	ret0, _ := ret[0].(*domain.CreateVendorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateVendor indicates an expected call of CreateVendor.
-func (mr *MockVendorRepositoryMockRecorder) CreateVendor(request interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) CreateVendor(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVendor", reflect.TypeOf((*MockVendorRepository)(nil).CreateVendor), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVendor", reflect.TypeOf((*MockVendorRepository)(nil).CreateVendor), ctx, request)
 }
 
 // DeleteVendor mocks base method.
-func (m *MockVendorRepository) DeleteVendor(id primitive.ObjectID) error {
+func (m *MockVendorRepository) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteVendor", id)
+	ret := m.ctrl.Call(m, "DeleteVendor", ctx, id, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteVendor indicates an expected call of DeleteVendor.
-func (mr *MockVendorRepositoryMockRecorder) DeleteVendor(id interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) DeleteVendor(ctx, id, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVendor", reflect.TypeOf((*MockVendorRepository)(nil).DeleteVendor), ctx, id, expectedVersion)
+}
+
+// ExportVendors mocks base method.
+func (m *MockVendorRepository) ExportVendors(ctx context.Context, filter domain.VendorFilter, order domain.Sort, emit func(*domain.GetVendorResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportVendors", ctx, filter, order, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportVendors indicates an expected call of ExportVendors.
+func (mr *MockVendorRepositoryMockRecorder) ExportVendors(ctx, filter, order, emit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVendor", reflect.TypeOf((*MockVendorRepository)(nil).DeleteVendor), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportVendors", reflect.TypeOf((*MockVendorRepository)(nil).ExportVendors), ctx, filter, order, emit)
+}
+
+// FilterVendors mocks base method.
+func (m *MockVendorRepository) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterVendors", ctx, filter, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterVendors indicates an expected call of FilterVendors.
+func (mr *MockVendorRepositoryMockRecorder) FilterVendors(ctx, filter, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVendors", reflect.TypeOf((*MockVendorRepository)(nil).FilterVendors), ctx, filter, opts)
 }
 
 // FilterVendorsByTags mocks base method.
-func (m *MockVendorRepository) FilterVendorsByTags(tags []string, page, pageSize int) ([]*domain.GetVendorResponse, error) {
+func (m *MockVendorRepository) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterVendorsByTags", tags, page, pageSize)
-	ret0, _ := ret[0].([]*domain.GetVendorResponse)
+	ret := m.ctrl.Call(m, "FilterVendorsByTags", ctx, tags, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FilterVendorsByTags indicates an expected call of FilterVendorsByTags.
-func (mr *MockVendorRepositoryMockRecorder) FilterVendorsByTags(tags, page, pageSize interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) FilterVendorsByTags(ctx, tags, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVendorsByTags", reflect.TypeOf((*MockVendorRepository)(nil).FilterVendorsByTags), tags, page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVendorsByTags", reflect.TypeOf((*MockVendorRepository)(nil).FilterVendorsByTags), ctx, tags, opts)
 }
 
-// GetAllVendors mocks base method.
-func (m *MockVendorRepository) GetAllVendors(page, pageSize int) ([]*domain.GetVendorResponse, error) {
+// FindDuplicateCandidates mocks base method.
+func (m *MockVendorRepository) FindDuplicateCandidates(ctx context.Context, vendorType string) ([]*domain.GetVendorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllVendors", page, pageSize)
+	ret := m.ctrl.Call(m, "FindDuplicateCandidates", ctx, vendorType)
 	ret0, _ := ret[0].([]*domain.GetVendorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// FindDuplicateCandidates indicates an expected call of FindDuplicateCandidates.
+func (mr *MockVendorRepositoryMockRecorder) FindDuplicateCandidates(ctx, vendorType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicateCandidates", reflect.TypeOf((*MockVendorRepository)(nil).FindDuplicateCandidates), ctx, vendorType)
+}
+
+// FindVendorsInArea mocks base method.
+func (m *MockVendorRepository) FindVendorsInArea(ctx context.Context, area *domain.GeoPolygon, limit int) ([]*domain.MapVendor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVendorsInArea", ctx, area, limit)
+	ret0, _ := ret[0].([]*domain.MapVendor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVendorsInArea indicates an expected call of FindVendorsInArea.
+func (mr *MockVendorRepositoryMockRecorder) FindVendorsInArea(ctx, area, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVendorsInArea", reflect.TypeOf((*MockVendorRepository)(nil).FindVendorsInArea), ctx, area, limit)
+}
+
+// FindVendorsNear mocks base method.
+func (m *MockVendorRepository) FindVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVendorsNear", ctx, point, radiusMeters, opts)
+	ret0, _ := ret[0].(*domain.NearbyVendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVendorsNear indicates an expected call of FindVendorsNear.
+func (mr *MockVendorRepositoryMockRecorder) FindVendorsNear(ctx, point, radiusMeters, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVendorsNear", reflect.TypeOf((*MockVendorRepository)(nil).FindVendorsNear), ctx, point, radiusMeters, opts)
+}
+
+// GetAllVendors mocks base method.
+func (m *MockVendorRepository) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllVendors", ctx, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GetAllVendors indicates an expected call of GetAllVendors.
-func (mr *MockVendorRepositoryMockRecorder) GetAllVendors(page, pageSize interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) GetAllVendors(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllVendors", reflect.TypeOf((*MockVendorRepository)(nil).GetAllVendors), ctx, opts)
+}
+
+// GetDeletedVendors mocks base method.
+func (m *MockVendorRepository) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedVendors", ctx, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedVendors indicates an expected call of GetDeletedVendors.
+func (mr *MockVendorRepositoryMockRecorder) GetDeletedVendors(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedVendors", reflect.TypeOf((*MockVendorRepository)(nil).GetDeletedVendors), ctx, opts)
+}
+
+// GetDeletedVendorsCount mocks base method.
+func (m *MockVendorRepository) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedVendorsCount", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedVendorsCount indicates an expected call of GetDeletedVendorsCount.
+func (mr *MockVendorRepositoryMockRecorder) GetDeletedVendorsCount(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllVendors", reflect.TypeOf((*MockVendorRepository)(nil).GetAllVendors), page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedVendorsCount", reflect.TypeOf((*MockVendorRepository)(nil).GetDeletedVendorsCount), ctx)
 }
 
 // GetTotalVendorsCount mocks base method.
-func (m *MockVendorRepository) GetTotalVendorsCount() (int, error) {
+func (m *MockVendorRepository) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTotalVendorsCount")
+	ret := m.ctrl.Call(m, "GetTotalVendorsCount", ctx, vendorType)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTotalVendorsCount indicates an expected call of GetTotalVendorsCount.
-func (mr *MockVendorRepositoryMockRecorder) GetTotalVendorsCount() *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) GetTotalVendorsCount(ctx, vendorType interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalVendorsCount", reflect.TypeOf((*MockVendorRepository)(nil).GetTotalVendorsCount))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalVendorsCount", reflect.TypeOf((*MockVendorRepository)(nil).GetTotalVendorsCount), ctx, vendorType)
+}
+
+// GetVendorByAlias mocks base method.
+func (m *MockVendorRepository) GetVendorByAlias(ctx context.Context, alias primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorByAlias", ctx, alias)
+	ret0, _ := ret[0].(*domain.GetVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorByAlias indicates an expected call of GetVendorByAlias.
+func (mr *MockVendorRepositoryMockRecorder) GetVendorByAlias(ctx, alias interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorByAlias", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorByAlias), ctx, alias)
 }
 
 // GetVendorByID mocks base method.
-func (m *MockVendorRepository) GetVendorByID(id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+func (m *MockVendorRepository) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVendorByID", id)
+	ret := m.ctrl.Call(m, "GetVendorByID", ctx, id)
 	ret0, _ := ret[0].(*domain.GetVendorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetVendorByID indicates an expected call of GetVendorByID.
-func (mr *MockVendorRepositoryMockRecorder) GetVendorByID(id interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) GetVendorByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorByID", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorByID", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorByID), ctx, id)
 }
 
-// SearchVendors mocks base method.
-func (m *MockVendorRepository) SearchVendors(query string, page, pageSize int) ([]*domain.GetVendorResponse, error) {
+// GetVendorFacets mocks base method.
+func (m *MockVendorRepository) GetVendorFacets(ctx context.Context, fields []string, vendorType string, limit int) (domain.Facets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchVendors", query, page, pageSize)
+	ret := m.ctrl.Call(m, "GetVendorFacets", ctx, fields, vendorType, limit)
+	ret0, _ := ret[0].(domain.Facets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorFacets indicates an expected call of GetVendorFacets.
+func (mr *MockVendorRepositoryMockRecorder) GetVendorFacets(ctx, fields, vendorType, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorFacets", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorFacets), ctx, fields, vendorType, limit)
+}
+
+// GetVendorsByIDs mocks base method.
+func (m *MockVendorRepository) GetVendorsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorsByIDs", ctx, ids)
 	ret0, _ := ret[0].([]*domain.GetVendorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetVendorsByIDs indicates an expected call of GetVendorsByIDs.
+func (mr *MockVendorRepositoryMockRecorder) GetVendorsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorsByIDs", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorsByIDs), ctx, ids)
+}
+
+// GetVendorsByNames mocks base method.
+func (m *MockVendorRepository) GetVendorsByNames(ctx context.Context, names []string) ([]*domain.GetVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorsByNames", ctx, names)
+	ret0, _ := ret[0].([]*domain.GetVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorsByNames indicates an expected call of GetVendorsByNames.
+func (mr *MockVendorRepositoryMockRecorder) GetVendorsByNames(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorsByNames", reflect.TypeOf((*MockVendorRepository)(nil).GetVendorsByNames), ctx, names)
+}
+
+// MergeVendor mocks base method.
+func (m *MockVendorRepository) MergeVendor(ctx context.Context, id primitive.ObjectID, merged *domain.UpdateVendorRequest, aliases []primitive.ObjectID, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeVendor", ctx, id, merged, aliases, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeVendor indicates an expected call of MergeVendor.
+func (mr *MockVendorRepositoryMockRecorder) MergeVendor(ctx, id, merged, aliases, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeVendor", reflect.TypeOf((*MockVendorRepository)(nil).MergeVendor), ctx, id, merged, aliases, expectedVersion)
+}
+
+// PatchVendor mocks base method.
+func (m *MockVendorRepository) PatchVendor(ctx context.Context, id primitive.ObjectID, patch *domain.VendorPatch, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchVendor", ctx, id, patch, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchVendor indicates an expected call of PatchVendor.
+func (mr *MockVendorRepositoryMockRecorder) PatchVendor(ctx, id, patch, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchVendor", reflect.TypeOf((*MockVendorRepository)(nil).PatchVendor), ctx, id, patch, expectedVersion)
+}
+
+// PurgeDeletedVendors mocks base method.
+func (m *MockVendorRepository) PurgeDeletedVendors(ctx context.Context, deletedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedVendors", ctx, deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedVendors indicates an expected call of PurgeDeletedVendors.
+func (mr *MockVendorRepositoryMockRecorder) PurgeDeletedVendors(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedVendors", reflect.TypeOf((*MockVendorRepository)(nil).PurgeDeletedVendors), ctx, deletedBefore)
+}
+
+// RestoreVendor mocks base method.
+func (m *MockVendorRepository) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVendor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVendor indicates an expected call of RestoreVendor.
+func (mr *MockVendorRepositoryMockRecorder) RestoreVendor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVendor", reflect.TypeOf((*MockVendorRepository)(nil).RestoreVendor), ctx, id)
+}
+
+// SearchVendors mocks base method.
+func (m *MockVendorRepository) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchVendors", ctx, query, opts)
+	ret0, _ := ret[0].(*domain.SearchVendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // SearchVendors indicates an expected call of SearchVendors.
-func (mr *MockVendorRepositoryMockRecorder) SearchVendors(query, page, pageSize interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) SearchVendors(ctx, query, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchVendors", reflect.TypeOf((*MockVendorRepository)(nil).SearchVendors), query, page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchVendors", reflect.TypeOf((*MockVendorRepository)(nil).SearchVendors), ctx, query, opts)
 }
 
 // UpdateVendor mocks base method.
-func (m *MockVendorRepository) UpdateVendor(id primitive.ObjectID, request *domain.UpdateVendorRequest) (*domain.UpdateVendorResponse, error) {
+func (m *MockVendorRepository) UpdateVendor(ctx context.Context, id primitive.ObjectID, request *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateVendor", id, request)
+	ret := m.ctrl.Call(m, "UpdateVendor", ctx, id, request, expectedVersion)
 	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateVendor indicates an expected call of UpdateVendor.
-func (mr *MockVendorRepositoryMockRecorder) UpdateVendor(id, request interface{}) *gomock.Call {
+func (mr *MockVendorRepositoryMockRecorder) UpdateVendor(ctx, id, request, expectedVersion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVendor", reflect.TypeOf((*MockVendorRepository)(nil).UpdateVendor), id, request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVendor", reflect.TypeOf((*MockVendorRepository)(nil).UpdateVendor), ctx, id, request, expectedVersion)
 }
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetVendorsByIDs returns the live vendors among ids, in no particular order.
+func (r *MongoDBVendorRepository) GetVendorsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil})
+	if err != nil {
+		slog.Error("error retrieving vendors by id", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.GetVendorResponse](ctx, cursor)
+}
+
+// GetVendorsByNames returns the live vendors named exactly like one of names,
+// in no particular order.
+func (r *MongoDBVendorRepository) GetVendorsByNames(ctx context.Context, names []string) ([]*domain.GetVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"name": bson.M{"$in": names}, "deleted_at": nil})
+	if err != nil {
+		slog.Error("error retrieving vendors by name", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.GetVendorResponse](ctx, cursor)
+}
+
+// BulkCreateVendors inserts the vendors in one bulk write. With an outbox the
+// write and the records announcing the vendors commit in one transaction.
+func (r *MongoDBVendorRepository) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) ([]domain.BulkItemResult, error) {
+	documents := make([]domain.CommonVendorResponse, len(vendors))
+	models := make([]mongo.WriteModel, len(vendors))
+	results := make([]domain.BulkItemResult, len(vendors))
+	for i, vendor := range vendors {
+		document := domain.CommonVendorResponse(newVendorDocument(vendor))
+		document.ID = primitive.NewObjectID()
+
+		documents[i] = document
+		models[i] = mongo.NewInsertOneModel().SetDocument(storedVendor(document))
+		results[i] = domain.BulkItemResult{Index: i, ID: document.ID, Version: document.Version}
+	}
+
+	if r.outbox != nil {
+		batched, err := r.writeBatch(ctx, models, ordered, func(ctx context.Context) ([]*domain.OutboxRecord, error) {
+			records := make([]*domain.OutboxRecord, len(documents))
+			for i, document := range documents {
+				records[i] = domain.NewOutboxRecord(domain.VendorEventCreated, document)
+			}
+			return records, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if batched {
+			for i := range results {
+				results[i].Status = domain.BulkItemOK
+			}
+			return results, nil
+		}
+
+		results = make([]domain.BulkItemResult, len(vendors))
+		writeEach(results, ordered, func(i int, result *domain.BulkItemResult) error {
+			vendor, err := r.CreateVendor(ctx, vendors[i])
+			if err != nil {
+				return err
+			}
+			result.ID, result.Version = vendor.ID, vendor.Version
+			return nil
+		})
+		return results, nil
+	}
+
+	_, err := r.bulkWrite(ctx, models, ordered, results, func(writeErr mongo.WriteError) error {
+		return writeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		if results[i].Status != domain.BulkItemOK {
+			results[i].ID = primitive.NilObjectID
+		}
+	}
+	return results, nil
+}
+
+func (r *MongoDBVendorRepository) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) ([]domain.BulkItemResult, error) {
+	items := make([]pinnedWrite, len(updates))
+	for i, update := range updates {
+		items[i] = pinnedWrite{
+			id:      update.ID,
+			version: update.Version,
+			update:  replaceVendor(update.Vendor),
+		}
+	}
+
+	return r.bulkPinned(ctx, items, ordered, domain.VendorEventUpdated, func(i int) (int64, error) {
+		vendor, err := r.UpdateVendor(ctx, updates[i].ID, updates[i].Vendor, updates[i].Version)
+		if err != nil {
+			return 0, err
+		}
+		return vendor.Version, nil
+	})
+}
+
+func (r *MongoDBVendorRepository) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) ([]domain.BulkItemResult, error) {
+	now := timestamp()
+
+	items := make([]pinnedWrite, len(deletes))
+	for i, del := range deletes {
+		items[i] = pinnedWrite{
+			id:      del.ID,
+			version: del.Version,
+			update:  bson.M{"$set": bson.M{"deleted_at": now}},
+		}
+	}
+
+	return r.bulkPinned(ctx, items, ordered, domain.VendorEventDeleted, func(i int) (int64, error) {
+		return deletes[i].Version + 1, r.DeleteVendor(ctx, deletes[i].ID, deletes[i].Version)
+	})
+}
+
+// pinnedWrite updates a live vendor as long as it is still at version, like
+// compareAndSwap does for a single write. A zero version skips the check.
+type pinnedWrite struct {
+	id      primitive.ObjectID
+	version int64
+	update  bson.M
+}
+
+func (w pinnedWrite) model() *mongo.UpdateOneModel {
+	filter := bson.M{"_id": w.id, "deleted_at": nil}
+	if w.version > 0 {
+		filter["version"] = w.version
+	}
+
+	w.update["$inc"] = bson.M{"version": 1}
+	touch(w.update)
+
+	return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(w.update)
+}
+
+// bulkPinned makes pinned writes in one bulk write. The server only reports
+// how many of them matched in total, so when some missed, or the new version
+// of a vendor isn't known, one read of the vendors afterwards tells which
+// writes went through and which missed a vendor that moved on or is gone. A
+// miss isn't a write error, so even an ordered write carries on past it, and
+// the items after it are reported as written, as they were.
+//
+// With an outbox the vendors are always read back, inside the transaction,
+// for the records announcing them. A write error aborts that transaction, so
+// then the items are written one at a time by writeOne instead.
+func (r *MongoDBVendorRepository) bulkPinned(ctx context.Context, items []pinnedWrite, ordered bool, eventType domain.VendorEventType, writeOne func(i int) (int64, error)) ([]domain.BulkItemResult, error) {
+	models := make([]mongo.WriteModel, len(items))
+	results := make([]domain.BulkItemResult, len(items))
+	for i, item := range items {
+		models[i] = item.model()
+		results[i] = domain.BulkItemResult{Index: i, ID: item.id, Version: item.version + 1}
+	}
+
+	deleted := eventType == domain.VendorEventDeleted
+
+	if r.outbox != nil {
+		batched, err := r.writeBatch(ctx, models, ordered, func(ctx context.Context) ([]*domain.OutboxRecord, error) {
+			for i, item := range items {
+				results[i] = domain.BulkItemResult{Index: i, ID: item.id, Version: item.version + 1, Status: domain.BulkItemOK}
+			}
+			vendors, err := r.settlePinned(ctx, items, results, deleted)
+			if err != nil {
+				return nil, err
+			}
+
+			var records []*domain.OutboxRecord
+			for i, result := range results {
+				if result.Status == domain.BulkItemOK {
+					records = append(records, domain.NewOutboxRecord(eventType, domain.CommonVendorResponse(*vendors[items[i].id])))
+				}
+			}
+			return records, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if batched {
+			return results, nil
+		}
+
+		for i := range results {
+			results[i] = domain.BulkItemResult{ID: items[i].id}
+		}
+		writeEach(results, ordered, func(i int, result *domain.BulkItemResult) error {
+			version, err := writeOne(i)
+			result.Version = version
+			return err
+		})
+		return results, nil
+	}
+
+	summary, err := r.bulkWrite(ctx, models, ordered, results, func(writeErr mongo.WriteError) error {
+		return writeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	written := 0
+	unpinned := false
+	for i, result := range results {
+		if result.Status == domain.BulkItemOK {
+			written++
+			unpinned = unpinned || items[i].version == 0
+		}
+	}
+	if summary != nil && int(summary.MatchedCount) == written && !unpinned {
+		return results, nil
+	}
+
+	if _, err := r.settlePinned(ctx, items, results, deleted); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// settlePinned reads the vendors of the written items back after a bulk
+// write and tells from their state which writes went through. A write went
+// through when the vendor is now at the version after the pinned one, or, for
+// an unpinned write, when it is live or in the trash as the write left it.
+// Otherwise a vendor that is still live moved on, which is a version
+// conflict, and any other is gone. It returns the vendors read by ID.
+func (r *MongoDBVendorRepository) settlePinned(ctx context.Context, items []pinnedWrite, results []domain.BulkItemResult, deleted bool) (map[primitive.ObjectID]*domain.GetVendorResponse, error) {
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for i, result := range results {
+		if result.Status == domain.BulkItemOK {
+			ids = append(ids, items[i].id)
+		}
+	}
+
+	vendors := make(map[primitive.ObjectID]*domain.GetVendorResponse, len(ids))
+	if len(ids) == 0 {
+		return vendors, nil
+	}
+
+	readCtx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	cursor, err := r.collection.Find(readCtx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		slog.Error("error reading back bulk written vendors", utils.Err(err))
+		return nil, err
+	}
+	found, err := decodeAll[domain.GetVendorResponse](readCtx, cursor)
+	if err != nil {
+		slog.Error("error reading back bulk written vendors", utils.Err(err))
+		return nil, err
+	}
+	for _, vendor := range found {
+		vendors[vendor.ID] = vendor
+	}
+
+	for i := range results {
+		if results[i].Status != domain.BulkItemOK {
+			continue
+		}
+
+		vendor, version := vendors[items[i].id], items[i].version
+		switch {
+		case vendor != nil && (vendor.DeletedAt != nil) == deleted && (version == 0 || vendor.Version == version+1):
+			results[i].Version = vendor.Version
+		case vendor != nil && vendor.DeletedAt == nil && version > 0:
+			results[i].Fail(domain.ErrVersionConflict)
+		default:
+			results[i].Fail(domain.ErrVendorNotFound)
+		}
+	}
+	return vendors, nil
+}
+
+// writeBatch makes a bulk write and adds the outbox records that records
+// returns once it went through, all in one transaction, which the driver
+// retries as a whole on transient errors. A write error aborts the
+// transaction, so nothing is written then and writeBatch reports false,
+// leaving the caller to make the writes one at a time and learn which fail.
+func (r *MongoDBVendorRepository) writeBatch(ctx context.Context, models []mongo.WriteModel, ordered bool, records func(ctx context.Context) ([]*domain.OutboxRecord, error)) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		slog.Error("error starting session", utils.Err(err))
+		return false, err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered)); err != nil {
+			return nil, err
+		}
+
+		written, err := records(ctx)
+		if err != nil || len(written) == 0 {
+			return nil, err
+		}
+		documents := make([]interface{}, len(written))
+		for i, record := range written {
+			documents[i] = record
+		}
+		return r.outbox.InsertMany(ctx, documents)
+	})
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 && bulkErr.WriteConcernError == nil {
+		return false, nil
+	}
+	if err != nil {
+		slog.Error("error running bulk write", utils.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// writeEach makes the single writes of a bulk request on a repository with an
+// outbox in turn, and fills in the status of each result. Every item gets a
+// transaction of its own, since a failed write aborts the transaction it runs
+// in and would take the other items with it. An ordered request stops at the
+// first failing item and skips the rest.
+func writeEach(results []domain.BulkItemResult, ordered bool, write func(i int, result *domain.BulkItemResult) error) {
+	for i := range results {
+		results[i].Index = i
+	}
+
+	for i := range results {
+		results[i].Status = domain.BulkItemOK
+		if err := write(i, &results[i]); err != nil {
+			results[i].Fail(err)
+			if ordered {
+				break
+			}
+		}
+	}
+
+	if ordered {
+		domain.SkipAfterFailure(results)
+	}
+}
+
+// bulkWrite runs models and fills in the status of each result. Per item
+// write errors are translated by itemErr. In an ordered write the server
+// stops at the first failing item, so every later item is skipped. An error
+// is only returned when the write as a whole failed; otherwise the result of
+// the write is returned as far as the server reported it.
+func (r *MongoDBVendorRepository) bulkWrite(ctx context.Context, models []mongo.WriteModel, ordered bool, results []domain.BulkItemResult, itemErr func(mongo.WriteError) error) (*mongo.BulkWriteResult, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	for i := range results {
+		results[i].Status = domain.BulkItemOK
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			results[writeErr.Index].Fail(itemErr(writeErr.WriteError))
+		}
+		if ordered {
+			domain.SkipAfterFailure(results)
+		}
+		return result, nil
+	}
+	if err != nil {
+		slog.Error("error running bulk write", utils.Err(err))
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// vendorDocument is a vendor as stored. DuplicateKeys are derived from its
+// fields in every write that changes them, so that duplicates can be looked for
+// through an index rather than by reading every vendor.
+type vendorDocument struct {
+	domain.CommonVendorResponse `bson:",inline"`
+	DuplicateKeys               []string `bson:"duplicate_keys,omitempty"`
+}
+
+func storedVendor(vendor domain.CommonVendorResponse) vendorDocument {
+	return vendorDocument{CommonVendorResponse: vendor, DuplicateKeys: duplicateKeys(vendor)}
+}
+
+func duplicateKeys(vendor domain.CommonVendorResponse) []string {
+	return domain.DuplicateKeys((*domain.GetVendorResponse)(&vendor))
+}
+
+// requestDuplicateKeys derives the duplicate keys of the state a write is
+// about to store, so they are set in the same write as the fields.
+func requestDuplicateKeys(vendor domain.CommonVendorRequest) []string {
+	return domain.DuplicateKeys(&domain.GetVendorResponse{
+		Name:         vendor.Name,
+		Location:     vendor.Location,
+		PhoneNumbers: vendor.PhoneNumbers,
+		Websites:     vendor.Websites,
+		Coordinates:  vendor.Coordinates,
+	})
+}
+
+// duplicateKeyFields are the stored fields that duplicate keys derive from.
+var duplicateKeyFields = []string{"name", "location", "phone_numbers", "websites", "coordinates"}
+
+// patchesDuplicateKeys reports whether a patch changes any field that
+// duplicate keys derive from.
+func patchesDuplicateKeys(patch *domain.VendorPatch) bool {
+	for _, field := range duplicateKeyFields {
+		_, set := patch.Set[field]
+		_, push := patch.Push[field]
+		_, pull := patch.Pull[field]
+		if set || push || pull || slices.Contains(patch.Unset, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindDuplicateCandidates groups the live vendors by type and duplicate key
+// in the database and only reads the vendors of groups that are neither
+// alone nor larger than MaxDuplicateBlock.
+func (r *MongoDBVendorRepository) FindDuplicateCandidates(ctx context.Context, vendorType string) ([]*domain.GetVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": nil, "duplicate_keys": bson.M{"$exists": true}}
+	if vendorType != "" {
+		filter["type"] = vendorType
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$project", Value: bson.M{"type": 1, "duplicate_keys": 1}}},
+		{{Key: "$unwind", Value: "$duplicate_keys"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"type": "$type", "key": "$duplicate_keys"},
+			"ids": bson.M{"$push": "$_id"},
+		}}},
+		{{Key: "$match", Value: bson.M{
+			"ids.1": bson.M{"$exists": true},
+			fmt.Sprintf("ids.%d", domain.MaxDuplicateBlock): bson.M{"$exists": false},
+		}}},
+		{{Key: "$unwind", Value: "$ids"}},
+		{{Key: "$group", Value: bson.M{"_id": "$ids"}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.collection.Name(),
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "vendor",
+		}}},
+		{{Key: "$unwind", Value: "$vendor"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$vendor"}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		slog.Error("error finding duplicate vendor candidates", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.GetVendorResponse](ctx, cursor)
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Server error codes for a resume token that points before the oldest entry
+// left in the oplog.
+const (
+	changeStreamFatalError  = 280
+	changeStreamHistoryLost = 286
+)
+
+// eventBuffer bounds how many decoded events wait for a slow subscriber
+// before reading the change stream pauses.
+const eventBuffer = 16
+
+// MongoDBVendorEventSource turns change stream events on the vendor
+// collections into vendor events. Event ids are change stream resume tokens,
+// so a stream can be resumed for as long as the oplog still covers it.
+type MongoDBVendorEventSource struct {
+	database    *mongo.Database
+	collections []string
+}
+
+func NewMongoDBVendorEventSource(database *mongo.Database, collections []string) *MongoDBVendorEventSource {
+	return &MongoDBVendorEventSource{
+		database:    database,
+		collections: collections,
+	}
+}
+
+// Available reports whether the deployment supports change streams, which
+// standalone servers don't.
+func (s *MongoDBVendorEventSource) Available(ctx context.Context) bool {
+	stream, err := s.watch(ctx, options.ChangeStream())
+	if err != nil {
+		return false
+	}
+	stream.Close(ctx)
+	return true
+}
+
+func (s *MongoDBVendorEventSource) Subscribe(ctx context.Context, lastEventID string) (<-chan *domain.VendorEvent, error) {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if lastEventID != "" {
+		token, err := decodeResumeToken(lastEventID)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := s.watch(ctx, opts)
+	if err != nil {
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && (serverErr.HasErrorCode(changeStreamHistoryLost) || serverErr.HasErrorCode(changeStreamFatalError)) {
+			return nil, domain.ErrEventsExpired
+		}
+		slog.Error("error opening vendor change stream", utils.Err(err))
+		return nil, err
+	}
+
+	events := make(chan *domain.VendorEvent, eventBuffer)
+	go func() {
+		defer close(events)
+		defer stream.Close(context.Background())
+
+		for stream.Next(ctx) {
+			var change changeEvent
+			if err := stream.Decode(&change); err != nil {
+				slog.Error("error decoding vendor change event", utils.Err(err))
+				continue
+			}
+
+			event, ok := change.event()
+			if !ok {
+				continue
+			}
+			event.ID = encodeResumeToken(stream.ResumeToken())
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			slog.Error("error reading vendor change stream", utils.Err(err))
+		}
+	}()
+
+	return events, nil
+}
+
+// watch opens a change stream on the inserts and updates of the vendor
+// collections. Deletes only ever purge vendors that were already announced
+// as deleted when they were moved to the trash.
+func (s *MongoDBVendorEventSource) watch(ctx context.Context, opts *options.ChangeStreamOptions) (*mongo.ChangeStream, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ns.coll":       bson.M{"$in": s.collections},
+			"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}},
+		}}},
+	}
+	return s.database.Watch(ctx, pipeline, opts)
+}
+
+type changeEvent struct {
+	OperationType     string                       `bson:"operationType"`
+	ClusterTime       primitive.Timestamp          `bson:"clusterTime"`
+	FullDocument      *domain.CommonVendorResponse `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields   bson.M   `bson:"updatedFields"`
+		RemovedFields   []string `bson:"removedFields"`
+		TruncatedArrays []struct {
+			Field string `bson:"field"`
+		} `bson:"truncatedArrays"`
+	} `bson:"updateDescription"`
+}
+
+// event maps a change to the vendor event it stands for. Moving a vendor to
+// the trash sets deleted_at and restoring it removes the field again. An
+// update whose document has since been purged is skipped, and so is one that
+// only stored duplicate keys, which are no part of the vendor.
+func (c *changeEvent) event() (*domain.VendorEvent, bool) {
+	if c.FullDocument == nil || c.duplicateKeysOnly() {
+		return nil, false
+	}
+
+	eventType := domain.VendorEventUpdated
+	switch {
+	case c.OperationType == "insert":
+		eventType = domain.VendorEventCreated
+	case c.UpdateDescription.UpdatedFields["deleted_at"] != nil:
+		eventType = domain.VendorEventDeleted
+	case slices.Contains(c.UpdateDescription.RemovedFields, "deleted_at"):
+		eventType = domain.VendorEventCreated
+	case c.FullDocument.DeletedAt != nil:
+		return nil, false
+	}
+
+	return &domain.VendorEvent{
+		Type:      eventType,
+		VendorID:  c.FullDocument.ID,
+		Revision:  c.FullDocument.Version,
+		Timestamp: time.Unix(int64(c.ClusterTime.T), 0).UTC(),
+		Vendor:    domain.SnapshotOf(*c.FullDocument),
+	}, true
+}
+
+// duplicateKeysOnly reports whether an update wrote nothing but duplicate
+// keys, as migrations filling them in for existing vendors do. Array changes
+// may be reported per element, as duplicate_keys.<index>.
+func (c *changeEvent) duplicateKeysOnly() bool {
+	if c.OperationType != "update" {
+		return false
+	}
+
+	fields := slices.Clone(c.UpdateDescription.RemovedFields)
+	for field := range c.UpdateDescription.UpdatedFields {
+		fields = append(fields, field)
+	}
+	for _, field := range c.UpdateDescription.TruncatedArrays {
+		fields = append(fields, field.Field)
+	}
+	if len(fields) == 0 {
+		return false
+	}
+
+	for _, field := range fields {
+		if field != "duplicate_keys" && !strings.HasPrefix(field, "duplicate_keys.") {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeResumeToken(token bson.Raw) string {
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodeResumeToken(id string) (bson.Raw, error) {
+	token, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil || bson.Raw(token).Validate() != nil {
+		return nil, domain.ErrInvalidEventID
+	}
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pageFacet names the $facet output that holds the page of results.
+const pageFacet = "vendors"
+
+func (r *MongoDBVendorRepository) GetVendorFacets(ctx context.Context, fields []string, vendorType string, limit int) (domain.Facets, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": nil}
+	if vendorType != "" {
+		filter["type"] = vendorType
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facetPipelines(fields, limit)}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("error counting vendor facets", utils.Err(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return nil, cursor.Err()
+	}
+	return decodeFacets(cursor.Current, fields)
+}
+
+// aggregatePage runs pipeline followed by the page stages and decodes the
+// page. With facets requested, the page stages run inside a $facet stage
+// next to the facet counts, so the counts cover every document the pipeline
+// matched and still come back in the same round trip.
+func aggregatePage[T any](ctx context.Context, collection *mongo.Collection, pipeline, page mongo.Pipeline, fields []string, limit int) ([]*T, domain.Facets, error) {
+	aggregateOptions := options.Aggregate()
+	if maxTime, ok := serverTime(ctx); ok {
+		aggregateOptions.SetMaxTime(maxTime)
+	}
+
+	if len(fields) == 0 {
+		cursor, err := collection.Aggregate(ctx, append(pipeline, page...), aggregateOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		results, err := decodeAll[T](ctx, cursor)
+		return results, nil, err
+	}
+
+	facets := facetPipelines(fields, limit)
+	facets[pageFacet] = page
+
+	cursor, err := collection.Aggregate(ctx, append(pipeline, bson.D{{Key: "$facet", Value: facets}}), aggregateOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return nil, nil, cursor.Err()
+	}
+
+	var results []*T
+	if err := cursor.Current.Lookup(pageFacet).Unmarshal(&results); err != nil {
+		return nil, nil, err
+	}
+
+	counts, err := decodeFacets(cursor.Current, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results, counts, nil
+}
+
+// facetPipelines returns a $facet sub-pipeline per field that counts its
+// values, most common first, and keeps the first limit. List fields are
+// counted per element.
+func facetPipelines(fields []string, limit int) bson.M {
+	limit = domain.FacetLimit(limit)
+
+	facets := bson.M{}
+	for _, field := range fields {
+		var stages bson.A
+		if field != domain.FacetType {
+			stages = append(stages, bson.M{"$unwind": "$" + field})
+		}
+		stages = append(stages,
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		)
+		if limit > 0 {
+			stages = append(stages, bson.M{"$limit": limit})
+		}
+		facets[field] = stages
+	}
+	return facets
+}
+
+func decodeFacets(document bson.Raw, fields []string) (domain.Facets, error) {
+	facets := make(domain.Facets, len(fields))
+	for _, field := range fields {
+		counts := []domain.FacetCount{}
+		if err := document.Lookup(field).Unmarshal(&counts); err != nil {
+			return nil, err
+		}
+		facets[field] = counts
+	}
+	return facets, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exportBatchSize is how many vendors an export reads from the server at once.
+const exportBatchSize = 500
+
+func (r *MongoDBVendorRepository) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	vendors, err := r.findPage(ctx, compileFilter(filter), opts)
+	if err != nil {
+		slog.Error("error filtering vendors", utils.Err(err))
+		return nil, err
+	}
+
+	return vendors, nil
+}
+
+func (r *MongoDBVendorRepository) CountFilteredVendors(ctx context.Context, filter domain.VendorFilter) (int, error) {
+	return r.countVendors(ctx, compileFilter(filter), "")
+}
+
+// ExportVendors reads the vendors from a cursor, so only one batch is held in
+// memory at a time. An export runs for as long as the caller takes to consume
+// it, so it has no timeout of its own.
+func (r *MongoDBVendorRepository) ExportVendors(ctx context.Context, filter domain.VendorFilter, order domain.Sort, emit func(*domain.GetVendorResponse) error) error {
+	cursor, err := r.collection.Find(ctx, compileFilter(filter), options.Find().SetSort(sortSpec(order)).SetBatchSize(exportBatchSize))
+	if err != nil {
+		slog.Error("error exporting vendors", utils.Err(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var vendor domain.GetVendorResponse
+		if err := cursor.Decode(&vendor); err != nil {
+			return err
+		}
+		if err := emit(&vendor); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// compileFilter turns a filter into a single query on live vendors. A text
+// query must sit at the top level of the query, next to the other criteria.
+func compileFilter(filter domain.VendorFilter) bson.M {
+	query := bson.M{"deleted_at": nil}
+	var conditions []bson.M
+
+	if !filter.Tags.Empty() {
+		conditions = append(conditions, bson.M{"tags": valueCondition(filter.Tags)})
+	}
+	if !filter.Categories.Empty() {
+		conditions = append(conditions, bson.M{"categories": valueCondition(filter.Categories)})
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Query != "" {
+		query["$text"] = bson.M{"$search": filter.Query}
+	}
+
+	for _, field := range domain.PresenceFields {
+		present, ok := filter.Presence[field]
+		if ok {
+			conditions = append(conditions, presenceCondition(field, present))
+		}
+	}
+
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+	return query
+}
+
+// valueCondition matches a list field against m: $all, $in or $nin for its
+// values, and $nin for the excluded ones.
+func valueCondition(m domain.ValueMatch) bson.M {
+	condition := bson.M{}
+	excluded := m.Exclude
+	if len(m.Values) > 0 {
+		switch m.Mode {
+		case domain.MatchAny:
+			condition["$in"] = m.Values
+		case domain.MatchNone:
+			excluded = append(append([]string{}, m.Values...), excluded...)
+		default:
+			condition["$all"] = m.Values
+		}
+	}
+	if len(excluded) > 0 {
+		condition["$nin"] = excluded
+	}
+	return condition
+}
+
+// presenceCondition matches vendors whose field is set or unset. Lists are
+// set when they have a first element; strings when they are not empty.
+// Coordinates are unset rather than null when missing.
+func presenceCondition(field string, present bool) bson.M {
+	switch field {
+	case "cover", "location":
+		if present {
+			return bson.M{field: bson.M{"$nin": bson.A{"", nil}}}
+		}
+		return bson.M{field: bson.M{"$in": bson.A{"", nil}}}
+	case "coordinates":
+		return bson.M{field: bson.M{"$exists": present}}
+	default:
+		return bson.M{field + ".0": bson.M{"$exists": present}}
+	}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDBHistoryRepository struct {
+	collection *mongo.Collection
+	timeouts   config.Timeouts
+}
+
+func NewMongoDBHistoryRepository(collection *mongo.Collection, timeouts config.Timeouts) *MongoDBHistoryRepository {
+	return &MongoDBHistoryRepository{
+		collection: collection,
+		timeouts:   timeouts,
+	}
+}
+
+func (r *MongoDBHistoryRepository) AddEntry(ctx context.Context, entry *domain.VendorHistoryEntry) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		slog.Error("error inserting vendor history entry", utils.Err(err))
+		return err
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		entry.ID = id
+	}
+
+	return nil
+}
+
+func (r *MongoDBHistoryRepository) GetEntries(ctx context.Context, vendorID primitive.ObjectID) ([]*domain.VendorHistoryEntry, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"vendor_id": vendorID}, opts)
+	if err != nil {
+		slog.Error("error retrieving vendor history", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.VendorHistoryEntry](ctx, cursor)
+}
+
+func (r *MongoDBHistoryRepository) GetEntry(ctx context.Context, vendorID primitive.ObjectID, revision int64) (*domain.VendorHistoryEntry, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"vendor_id": vendorID, "revision": revision}
+
+	var entry domain.VendorHistoryEntry
+	err := r.collection.FindOne(ctx, filter).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRevisionNotFound
+		}
+		slog.Error("error getting vendor history entry", utils.Err(err))
+		return nil, err
+	}
+
+	return &entry, nil
+}
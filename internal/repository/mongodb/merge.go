@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (r *MongoDBVendorRepository) GetVendorByAlias(ctx context.Context, alias primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var vendor domain.GetVendorResponse
+	err := r.collection.FindOne(ctx, bson.M{"aliases": alias, "deleted_at": nil}).Decode(&vendor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("error getting vendor by alias", utils.Err(err))
+		return nil, err
+	}
+
+	return &vendor, nil
+}
+
+// MergeVendor replaces the vendor's fields like UpdateVendor does and adds
+// the aliases in the same write, so a merged ID never resolves to a vendor
+// that lacks its data.
+func (r *MongoDBVendorRepository) MergeVendor(ctx context.Context, id primitive.ObjectID, merged *domain.UpdateVendorRequest, aliases []primitive.ObjectID, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	update := replaceVendor(merged)
+	update["$addToSet"] = bson.M{"aliases": bson.M{"$each": aliases}}
+
+	updatedVendor, err := r.compareAndSwap(ctx, id, expectedVersion, update, domain.VendorEventUpdated)
+	if err != nil {
+		if !errors.Is(err, domain.ErrVendorNotFound) && !errors.Is(err, domain.ErrVersionConflict) {
+			slog.Error("error merging vendor: ", utils.Err(err))
+		}
+		return nil, err
+	}
+
+	updateResponse := domain.UpdateVendorResponse(*updatedVendor)
+
+	return &updateResponse, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDBOutboxRepository struct {
+	collection *mongo.Collection
+	timeouts   config.Timeouts
+}
+
+func NewMongoDBOutboxRepository(collection *mongo.Collection, timeouts config.Timeouts) *MongoDBOutboxRepository {
+	return &MongoDBOutboxRepository{
+		collection: collection,
+		timeouts:   timeouts,
+	}
+}
+
+func (r *MongoDBOutboxRepository) ClaimOutboxRecords(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	filter := bson.M{"available_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"available_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var claimed []*domain.OutboxRecord
+	for len(claimed) < limit {
+		var record domain.OutboxRecord
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&record)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			slog.Error("error claiming outbox record", utils.Err(err))
+			return claimed, err
+		}
+		claimed = append(claimed, &record)
+	}
+
+	return claimed, nil
+}
+
+// DeleteOutboxRecord doesn't mind a record that is already gone, which
+// happens when a relay outlived its lease and another one published it too.
+func (r *MongoDBOutboxRepository) DeleteOutboxRecord(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		slog.Error("error deleting outbox record", utils.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoDBOutboxRepository) ReleaseOutboxRecord(ctx context.Context, record *domain.OutboxRecord) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"attempts":     record.Attempts,
+		"last_error":   record.LastError,
+		"available_at": record.AvailableAt,
+	}}
+
+	if _, err := r.collection.UpdateByID(ctx, record.ID, update); err != nil {
+		slog.Error("error releasing outbox record", utils.Err(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// timestamp returns the current time at the millisecond precision MongoDB
+// stores, so a vendor returned from a write matches the one read back later.
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// touch makes update also set the vendor's updated_at.
+func touch(update bson.M) {
+	set, ok := update["$set"].(bson.M)
+	if !ok {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = timestamp()
+}
+
+// sortSpec orders by the fields of sort and then by _id.
+func sortSpec(sort domain.Sort) bson.D {
+	spec := bson.D{}
+	for _, field := range sort {
+		spec = append(spec, bson.E{Key: field.Field, Value: direction(field.Descending)})
+	}
+	return append(spec, bson.E{Key: "_id", Value: direction(sort.DescendingID())})
+}
+
+// keysetFilter matches the documents that sort after the position of a
+// cursor read with Sort.DecodeCursor: those that tie with it on the first i
+// sort fields and come after it on the next one, for every i, or tie on all
+// of them and come after it by _id.
+func keysetFilter(sort domain.Sort, cursor domain.Cursor) bson.M {
+	values := cursor.Values
+
+	var alternatives []bson.M
+	for i := 0; i <= len(sort); i++ {
+		condition := bson.M{}
+		for j := 0; j < i; j++ {
+			condition[sort[j].Field] = values[j]
+		}
+		if i < len(sort) {
+			condition[sort[i].Field] = bson.M{comparison(sort[i].Descending): values[i]}
+		} else {
+			condition["_id"] = bson.M{comparison(sort.DescendingID()): cursor.ID}
+		}
+		alternatives = append(alternatives, condition)
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return bson.M{"$or": alternatives}
+}
+
+func direction(descending bool) int {
+	if descending {
+		return -1
+	}
+	return 1
+}
+
+func comparison(descending bool) string {
+	if descending {
+		return "$lt"
+	}
+	return "$gt"
+}
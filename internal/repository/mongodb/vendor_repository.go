@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
+	"vendors/internal/config"
 	"vendors/internal/domain"
 	"vendors/pkg/lib/utils"
 
@@ -13,49 +16,98 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// MongoDBVendorRepository stores the vendors of one collection. With an
+// outbox, every vendor write also adds the event announcing it to the outbox
+// in the same transaction, which needs a replica set.
 type MongoDBVendorRepository struct {
 	collection *mongo.Collection
+	outbox     *mongo.Collection
+	timeouts   config.Timeouts
 }
 
-func NewMongoDBVendorRepository(collection *mongo.Collection) *MongoDBVendorRepository {
+func NewMongoDBVendorRepository(collection *mongo.Collection, timeouts config.Timeouts) *MongoDBVendorRepository {
 	return &MongoDBVendorRepository{
 		collection: collection,
+		timeouts:   timeouts,
 	}
 }
 
-func (r *MongoDBVendorRepository) GetAllVendors(page, pageSize int) ([]*domain.GetVendorResponse, error) {
-	skip := (page - 1) * pageSize
-
-	filter := bson.M{}
+// WithOutbox makes vendor writes add their events to outbox.
+func (r *MongoDBVendorRepository) WithOutbox(outbox *mongo.Collection) *MongoDBVendorRepository {
+	r.outbox = outbox
+	return r
+}
 
-	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(pageSize))
+// write runs fn, which makes one vendor write and returns the outbox record
+// announcing it. With an outbox the write and the record are committed in one
+// transaction, which the driver retries as a whole on transient errors, so fn
+// may run more than once. Without an outbox the record is dropped.
+func (r *MongoDBVendorRepository) write(ctx context.Context, fn func(ctx context.Context) (*domain.OutboxRecord, error)) error {
+	if r.outbox == nil {
+		_, err := fn(ctx)
+		return err
+	}
 
-	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	session, err := r.collection.Database().Client().StartSession()
 	if err != nil {
-		slog.Error("error retrieving vendors list", utils.Err(err))
-		return nil, err
+		slog.Error("error starting session", utils.Err(err))
+		return err
 	}
-	defer cursor.Close(context.Background())
+	defer session.EndSession(ctx)
 
-	var vendors []*domain.GetVendorResponse
-	for cursor.Next(context.Background()) {
-		var vendor domain.GetVendorResponse
-		if err := cursor.Decode(&vendor); err != nil {
-			slog.Error("Error decoding vendor: ", utils.Err(err))
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		record, err := fn(ctx)
+		if err != nil {
 			return nil, err
 		}
-		vendors = append(vendors, &vendor)
+		return r.outbox.InsertOne(ctx, record)
+	})
+	return err
+}
+
+// withTimeout derives the context a single operation runs under. The caller's
+// context still wins if it is cancelled first, e.g. when the client hangs up.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// serverTime returns the time left until the deadline of ctx, which queries
+// pass on as their maxTimeMS. Otherwise the server would carry on with a
+// query the driver has stopped waiting for.
+func serverTime(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return max(time.Until(deadline), time.Millisecond), true
+}
+
+func (r *MongoDBVendorRepository) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	vendors, err := r.findPage(ctx, bson.M{"deleted_at": nil}, opts)
+	if err != nil {
+		slog.Error("error retrieving vendors list", utils.Err(err))
+		return nil, err
 	}
 
 	return vendors, nil
 }
 
-func (r *MongoDBVendorRepository) GetTotalVendorsCount() (int, error) {
-	filter := bson.M{}
+func (r *MongoDBVendorRepository) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Count)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": nil}
+	if vendorType != "" {
+		filter["type"] = vendorType
+	}
 
-	totalVendors, err := r.collection.CountDocuments(context.Background(), filter)
+	totalVendors, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		slog.Error("error getting total vendor count", utils.Err(err))
 		return 0, err
@@ -64,12 +116,15 @@ func (r *MongoDBVendorRepository) GetTotalVendorsCount() (int, error) {
 	return int(totalVendors), nil
 }
 
-func (r *MongoDBVendorRepository) GetVendorByID(id primitive.ObjectID) (*domain.GetVendorResponse, error) {
-	filter := bson.M{"_id": id}
+func (r *MongoDBVendorRepository) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "deleted_at": nil}
 
 	var vendor domain.GetVendorResponse
 
-	err := r.collection.FindOne(context.Background(), filter).Decode(&vendor)
+	err := r.collection.FindOne(ctx, filter).Decode(&vendor)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -80,8 +135,39 @@ func (r *MongoDBVendorRepository) GetVendorByID(id primitive.ObjectID) (*domain.
 	return &vendor, nil
 }
 
-func (r *MongoDBVendorRepository) CreateVendor(vendor *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
-	c := domain.CreateVendorResponse{
+func (r *MongoDBVendorRepository) CreateVendor(ctx context.Context, vendor *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	c := newVendorDocument(vendor)
+
+	err := r.write(ctx, func(ctx context.Context) (*domain.OutboxRecord, error) {
+		result, err := r.collection.InsertOne(ctx, storedVendor(domain.CommonVendorResponse(c)))
+		if err != nil {
+			slog.Error("error inserting vendor document: %v", utils.Err(err))
+			return nil, err
+		}
+
+		insertedID, ok := result.InsertedID.(primitive.ObjectID)
+		if !ok {
+			slog.Error("error getting inserted vendor ID")
+			return nil, errors.New("error getting inserted vendor ID")
+		}
+
+		c.ID = insertedID
+
+		return domain.NewOutboxRecord(domain.VendorEventCreated, domain.CommonVendorResponse(c)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func newVendorDocument(vendor *domain.CreateVendorRequest) domain.CreateVendorResponse {
+	now := timestamp()
+	return domain.CreateVendorResponse{
 		Cover:          vendor.Cover,
 		Type:           vendor.Type,
 		Name:           vendor.Name,
@@ -92,26 +178,33 @@ func (r *MongoDBVendorRepository) CreateVendor(vendor *domain.CreateVendorReques
 		Media:          vendor.Media,
 		Tags:           vendor.Tags,
 		Categories:     vendor.Categories,
+		Coordinates:    vendor.Coordinates,
+		Version:        1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
+}
 
-	result, err := r.collection.InsertOne(context.Background(), c)
+func (r *MongoDBVendorRepository) UpdateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	updatedVendor, err := r.compareAndSwap(ctx, id, expectedVersion, replaceVendor(update), domain.VendorEventUpdated)
 	if err != nil {
-		slog.Error("error inserting vendor document: %v", utils.Err(err))
+		if !errors.Is(err, domain.ErrVendorNotFound) && !errors.Is(err, domain.ErrVersionConflict) {
+			slog.Error("error updating vendor: ", utils.Err(err))
+		}
 		return nil, err
 	}
 
-	insertedID, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		slog.Error("error getting inserted vendor ID")
-		return nil, errors.New("error getting inserted vendor ID")
-	}
-
-	c.ID = insertedID
+	updateResponse := domain.UpdateVendorResponse(*updatedVendor)
 
-	return &c, nil
+	return &updateResponse, nil
 }
 
-func (r *MongoDBVendorRepository) UpdateVendor(id primitive.ObjectID, update *domain.UpdateVendorRequest) (*domain.UpdateVendorResponse, error) {
+// replaceVendor builds the update that overwrites every vendor field, along
+// with the duplicate keys they give.
+func replaceVendor(update *domain.UpdateVendorRequest) bson.M {
 	updateFields := bson.M{
 		"$set": bson.M{
 			"cover":           update.Cover,
@@ -124,120 +217,670 @@ func (r *MongoDBVendorRepository) UpdateVendor(id primitive.ObjectID, update *do
 			"media":           update.Media,
 			"tags":            update.Tags,
 			"categories":      update.Categories,
+			"duplicate_keys":  requestDuplicateKeys(domain.CommonVendorRequest(*update)),
 		},
 	}
 
-	filter := bson.M{"_id": id}
+	// Vendors without coordinates must not carry a null location, so that
+	// the 2dsphere index can keep skipping them.
+	if update.Coordinates != nil {
+		updateFields["$set"].(bson.M)["coordinates"] = update.Coordinates
+	} else {
+		updateFields["$unset"] = bson.M{"coordinates": ""}
+	}
+
+	return updateFields
+}
+
+// patchAttempts bounds how often PatchVendor reads the current state again
+// after a concurrent write without an expected version.
+const patchAttempts = 3
+
+// PatchVendor writes only the fields the patch touches, appending to and
+// removing from lists in place rather than overwriting them. A patch of the
+// fields duplicate keys derive from is applied to the current state first to
+// set the keys in the same write, which is therefore pinned to that state.
+// Without an expected version, a write that loses the race is tried again.
+func (r *MongoDBVendorRepository) PatchVendor(ctx context.Context, id primitive.ObjectID, patch *domain.VendorPatch, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		update := patchVendor(patch)
+		version := expectedVersion
+
+		if patchesDuplicateKeys(patch) {
+			current, err := r.GetVendorByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil {
+				return nil, domain.ErrVendorNotFound
+			}
+			if version == 0 {
+				version = current.Version
+			}
+
+			patched := patch.Apply(domain.SnapshotOf(domain.CommonVendorResponse(*current)))
+			set, ok := update["$set"].(bson.M)
+			if !ok {
+				set = bson.M{}
+				update["$set"] = set
+			}
+			set["duplicate_keys"] = requestDuplicateKeys(patched)
+		}
+
+		updatedVendor, err := r.compareAndSwap(ctx, id, version, update, domain.VendorEventUpdated)
+		if errors.Is(err, domain.ErrVersionConflict) && expectedVersion == 0 && attempt < patchAttempts {
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, domain.ErrVendorNotFound) && !errors.Is(err, domain.ErrVersionConflict) {
+				slog.Error("error patching vendor: ", utils.Err(err))
+			}
+			return nil, err
+		}
+
+		updateResponse := domain.UpdateVendorResponse(*updatedVendor)
+
+		return &updateResponse, nil
+	}
+}
+
+func patchVendor(patch *domain.VendorPatch) bson.M {
+	update := bson.M{}
+
+	if len(patch.Set) > 0 {
+		set := bson.M{}
+		for field, value := range patch.Set {
+			set[field] = value
+		}
+		update["$set"] = set
+	}
 
-	_, err := r.collection.UpdateOne(context.Background(), filter, updateFields)
+	if len(patch.Unset) > 0 {
+		unset := bson.M{}
+		for _, field := range patch.Unset {
+			unset[field] = ""
+		}
+		update["$unset"] = unset
+	}
+
+	if len(patch.Push) > 0 {
+		push := bson.M{}
+		for field, values := range patch.Push {
+			push[field] = bson.M{"$each": values}
+		}
+		update["$push"] = push
+	}
+
+	if len(patch.Pull) > 0 {
+		pull := bson.M{}
+		for field, values := range patch.Pull {
+			pull[field] = bson.M{"$in": values}
+		}
+		update["$pull"] = pull
+	}
+
+	return update
+}
+
+func (r *MongoDBVendorRepository) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"deleted_at": timestamp()}}
+
+	_, err := r.compareAndSwap(ctx, id, expectedVersion, update, domain.VendorEventDeleted)
 	if err != nil {
-		slog.Error("error updating vendor: ", utils.Err(err))
-		return nil, err
+		if !errors.Is(err, domain.ErrVendorNotFound) && !errors.Is(err, domain.ErrVersionConflict) {
+			slog.Error("Error deleting vendor: ", utils.Err(err))
+		}
+		return err
 	}
 
-	updatedVendor, err := r.GetVendorByID(id)
+	return nil
+}
+
+// compareAndSwap applies update to the live vendor with the given ID as long
+// as it is still at expectedVersion, and bumps the version and updated_at in
+// the same write. A zero expectedVersion skips the check. It returns the
+// vendor as updated, and announces the change as an event of eventType.
+func (r *MongoDBVendorRepository) compareAndSwap(ctx context.Context, id primitive.ObjectID, expectedVersion int64, update bson.M, eventType domain.VendorEventType) (*domain.GetVendorResponse, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+	if expectedVersion > 0 {
+		filter["version"] = expectedVersion
+	}
+
+	update["$inc"] = bson.M{"version": 1}
+	touch(update)
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var vendor domain.GetVendorResponse
+	err := r.write(ctx, func(ctx context.Context) (*domain.OutboxRecord, error) {
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&vendor)
+		if err == mongo.ErrNoDocuments {
+			if expectedVersion > 0 {
+				current, err := r.GetVendorByID(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				if current != nil {
+					return nil, domain.ErrVersionConflict
+				}
+			}
+			return nil, domain.ErrVendorNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return domain.NewOutboxRecord(eventType, domain.CommonVendorResponse(vendor)), nil
+	})
 	if err != nil {
-		slog.Error("error fetching updated vendor: ", utils.Err(err))
 		return nil, err
 	}
 
-	updateResponse := &domain.UpdateVendorResponse{
-		ID:             updatedVendor.ID,
-		Cover:          updatedVendor.Cover,
-		Type:           updatedVendor.Type,
-		Name:           updatedVendor.Name,
-		Location:       updatedVendor.Location,
-		PhoneNumbers:   updatedVendor.PhoneNumbers,
-		Websites:       updatedVendor.Websites,
-		SocialNetworks: updatedVendor.SocialNetworks,
-		Media:          updatedVendor.Media,
-		Tags:           updatedVendor.Tags,
-		Categories:     updatedVendor.Categories,
+	return &vendor, nil
+}
+
+func (r *MongoDBVendorRepository) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	vendors, err := r.findPage(ctx, bson.M{"deleted_at": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		slog.Error("error retrieving deleted vendors", utils.Err(err))
+		return nil, err
 	}
 
-	return updateResponse, nil
+	return vendors, nil
 }
 
-func (r *MongoDBVendorRepository) DeleteVendor(id primitive.ObjectID) error {
-	filter := bson.M{"_id": id}
+func (r *MongoDBVendorRepository) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Count)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil}}
 
-	result, err := r.collection.DeleteOne(context.Background(), filter)
+	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		slog.Error("Error deleting vendor: ", utils.Err(err))
-		return err
+		slog.Error("error getting deleted vendor count", utils.Err(err))
+		return 0, err
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("vendor not found")
+	return int(total), nil
+}
+
+func (r *MongoDBVendorRepository) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$inc":   bson.M{"version": 1},
 	}
+	touch(update)
 
-	return nil
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	return r.write(ctx, func(ctx context.Context) (*domain.OutboxRecord, error) {
+		var vendor domain.CommonVendorResponse
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&vendor)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrVendorNotFound
+		}
+		if err != nil {
+			slog.Error("error restoring vendor", utils.Err(err))
+			return nil, err
+		}
+
+		return domain.NewOutboxRecord(domain.VendorEventCreated, vendor), nil
+	})
 }
 
-func (r *MongoDBVendorRepository) SearchVendors(query string, page int, pageSize int) ([]*domain.GetVendorResponse, error) {
-	offset := (page - 1) * pageSize
+func (r *MongoDBVendorRepository) PurgeDeletedVendors(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
-	options := options.Find().SetSkip(int64(offset)).SetLimit(int64(pageSize))
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil, "$lte": deletedBefore}}
 
-	filter := bson.M{
-		"$or": []interface{}{
-			bson.M{"name": bson.M{"$regex": query, "$options": "i"}},
-		},
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		slog.Error("error purging deleted vendors", utils.Err(err))
+		return 0, err
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+func (r *MongoDBVendorRepository) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	if query.Mode != domain.SearchModeText {
+		ctx, cancel := withSearchTime(ctx, query)
+		defer cancel()
+
+		list, err := r.findPage(ctx, searchFilter(query), opts)
+		if err != nil {
+			return nil, searchError(query, err)
+		}
+
+		results := &domain.SearchVendorList{NextCursor: list.NextCursor, Facets: list.Facets}
+		for _, vendor := range list.Vendors {
+			results.Vendors = append(results.Vendors, &domain.SearchVendorResponse{GetVendorResponse: *vendor})
+		}
+		return results, nil
+	}
+
+	// A text score can't be referenced from a find filter, so the keyset
+	// condition on it has to run as a later stage of an aggregation. Results
+	// are ordered by score unless a sort was requested.
+	match := searchFilter(query)
+	if opts.Type != "" {
+		match["type"] = opts.Type
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+	}
+
+	sorted := len(opts.Sort) > 0
+
+	var page mongo.Pipeline
+
+	if opts.Cursor != "" && sorted {
+		cursor, err := opts.Sort.DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, bson.D{{Key: "$match", Value: keysetFilter(opts.Sort, cursor)}})
+	} else if opts.Cursor != "" {
+		cursor, err := domain.DecodeCursor(opts.Cursor, 1)
+		if err != nil {
+			return nil, err
+		}
+		score, ok := cursor.Values[0].(float64)
+		if !ok {
+			return nil, domain.ErrInvalidCursor
+		}
+		page = append(page, bson.D{{Key: "$match", Value: bson.M{"$or": []bson.M{
+			{"score": bson.M{"$lt": score}},
+			{"score": score, "_id": bson.M{"$gt": cursor.ID}},
+		}}}})
 	}
 
-	cursor, err := r.collection.Find(context.Background(), filter, options)
+	order := bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}
+	if sorted {
+		order = sortSpec(opts.Sort)
+	}
+	page = append(page, bson.D{{Key: "$sort", Value: order}})
+	if opts.Cursor == "" {
+		page = append(page, bson.D{{Key: "$skip", Value: (opts.Page - 1) * opts.PageSize}})
+	}
+	page = append(page, bson.D{{Key: "$limit", Value: opts.PageSize + 1}})
+
+	vendors, facets, err := aggregatePage[domain.SearchVendorResponse](ctx, r.collection, pipeline, page, opts.Facets, opts.FacetLimit)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
 
-	var vendors []*domain.GetVendorResponse
+	results := &domain.SearchVendorList{Vendors: vendors, Facets: facets}
+	if len(vendors) > opts.PageSize {
+		last := vendors[opts.PageSize-1]
+		results.Vendors = vendors[:opts.PageSize]
+		values := []interface{}{last.Score}
+		if sorted {
+			values = opts.Sort.Values(&last.GetVendorResponse)
+		}
+		results.NextCursor = domain.EncodeCursor(domain.Cursor{Values: values, ID: last.ID})
+	}
+
+	return results, nil
+}
+
+func (r *MongoDBVendorRepository) CountSearchVendors(ctx context.Context, query domain.SearchQuery, vendorType string) (int, error) {
+	ctx, cancel := withSearchTime(ctx, query)
+	defer cancel()
+
+	total, err := r.countVendors(ctx, searchFilter(query), vendorType)
+	return total, searchError(query, err)
+}
+
+// regexSearchTime caps how long the server may spend on a regex search.
+// MongoDB matches $regex with PCRE, which backtracks, so a pattern such as
+// (a+)+$ can take exponential time on a name it almost matches.
+const regexSearchTime = 2 * time.Second
 
-	for cursor.Next(context.Background()) {
-		var vendor domain.GetVendorResponse
-		if err := cursor.Decode(&vendor); err != nil {
+// withSearchTime bounds a search in regex mode by regexSearchTime, on top of
+// the deadline ctx already has.
+func withSearchTime(ctx context.Context, query domain.SearchQuery) (context.Context, context.CancelFunc) {
+	if query.Mode != domain.SearchModeRegex {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, regexSearchTime)
+}
+
+// searchError reports a regex search that ran out of time as an invalid
+// query, since it is the pattern that is too costly to match.
+func searchError(query domain.SearchQuery, err error) error {
+	if query.Mode == domain.SearchModeRegex && mongo.IsTimeout(err) {
+		return fmt.Errorf("%w: pattern takes too long to match", domain.ErrInvalidSearchQuery)
+	}
+	return err
+}
+
+// searchFilter selects the live vendors matching a search query.
+func searchFilter(query domain.SearchQuery) bson.M {
+	if query.Mode == domain.SearchModeText {
+		return bson.M{"$text": bson.M{"$search": query.Text}, "deleted_at": nil}
+	}
+	return bson.M{"name": bson.M{"$regex": query.Pattern(), "$options": "i"}, "deleted_at": nil}
+}
+
+// countVendors counts the documents matching filter, narrowed to vendorType
+// when one is given. The driver runs the count as a single aggregation.
+func (r *MongoDBVendorRepository) countVendors(ctx context.Context, filter bson.M, vendorType string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Count)
+	defer cancel()
+
+	if vendorType != "" {
+		filter["type"] = vendorType
+	}
+
+	countOptions := options.Count()
+	if maxTime, ok := serverTime(ctx); ok {
+		countOptions.SetMaxTime(maxTime)
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter, countOptions)
+	if err != nil {
+		slog.Error("error counting vendors", utils.Err(err))
+		return 0, err
+	}
+
+	return int(total), nil
+}
+
+func (r *MongoDBVendorRepository) FindVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          point,
+			"key":           "coordinates",
+			"distanceField": "distance",
+			"maxDistance":   radiusMeters,
+			"spherical":     true,
+			"query":         bson.M{"deleted_at": nil},
+		}}},
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := domain.DecodeCursor(opts.Cursor, 1)
+		if err != nil {
 			return nil, err
 		}
-		vendors = append(vendors, &vendor)
+		distance, ok := cursor.Values[0].(float64)
+		if !ok {
+			return nil, domain.ErrInvalidCursor
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": []bson.M{
+			{"distance": bson.M{"$gt": distance}},
+			{"distance": distance, "_id": bson.M{"$gt": cursor.ID}},
+		}}}})
 	}
 
-	if err := cursor.Err(); err != nil {
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "distance", Value: 1}, {Key: "_id", Value: 1}}}})
+	if opts.Cursor == "" {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: (opts.Page - 1) * opts.PageSize}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: opts.PageSize + 1}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("error finding vendors near point", utils.Err(err))
 		return nil, err
 	}
 
-	return vendors, nil
+	vendors, err := decodeAll[domain.NearbyVendorResponse](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &domain.NearbyVendorList{Vendors: vendors}
+	if len(vendors) > opts.PageSize {
+		last := vendors[opts.PageSize-1]
+		results.Vendors = vendors[:opts.PageSize]
+		results.NextCursor = domain.EncodeCursor(domain.Cursor{Values: []interface{}{last.Distance}, ID: last.ID})
+	}
+
+	return results, nil
 }
 
-func (r *MongoDBVendorRepository) FilterVendorsByTags(tags []string, page int, pageSize int) ([]*domain.GetVendorResponse, error) {
-	offset := (page - 1) * pageSize
+func (r *MongoDBVendorRepository) CountVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Count)
+	defer cancel()
 
-	var tagConditions []bson.M
-	for _, tag := range tags {
-		tagConditions = append(tagConditions, bson.M{"tags": tag})
+	// $near can't be used when counting, but a sphere of the same radius
+	// selects the same documents.
+	filter := bson.M{
+		"coordinates": bson.M{"$geoWithin": bson.M{
+			"$centerSphere": bson.A{point.Coordinates, radiusMeters / domain.EarthRadiusMeters},
+		}},
+		"deleted_at": nil,
 	}
 
-	filter := bson.M{"$and": tagConditions}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		slog.Error("error counting vendors near point", utils.Err(err))
+		return 0, err
+	}
 
-	options := options.Find().SetSkip(int64(offset)).SetLimit(int64(pageSize))
+	return int(total), nil
+}
 
-	cursor, err := r.collection.Find(context.Background(), filter, options)
+// mapProjection keeps only the fields needed to draw a vendor on a map.
+var mapProjection = bson.M{"name": 1, "type": 1, "cover": 1, "coordinates": 1}
+
+func areaFilter(area *domain.GeoPolygon) bson.M {
+	return bson.M{
+		"coordinates": bson.M{"$geoWithin": bson.M{"$geometry": area}},
+		"deleted_at":  nil,
+	}
+}
+
+func (r *MongoDBVendorRepository) FindVendorsInArea(ctx context.Context, area *domain.GeoPolygon, limit int) ([]*domain.MapVendor, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetProjection(mapProjection).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, areaFilter(area), findOptions)
+	if err != nil {
+		slog.Error("error finding vendors in area", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.MapVendor](ctx, cursor)
+}
+
+// ClusterVendorsInArea groups the vendors in area into square cells of
+// cellSize degrees, aligned to the origin so panning keeps clusters stable.
+func (r *MongoDBVendorRepository) ClusterVendorsInArea(ctx context.Context, area *domain.GeoPolygon, cellSize float64) ([]*domain.MapCluster, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	lng := bson.M{"$arrayElemAt": bson.A{"$coordinates.coordinates", 0}}
+	lat := bson.M{"$arrayElemAt": bson.A{"$coordinates.coordinates", 1}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: areaFilter(area)}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"x": bson.M{"$floor": bson.M{"$divide": bson.A{lng, cellSize}}},
+				"y": bson.M{"$floor": bson.M{"$divide": bson.A{lat, cellSize}}},
+			},
+			"count": bson.M{"$sum": 1},
+			"lng":   bson.M{"$avg": lng},
+			"lat":   bson.M{"$avg": lat},
+			"vendor": bson.M{"$first": bson.M{
+				"_id":         "$_id",
+				"name":        "$name",
+				"type":        "$type",
+				"cover":       "$cover",
+				"coordinates": "$coordinates",
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.y", Value: 1}, {Key: "_id.x", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
+		slog.Error("error clustering vendors in area", utils.Err(err))
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
+
+	type clusterRow struct {
+		Count  int              `bson:"count"`
+		Lng    float64          `bson:"lng"`
+		Lat    float64          `bson:"lat"`
+		Vendor domain.MapVendor `bson:"vendor"`
+	}
+
+	rows, err := decodeAll[clusterRow](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*domain.MapCluster, 0, len(rows))
+	for _, row := range rows {
+		vendor := row.Vendor
+		clusters = append(clusters, &domain.MapCluster{
+			Count:       row.Count,
+			Coordinates: domain.NewGeoPoint(row.Lng, row.Lat),
+			Vendor:      &vendor,
+		})
+	}
+
+	return clusters, nil
+}
+
+func (r *MongoDBVendorRepository) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"tags": valueCondition(tags), "deleted_at": nil}
+
+	return r.findPage(ctx, filter, opts)
+}
+
+func (r *MongoDBVendorRepository) CountVendorsByTags(ctx context.Context, tags domain.ValueMatch, vendorType string) (int, error) {
+	return r.countVendors(ctx, bson.M{"tags": valueCondition(tags), "deleted_at": nil}, vendorType)
+}
+
+// findPage returns one page of the vendors matching filter in _id order. It
+// reads one document past the page to learn whether a next cursor is needed.
+func (r *MongoDBVendorRepository) findPage(ctx context.Context, filter bson.M, opts domain.ListOptions) (*domain.VendorList, error) {
+	if opts.Type != "" {
+		filter["type"] = opts.Type
+	}
+
+	var after bson.M
+	if opts.Cursor != "" {
+		cursor, err := opts.Sort.DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = keysetFilter(opts.Sort, cursor)
+	}
 
 	var vendors []*domain.GetVendorResponse
-	for cursor.Next(context.Background()) {
-		var vendor domain.GetVendorResponse
-		if err := cursor.Decode(&vendor); err != nil {
+	var facets domain.Facets
+	var err error
+	if len(opts.Facets) > 0 {
+		vendors, facets, err = r.aggregateFacetedPage(ctx, filter, after, opts)
+	} else {
+		vendors, err = r.findVendors(ctx, filter, after, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &domain.VendorList{Vendors: vendors, Facets: facets}
+	if len(vendors) > opts.PageSize {
+		last := vendors[opts.PageSize-1]
+		list.Vendors = vendors[:opts.PageSize]
+		list.NextCursor = domain.EncodeCursor(domain.Cursor{Values: opts.Sort.Values(last), ID: last.ID})
+	}
+
+	return list, nil
+}
+
+// findVendors reads one page, plus one vendor to tell whether more follow,
+// of the vendors matching filter that sort after the keyset condition.
+func (r *MongoDBVendorRepository) findVendors(ctx context.Context, filter, after bson.M, opts domain.ListOptions) ([]*domain.GetVendorResponse, error) {
+	findOptions := options.Find().
+		SetSort(sortSpec(opts.Sort)).
+		SetLimit(int64(opts.PageSize + 1))
+	if maxTime, ok := serverTime(ctx); ok {
+		findOptions.SetMaxTime(maxTime)
+	}
+
+	if after != nil {
+		filter = bson.M{"$and": []bson.M{filter, after}}
+	} else {
+		findOptions.SetSkip(int64((opts.Page - 1) * opts.PageSize))
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAll[domain.GetVendorResponse](ctx, cursor)
+}
+
+// aggregateFacetedPage reads the same page as findVendors together with the
+// facet counts of every vendor matching filter.
+func (r *MongoDBVendorRepository) aggregateFacetedPage(ctx context.Context, filter, after bson.M, opts domain.ListOptions) ([]*domain.GetVendorResponse, domain.Facets, error) {
+	var page mongo.Pipeline
+	if after != nil {
+		page = append(page, bson.D{{Key: "$match", Value: after}})
+	}
+	page = append(page, bson.D{{Key: "$sort", Value: sortSpec(opts.Sort)}})
+	if after == nil {
+		page = append(page, bson.D{{Key: "$skip", Value: (opts.Page - 1) * opts.PageSize}})
+	}
+	page = append(page, bson.D{{Key: "$limit", Value: opts.PageSize + 1}})
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	return aggregatePage[domain.GetVendorResponse](ctx, r.collection, pipeline, page, opts.Facets, opts.FacetLimit)
+}
+
+func decodeAll[T any](ctx context.Context, cursor *mongo.Cursor) ([]*T, error) {
+	defer cursor.Close(ctx)
+
+	var results []*T
+	for cursor.Next(ctx) {
+		var result T
+		if err := cursor.Decode(&result); err != nil {
 			return nil, err
 		}
-		vendors = append(vendors, &vendor)
+		results = append(results, &result)
 	}
 
 	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
 
-	return vendors, nil
+	return results, nil
 }
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"vendors/internal/domain"
@@ -17,9 +18,9 @@ func TestGetAllVendors(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
-	page := 1
-	pageSize := 10
+	opts := domain.ListOptions{Page: 1, PageSize: 10}
 
 	vendor := &domain.GetVendorResponse{
 		ID: primitive.NewObjectID(),
@@ -28,25 +29,25 @@ func TestGetAllVendors(t *testing.T) {
 	tests := []struct {
 		name  string
 		setup func()
-		check func([]*domain.GetVendorResponse, error)
+		check func(*domain.VendorList, error)
 	}{
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().GetAllVendors(page, pageSize).Return([]*domain.GetVendorResponse{vendor}, nil)
+				mockVendorRepo.EXPECT().GetAllVendors(ctx, opts).Return(&domain.VendorList{Vendors: []*domain.GetVendorResponse{vendor}}, nil)
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, response)
-				assert.Equal(t, vendor, response[0])
+				assert.Equal(t, vendor, response.Vendors[0])
 			},
 		},
 		{
 			name: "Find error",
 			setup: func() {
-				mockVendorRepo.EXPECT().GetAllVendors(page, pageSize).Return(nil, errors.New("find error"))
+				mockVendorRepo.EXPECT().GetAllVendors(ctx, opts).Return(nil, errors.New("find error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "find error", err.Error())
@@ -55,9 +56,9 @@ func TestGetAllVendors(t *testing.T) {
 		{
 			name: "Decode error",
 			setup: func() {
-				mockVendorRepo.EXPECT().GetAllVendors(page, pageSize).Return(nil, errors.New("decode error"))
+				mockVendorRepo.EXPECT().GetAllVendors(ctx, opts).Return(nil, errors.New("decode error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "decode error", err.Error())
@@ -68,7 +69,7 @@ func TestGetAllVendors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			response, err := mockVendorRepo.GetAllVendors(page, pageSize)
+			response, err := mockVendorRepo.GetAllVendors(ctx, opts)
 			tt.check(response, err)
 		})
 	}
@@ -79,6 +80,7 @@ func TestGetVendorByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
 	id := primitive.NewObjectID()
 	vendor := &domain.GetVendorResponse{
@@ -93,7 +95,7 @@ func TestGetVendorByID(t *testing.T) {
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().GetVendorByID(id).Return(vendor, nil)
+				mockVendorRepo.EXPECT().GetVendorByID(ctx, id).Return(vendor, nil)
 			},
 			check: func(vendor *domain.GetVendorResponse, err error) {
 				assert.NoError(t, err)
@@ -104,7 +106,7 @@ func TestGetVendorByID(t *testing.T) {
 		{
 			name: "No document found",
 			setup: func() {
-				mockVendorRepo.EXPECT().GetVendorByID(id).Return(nil, mongo.ErrNoDocuments)
+				mockVendorRepo.EXPECT().GetVendorByID(ctx, id).Return(nil, mongo.ErrNoDocuments)
 			},
 			check: func(vendor *domain.GetVendorResponse, err error) {
 				assert.Error(t, err)
@@ -118,7 +120,7 @@ func TestGetVendorByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			vendor, err := mockVendorRepo.GetVendorByID(id)
+			vendor, err := mockVendorRepo.GetVendorByID(ctx, id)
 			tt.check(vendor, err)
 		})
 	}
@@ -129,6 +131,7 @@ func TestCreateVendor(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
 	vendorRequest := &domain.CreateVendorRequest{
 		Cover:          "cover",
@@ -165,7 +168,7 @@ func TestCreateVendor(t *testing.T) {
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().CreateVendor(vendorRequest).Return(vendorResponse, nil)
+				mockVendorRepo.EXPECT().CreateVendor(ctx, vendorRequest).Return(vendorResponse, nil)
 			},
 			check: func(response *domain.CreateVendorResponse, err error) {
 				assert.NoError(t, err)
@@ -176,7 +179,7 @@ func TestCreateVendor(t *testing.T) {
 		{
 			name: "InsertOne error",
 			setup: func() {
-				mockVendorRepo.EXPECT().CreateVendor(vendorRequest).Return(nil, errors.New("error inserting vendor document"))
+				mockVendorRepo.EXPECT().CreateVendor(ctx, vendorRequest).Return(nil, errors.New("error inserting vendor document"))
 			},
 			check: func(response *domain.CreateVendorResponse, err error) {
 				assert.Error(t, err)
@@ -187,7 +190,7 @@ func TestCreateVendor(t *testing.T) {
 		{
 			name: "InsertedID type assertion error",
 			setup: func() {
-				mockVendorRepo.EXPECT().CreateVendor(vendorRequest).Return(nil, errors.New("error getting inserted vendor ID"))
+				mockVendorRepo.EXPECT().CreateVendor(ctx, vendorRequest).Return(nil, errors.New("error getting inserted vendor ID"))
 			},
 			check: func(response *domain.CreateVendorResponse, err error) {
 				assert.Error(t, err)
@@ -200,7 +203,7 @@ func TestCreateVendor(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			response, err := mockVendorRepo.CreateVendor(vendorRequest)
+			response, err := mockVendorRepo.CreateVendor(ctx, vendorRequest)
 			tt.check(response, err)
 		})
 	}
@@ -211,6 +214,7 @@ func TestDeleteVendor(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
 	id := primitive.NewObjectID()
 
@@ -222,7 +226,7 @@ func TestDeleteVendor(t *testing.T) {
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().DeleteVendor(id).Return(nil)
+				mockVendorRepo.EXPECT().DeleteVendor(ctx, id, int64(0)).Return(nil)
 			},
 			check: func(err error) {
 				assert.NoError(t, err)
@@ -231,7 +235,7 @@ func TestDeleteVendor(t *testing.T) {
 		{
 			name: "Vendor not found",
 			setup: func() {
-				mockVendorRepo.EXPECT().DeleteVendor(id).Return(errors.New("vendor not found"))
+				mockVendorRepo.EXPECT().DeleteVendor(ctx, id, int64(0)).Return(errors.New("vendor not found"))
 			},
 			check: func(err error) {
 				assert.Error(t, err)
@@ -241,7 +245,7 @@ func TestDeleteVendor(t *testing.T) {
 		{
 			name: "DeleteOne error",
 			setup: func() {
-				mockVendorRepo.EXPECT().DeleteVendor(id).Return(errors.New("delete error"))
+				mockVendorRepo.EXPECT().DeleteVendor(ctx, id, int64(0)).Return(errors.New("delete error"))
 			},
 			check: func(err error) {
 				assert.Error(t, err)
@@ -253,7 +257,7 @@ func TestDeleteVendor(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := mockVendorRepo.DeleteVendor(id)
+			err := mockVendorRepo.DeleteVendor(ctx, id, int64(0))
 			tt.check(err)
 		})
 	}
@@ -264,37 +268,38 @@ func TestSearchVendors(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
-	query := "test"
-	page := 1
-	pageSize := 10
+	query := domain.SearchQuery{Text: "test", Mode: domain.SearchModeText}
+	opts := domain.ListOptions{Page: 1, PageSize: 10}
 
-	vendor := &domain.GetVendorResponse{
-		ID: primitive.NewObjectID(),
+	vendor := &domain.SearchVendorResponse{
+		GetVendorResponse: domain.GetVendorResponse{ID: primitive.NewObjectID()},
+		Score:             1.5,
 	}
 
 	tests := []struct {
 		name  string
 		setup func()
-		check func([]*domain.GetVendorResponse, error)
+		check func(*domain.SearchVendorList, error)
 	}{
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().SearchVendors(query, page, pageSize).Return([]*domain.GetVendorResponse{vendor}, nil)
+				mockVendorRepo.EXPECT().SearchVendors(ctx, query, opts).Return(&domain.SearchVendorList{Vendors: []*domain.SearchVendorResponse{vendor}}, nil)
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.SearchVendorList, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, response)
-				assert.Equal(t, vendor, response[0])
+				assert.Equal(t, vendor, response.Vendors[0])
 			},
 		},
 		{
 			name: "Find error",
 			setup: func() {
-				mockVendorRepo.EXPECT().SearchVendors(query, page, pageSize).Return(nil, errors.New("find error"))
+				mockVendorRepo.EXPECT().SearchVendors(ctx, query, opts).Return(nil, errors.New("find error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.SearchVendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "find error", err.Error())
@@ -303,9 +308,9 @@ func TestSearchVendors(t *testing.T) {
 		{
 			name: "Decode error",
 			setup: func() {
-				mockVendorRepo.EXPECT().SearchVendors(query, page, pageSize).Return(nil, errors.New("decode error"))
+				mockVendorRepo.EXPECT().SearchVendors(ctx, query, opts).Return(nil, errors.New("decode error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.SearchVendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "decode error", err.Error())
@@ -316,7 +321,7 @@ func TestSearchVendors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			response, err := mockVendorRepo.SearchVendors(query, page, pageSize)
+			response, err := mockVendorRepo.SearchVendors(ctx, query, opts)
 			tt.check(response, err)
 		})
 	}
@@ -327,10 +332,10 @@ func TestFilterVendorsByTags(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockVendorRepo := mock_repository.NewMockVendorRepository(ctrl)
+	ctx := context.Background()
 
-	tags := []string{"tag1", "tag2"}
-	page := 1
-	pageSize := 10
+	tags := domain.ValueMatch{Values: []string{"tag1", "tag2"}, Mode: domain.MatchAny, Exclude: []string{"tag3"}}
+	opts := domain.ListOptions{Page: 1, PageSize: 10}
 
 	vendor := &domain.GetVendorResponse{
 		ID: primitive.NewObjectID(),
@@ -339,25 +344,25 @@ func TestFilterVendorsByTags(t *testing.T) {
 	tests := []struct {
 		name  string
 		setup func()
-		check func([]*domain.GetVendorResponse, error)
+		check func(*domain.VendorList, error)
 	}{
 		{
 			name: "Success",
 			setup: func() {
-				mockVendorRepo.EXPECT().FilterVendorsByTags(tags, page, pageSize).Return([]*domain.GetVendorResponse{vendor}, nil)
+				mockVendorRepo.EXPECT().FilterVendorsByTags(ctx, tags, opts).Return(&domain.VendorList{Vendors: []*domain.GetVendorResponse{vendor}}, nil)
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, response)
-				assert.Equal(t, vendor, response[0])
+				assert.Equal(t, vendor, response.Vendors[0])
 			},
 		},
 		{
 			name: "Find error",
 			setup: func() {
-				mockVendorRepo.EXPECT().FilterVendorsByTags(tags, page, pageSize).Return(nil, errors.New("find error"))
+				mockVendorRepo.EXPECT().FilterVendorsByTags(ctx, tags, opts).Return(nil, errors.New("find error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "find error", err.Error())
@@ -366,9 +371,9 @@ func TestFilterVendorsByTags(t *testing.T) {
 		{
 			name: "Decode error",
 			setup: func() {
-				mockVendorRepo.EXPECT().FilterVendorsByTags(tags, page, pageSize).Return(nil, errors.New("decode error"))
+				mockVendorRepo.EXPECT().FilterVendorsByTags(ctx, tags, opts).Return(nil, errors.New("decode error"))
 			},
-			check: func(response []*domain.GetVendorResponse, err error) {
+			check: func(response *domain.VendorList, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, response)
 				assert.Equal(t, "decode error", err.Error())
@@ -379,7 +384,7 @@ func TestFilterVendorsByTags(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			response, err := mockVendorRepo.FilterVendorsByTags(tags, page, pageSize)
+			response, err := mockVendorRepo.FilterVendorsByTags(ctx, tags, opts)
 			tt.check(response, err)
 		})
 	}
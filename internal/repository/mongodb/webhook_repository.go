@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDBWebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+	timeouts   config.Timeouts
+}
+
+func NewMongoDBWebhookRepository(webhooks, deliveries *mongo.Collection, timeouts config.Timeouts) *MongoDBWebhookRepository {
+	return &MongoDBWebhookRepository{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		timeouts:   timeouts,
+	}
+}
+
+func (r *MongoDBWebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.webhooks.InsertOne(ctx, webhook)
+	if err != nil {
+		slog.Error("error inserting webhook", utils.Err(err))
+		return err
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		webhook.ID = id
+	}
+
+	return nil
+}
+
+func (r *MongoDBWebhookRepository) GetWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	cursor, err := r.webhooks.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		slog.Error("error retrieving webhooks", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.Webhook](ctx, cursor)
+}
+
+func (r *MongoDBWebhookRepository) GetWebhook(ctx context.Context, id primitive.ObjectID) (*domain.Webhook, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var webhook domain.Webhook
+	if err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrWebhookNotFound
+		}
+		slog.Error("error getting webhook", utils.Err(err))
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *MongoDBWebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		slog.Error("error deleting webhook", utils.Err(err))
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	if _, err := r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": id}); err != nil {
+		slog.Error("error deleting webhook deliveries", utils.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoDBWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	documents := make([]interface{}, len(deliveries))
+	for i, delivery := range deliveries {
+		delivery.ID = primitive.NewObjectID()
+		documents[i] = delivery
+	}
+
+	if _, err := r.deliveries.InsertMany(ctx, documents); err != nil {
+		slog.Error("error inserting webhook deliveries", utils.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries claims deliveries one at a time. Each claim is a single
+// atomic update, so concurrent dispatchers never claim the same delivery.
+func (r *MongoDBWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	filter := bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var claimed []*domain.WebhookDelivery
+	for len(claimed) < limit {
+		var delivery domain.WebhookDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			slog.Error("error claiming webhook delivery", utils.Err(err))
+			return claimed, err
+		}
+		claimed = append(claimed, &delivery)
+	}
+
+	return claimed, nil
+}
+
+func (r *MongoDBWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+	}}
+
+	result, err := r.deliveries.UpdateByID(ctx, delivery.ID, update)
+	if err != nil {
+		slog.Error("error updating webhook delivery", utils.Err(err))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoDBWebhookRepository) GetDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var delivery domain.WebhookDelivery
+	if err := r.deliveries.FindOne(ctx, bson.M{"_id": id, "webhook_id": webhookID}).Decode(&delivery); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		slog.Error("error getting webhook delivery", utils.Err(err))
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *MongoDBWebhookRepository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	filter := bson.M{"webhook_id": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("error retrieving webhook deliveries", utils.Err(err))
+		return nil, err
+	}
+
+	return decodeAll[domain.WebhookDelivery](ctx, cursor)
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r *PartitionedVendorRepository) GetVendorsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, error) {
+	var vendors []*domain.GetVendorResponse
+	for _, partition := range r.all() {
+		found, err := partition.GetVendorsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, found...)
+	}
+	return vendors, nil
+}
+
+func (r *PartitionedVendorRepository) GetVendorsByNames(ctx context.Context, names []string) ([]*domain.GetVendorResponse, error) {
+	var vendors []*domain.GetVendorResponse
+	for _, partition := range r.all() {
+		found, err := partition.GetVendorsByNames(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, found...)
+	}
+	return vendors, nil
+}
+
+func (r *PartitionedVendorRepository) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) ([]domain.BulkItemResult, error) {
+	return bulk(len(vendors), ordered, func(i int) (repository.VendorRepository, error) {
+		partition, ok := r.partitions[vendors[i].Type]
+		if !ok {
+			return nil, domain.ErrUnknownVendorType
+		}
+		return partition, nil
+	}, func(partition repository.VendorRepository, indexes []int) ([]domain.BulkItemResult, error) {
+		batch := make([]*domain.CreateVendorRequest, len(indexes))
+		for j, i := range indexes {
+			batch[j] = vendors[i]
+		}
+		return partition.BulkCreateVendors(ctx, batch, ordered)
+	})
+}
+
+func (r *PartitionedVendorRepository) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) ([]domain.BulkItemResult, error) {
+	ids := make([]primitive.ObjectID, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+	}
+
+	owners, err := r.owners(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := bulk(len(updates), ordered, func(i int) (repository.VendorRepository, error) {
+		owner, ok := owners[updates[i].ID]
+		if !ok {
+			return nil, domain.ErrVendorNotFound
+		}
+		if owner.vendor.Type != updates[i].Vendor.Type {
+			return nil, domain.ErrVendorTypeChanged
+		}
+		return owner.partition, nil
+	}, func(partition repository.VendorRepository, indexes []int) ([]domain.BulkItemResult, error) {
+		batch := make([]domain.BulkUpdate, len(indexes))
+		for j, i := range indexes {
+			batch[j] = updates[i]
+		}
+		return partition.BulkUpdateVendors(ctx, batch, ordered)
+	})
+	return withIDs(results, ids), err
+}
+
+func (r *PartitionedVendorRepository) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) ([]domain.BulkItemResult, error) {
+	ids := make([]primitive.ObjectID, len(deletes))
+	for i, del := range deletes {
+		ids[i] = del.ID
+	}
+
+	owners, err := r.owners(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := bulk(len(deletes), ordered, func(i int) (repository.VendorRepository, error) {
+		owner, ok := owners[deletes[i].ID]
+		if !ok {
+			return nil, domain.ErrVendorNotFound
+		}
+		return owner.partition, nil
+	}, func(partition repository.VendorRepository, indexes []int) ([]domain.BulkItemResult, error) {
+		batch := make([]domain.BulkDelete, len(indexes))
+		for j, i := range indexes {
+			batch[j] = deletes[i]
+		}
+		return partition.BulkDeleteVendors(ctx, batch, ordered)
+	})
+	return withIDs(results, ids), err
+}
+
+type ownedVendor struct {
+	partition repository.VendorRepository
+	vendor    *domain.GetVendorResponse
+}
+
+// owners finds the partition of every live vendor among ids.
+func (r *PartitionedVendorRepository) owners(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]ownedVendor, error) {
+	owners := make(map[primitive.ObjectID]ownedVendor, len(ids))
+	for _, partition := range r.all() {
+		vendors, err := partition.GetVendorsByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, vendor := range vendors {
+			owners[vendor.ID] = ownedVendor{partition: partition, vendor: vendor}
+		}
+	}
+	return owners, nil
+}
+
+// bulk splits a bulk write by partition. route names the partition of an item
+// or fails it, and write runs the items at the given request indexes against
+// one partition. An ordered write sends runs of consecutive items of the same
+// partition one after another and stops at the first failure; an unordered
+// write sends one batch per partition. Items sent keep the outcome their
+// partition reported.
+func bulk(count int, ordered bool, route func(i int) (repository.VendorRepository, error), write func(partition repository.VendorRepository, indexes []int) ([]domain.BulkItemResult, error)) ([]domain.BulkItemResult, error) {
+	results := make([]domain.BulkItemResult, count)
+	for i := range results {
+		results[i].Index = i
+	}
+
+	merge := func(partition repository.VendorRepository, indexes []int) (bool, error) {
+		written, err := write(partition, indexes)
+		if err != nil {
+			return false, err
+		}
+		ok := true
+		for j, result := range written {
+			result.Index = indexes[j]
+			results[indexes[j]] = result
+			ok = ok && result.Status == domain.BulkItemOK
+		}
+		return ok, nil
+	}
+
+	if ordered {
+		for start := 0; start < count; {
+			partition, err := route(start)
+			if err != nil {
+				results[start].Fail(err)
+				break
+			}
+
+			indexes := []int{start}
+			for next := start + 1; next < count; next++ {
+				if p, err := route(next); err != nil || p != partition {
+					break
+				}
+				indexes = append(indexes, next)
+			}
+
+			ok, err := merge(partition, indexes)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			start += len(indexes)
+		}
+
+		domain.SkipUnsent(results)
+		return results, nil
+	}
+
+	var partitions []repository.VendorRepository
+	groups := make(map[repository.VendorRepository][]int)
+	for i := 0; i < count; i++ {
+		partition, err := route(i)
+		if err != nil {
+			results[i].Fail(err)
+			continue
+		}
+		if _, ok := groups[partition]; !ok {
+			partitions = append(partitions, partition)
+		}
+		groups[partition] = append(groups[partition], i)
+	}
+
+	for _, partition := range partitions {
+		if _, err := merge(partition, groups[partition]); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// withIDs fills in the vendor ID of items that never reached a partition.
+func withIDs(results []domain.BulkItemResult, ids []primitive.ObjectID) []domain.BulkItemResult {
+	for i := range results {
+		results[i].ID = ids[i]
+	}
+	return results
+}
@@ -0,0 +1,604 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PartitionedVendorRepository stores each vendor type in its own repository,
+// usually one collection per type. Writes go to the partition of the vendor's
+// type, lookups by ID probe every partition, and lists either read a single
+// partition or merge the pages of all of them.
+type PartitionedVendorRepository struct {
+	partitions map[string]repository.VendorRepository
+	types      []string
+}
+
+func NewPartitionedVendorRepository(partitions map[string]repository.VendorRepository) *PartitionedVendorRepository {
+	types := make([]string, 0, len(partitions))
+	for vendorType := range partitions {
+		types = append(types, vendorType)
+	}
+	sort.Strings(types)
+
+	return &PartitionedVendorRepository{
+		partitions: partitions,
+		types:      types,
+	}
+}
+
+func (r *PartitionedVendorRepository) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.listVendors(opts, func(partition repository.VendorRepository, opts domain.ListOptions) (*domain.VendorList, error) {
+		return partition.GetAllVendors(ctx, opts)
+	})
+}
+
+func (r *PartitionedVendorRepository) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
+	partitions, err := r.selected(vendorType)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum(partitions, func(partition repository.VendorRepository) (int, error) {
+		return partition.GetTotalVendorsCount(ctx, vendorType)
+	})
+}
+
+func (r *PartitionedVendorRepository) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	_, vendor, err := r.owner(ctx, id)
+	return vendor, err
+}
+
+func (r *PartitionedVendorRepository) GetVendorByAlias(ctx context.Context, alias primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	for _, partition := range r.all() {
+		vendor, err := partition.GetVendorByAlias(ctx, alias)
+		if err != nil || vendor != nil {
+			return vendor, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *PartitionedVendorRepository) CreateVendor(ctx context.Context, vendor *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+	partition, ok := r.partitions[vendor.Type]
+	if !ok {
+		return nil, domain.ErrUnknownVendorType
+	}
+	return partition.CreateVendor(ctx, vendor)
+}
+
+// UpdateVendor writes to the partition that holds the vendor. Moving a vendor
+// to another type would have to move it between collections, so it is
+// rejected instead.
+func (r *PartitionedVendorRepository) UpdateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	partition, vendor, err := r.owner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if vendor == nil {
+		return nil, domain.ErrVendorNotFound
+	}
+	if update.Type != vendor.Type {
+		return nil, domain.ErrVendorTypeChanged
+	}
+	return partition.UpdateVendor(ctx, id, update, expectedVersion)
+}
+
+func (r *PartitionedVendorRepository) PatchVendor(ctx context.Context, id primitive.ObjectID, patch *domain.VendorPatch, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	partition, vendor, err := r.owner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if vendor == nil {
+		return nil, domain.ErrVendorNotFound
+	}
+	if vendorType, ok := patch.Set["type"]; ok && vendorType != vendor.Type {
+		return nil, domain.ErrVendorTypeChanged
+	}
+	return partition.PatchVendor(ctx, id, patch, expectedVersion)
+}
+
+// MergeVendor writes to the partition that holds the vendor. Duplicates are
+// only ever of the vendor's own type, so they come from the same partition.
+func (r *PartitionedVendorRepository) MergeVendor(ctx context.Context, id primitive.ObjectID, merged *domain.UpdateVendorRequest, aliases []primitive.ObjectID, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	partition, vendor, err := r.owner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if vendor == nil {
+		return nil, domain.ErrVendorNotFound
+	}
+	if merged.Type != vendor.Type {
+		return nil, domain.ErrVendorTypeChanged
+	}
+	return partition.MergeVendor(ctx, id, merged, aliases, expectedVersion)
+}
+
+// FindDuplicateCandidates gathers the candidates of each partition. Only
+// vendors of the same type are compared, so no candidate spans partitions.
+func (r *PartitionedVendorRepository) FindDuplicateCandidates(ctx context.Context, vendorType string) ([]*domain.GetVendorResponse, error) {
+	partitions, err := r.selected(vendorType)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*domain.GetVendorResponse
+	for _, partition := range partitions {
+		found, err := partition.FindDuplicateCandidates(ctx, vendorType)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+	return candidates, nil
+}
+
+func (r *PartitionedVendorRepository) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	return r.each(func(partition repository.VendorRepository) error {
+		return partition.DeleteVendor(ctx, id, expectedVersion)
+	})
+}
+
+func (r *PartitionedVendorRepository) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.listVendors(opts, func(partition repository.VendorRepository, opts domain.ListOptions) (*domain.VendorList, error) {
+		return partition.GetDeletedVendors(ctx, opts)
+	})
+}
+
+func (r *PartitionedVendorRepository) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	return sum(r.all(), func(partition repository.VendorRepository) (int, error) {
+		return partition.GetDeletedVendorsCount(ctx)
+	})
+}
+
+func (r *PartitionedVendorRepository) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	return r.each(func(partition repository.VendorRepository) error {
+		return partition.RestoreVendor(ctx, id)
+	})
+}
+
+func (r *PartitionedVendorRepository) PurgeDeletedVendors(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return sum(r.all(), func(partition repository.VendorRepository) (int, error) {
+		return partition.PurgeDeletedVendors(ctx, deletedBefore)
+	})
+}
+
+// SearchVendors merges the results of every partition. Text scores are
+// computed per collection, so they are comparable but not identical to the
+// scores a single collection holding every vendor would give.
+func (r *PartitionedVendorRepository) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, error) {
+	partitions, err := r.selected(opts.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	byScore := query.Mode == domain.SearchModeText && len(opts.Sort) == 0
+
+	var facets []domain.Facets
+	page, more, err := mergePage(partitions, opts, func(partition repository.VendorRepository, opts domain.ListOptions) ([]*domain.SearchVendorResponse, string, error) {
+		list, err := partition.SearchVendors(ctx, query, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		facets = append(facets, list.Facets)
+		return list.Vendors, list.NextCursor, nil
+	}, func(a, b *domain.SearchVendorResponse) bool {
+		if byScore && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if byScore {
+			return lessID(a.ID, b.ID)
+		}
+		return opts.Sort.Compare(&a.GetVendorResponse, &b.GetVendorResponse) < 0
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := &domain.SearchVendorList{Vendors: page, Facets: domain.MergeFacets(opts.FacetLimit, facets...)}
+	if more {
+		last := page[len(page)-1]
+		cursor := domain.Cursor{Values: opts.Sort.Values(&last.GetVendorResponse), ID: last.ID}
+		if byScore {
+			cursor.Values = []interface{}{last.Score}
+		}
+		results.NextCursor = domain.EncodeCursor(cursor)
+	}
+	return results, nil
+}
+
+func (r *PartitionedVendorRepository) FindVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, error) {
+	page, more, err := mergePage(r.all(), opts, func(partition repository.VendorRepository, opts domain.ListOptions) ([]*domain.NearbyVendorResponse, string, error) {
+		list, err := partition.FindVendorsNear(ctx, point, radiusMeters, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Vendors, list.NextCursor, nil
+	}, func(a, b *domain.NearbyVendorResponse) bool {
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return lessID(a.ID, b.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := &domain.NearbyVendorList{Vendors: page}
+	if more {
+		last := page[len(page)-1]
+		results.NextCursor = domain.EncodeCursor(domain.Cursor{Values: []interface{}{last.Distance}, ID: last.ID})
+	}
+	return results, nil
+}
+
+func (r *PartitionedVendorRepository) CountSearchVendors(ctx context.Context, query domain.SearchQuery, vendorType string) (int, error) {
+	partitions, err := r.selected(vendorType)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum(partitions, func(partition repository.VendorRepository) (int, error) {
+		return partition.CountSearchVendors(ctx, query, vendorType)
+	})
+}
+
+func (r *PartitionedVendorRepository) CountVendorsNear(ctx context.Context, point *domain.GeoPoint, radiusMeters float64) (int, error) {
+	return sum(r.all(), func(partition repository.VendorRepository) (int, error) {
+		return partition.CountVendorsNear(ctx, point, radiusMeters)
+	})
+}
+
+func (r *PartitionedVendorRepository) FindVendorsInArea(ctx context.Context, area *domain.GeoPolygon, limit int) ([]*domain.MapVendor, error) {
+	var vendors []*domain.MapVendor
+	for _, partition := range r.all() {
+		found, err := partition.FindVendorsInArea(ctx, area, limit)
+		if err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, found...)
+	}
+
+	sort.SliceStable(vendors, func(i, j int) bool {
+		return lessID(vendors[i].ID, vendors[j].ID)
+	})
+	if len(vendors) > limit {
+		vendors = vendors[:limit]
+	}
+	return vendors, nil
+}
+
+// ClusterVendorsInArea combines the clusters each partition found in the same
+// grid cell. The cell is recovered from the cluster centroid, which always
+// lies inside it.
+func (r *PartitionedVendorRepository) ClusterVendorsInArea(ctx context.Context, area *domain.GeoPolygon, cellSize float64) ([]*domain.MapCluster, error) {
+	type cell struct{ x, y float64 }
+	type sums struct {
+		cluster  *domain.MapCluster
+		lng, lat float64
+	}
+
+	var cells []cell
+	byCell := make(map[cell]*sums)
+	for _, partition := range r.all() {
+		clusters, err := partition.ClusterVendorsInArea(ctx, area, cellSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clusters {
+			key := cell{math.Floor(cluster.Coordinates.Lng() / cellSize), math.Floor(cluster.Coordinates.Lat() / cellSize)}
+			group, ok := byCell[key]
+			if !ok {
+				group = &sums{cluster: &domain.MapCluster{Vendor: cluster.Vendor}}
+				byCell[key] = group
+				cells = append(cells, key)
+			}
+			count := float64(cluster.Count)
+			group.cluster.Count += cluster.Count
+			group.lng += cluster.Coordinates.Lng() * count
+			group.lat += cluster.Coordinates.Lat() * count
+		}
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].y != cells[j].y {
+			return cells[i].y < cells[j].y
+		}
+		return cells[i].x < cells[j].x
+	})
+
+	merged := make([]*domain.MapCluster, 0, len(cells))
+	for _, key := range cells {
+		group := byCell[key]
+		count := float64(group.cluster.Count)
+		group.cluster.Coordinates = domain.NewGeoPoint(group.lng/count, group.lat/count)
+		merged = append(merged, group.cluster)
+	}
+
+	return merged, nil
+}
+
+// GetVendorFacets counts every value in each partition and only keeps the
+// most common once the counts are added up.
+func (r *PartitionedVendorRepository) GetVendorFacets(ctx context.Context, fields []string, vendorType string, limit int) (domain.Facets, error) {
+	partitions, err := r.selected(vendorType)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]domain.Facets, len(partitions))
+	for i, partition := range partitions {
+		parts[i], err = partition.GetVendorFacets(ctx, fields, vendorType, domain.AllFacetValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return domain.MergeFacets(limit, parts...), nil
+}
+
+func (r *PartitionedVendorRepository) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, error) {
+	opts.Type = filter.Type
+	return r.listVendors(opts, func(partition repository.VendorRepository, opts domain.ListOptions) (*domain.VendorList, error) {
+		return partition.FilterVendors(ctx, filter, opts)
+	})
+}
+
+func (r *PartitionedVendorRepository) CountFilteredVendors(ctx context.Context, filter domain.VendorFilter) (int, error) {
+	partitions, err := r.selected(filter.Type)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum(partitions, func(partition repository.VendorRepository) (int, error) {
+		return partition.CountFilteredVendors(ctx, filter)
+	})
+}
+
+// ExportVendors merges the exports of the selected partitions in order.
+// Every partition exports in a goroutine of its own and hands over one vendor
+// at a time, so the merge holds no more than one vendor per partition.
+func (r *PartitionedVendorRepository) ExportVendors(ctx context.Context, filter domain.VendorFilter, order domain.Sort, emit func(*domain.GetVendorResponse) error) error {
+	partitions, err := r.selected(filter.Type)
+	if err != nil {
+		return err
+	}
+	if len(partitions) == 1 {
+		return partitions[0].ExportVendors(ctx, filter, order, emit)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	streams := make([]chan *domain.GetVendorResponse, len(partitions))
+	results := make([]chan error, len(partitions))
+	for i, partition := range partitions {
+		stream := make(chan *domain.GetVendorResponse)
+		result := make(chan error, 1)
+		streams[i], results[i] = stream, result
+
+		go func(partition repository.VendorRepository) {
+			defer close(stream)
+			result <- partition.ExportVendors(ctx, filter, order, func(vendor *domain.GetVendorResponse) error {
+				select {
+				case stream <- vendor:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}(partition)
+	}
+
+	// next reads the following vendor of partition i. Once the partition is
+	// done it returns nil, or the error its export ended with.
+	next := func(i int) (*domain.GetVendorResponse, error) {
+		if vendor, ok := <-streams[i]; ok {
+			return vendor, nil
+		}
+		return nil, <-results[i]
+	}
+
+	heads := make([]*domain.GetVendorResponse, len(partitions))
+	for i := range heads {
+		if heads[i], err = next(i); err != nil {
+			return err
+		}
+	}
+
+	for {
+		first := -1
+		for i, head := range heads {
+			if head != nil && (first < 0 || order.Compare(head, heads[first]) < 0) {
+				first = i
+			}
+		}
+		if first < 0 {
+			return nil
+		}
+
+		if err := emit(heads[first]); err != nil {
+			return err
+		}
+		if heads[first], err = next(first); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *PartitionedVendorRepository) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, error) {
+	return r.listVendors(opts, func(partition repository.VendorRepository, opts domain.ListOptions) (*domain.VendorList, error) {
+		return partition.FilterVendorsByTags(ctx, tags, opts)
+	})
+}
+
+func (r *PartitionedVendorRepository) CountVendorsByTags(ctx context.Context, tags domain.ValueMatch, vendorType string) (int, error) {
+	partitions, err := r.selected(vendorType)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum(partitions, func(partition repository.VendorRepository) (int, error) {
+		return partition.CountVendorsByTags(ctx, tags, vendorType)
+	})
+}
+
+// listVendors merges a list query ordered by _id across the partitions that
+// opts selects.
+func (r *PartitionedVendorRepository) listVendors(opts domain.ListOptions, fetch func(repository.VendorRepository, domain.ListOptions) (*domain.VendorList, error)) (*domain.VendorList, error) {
+	partitions, err := r.selected(opts.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var facets []domain.Facets
+	page, more, err := mergePage(partitions, opts, func(partition repository.VendorRepository, opts domain.ListOptions) ([]*domain.GetVendorResponse, string, error) {
+		list, err := fetch(partition, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		facets = append(facets, list.Facets)
+		return list.Vendors, list.NextCursor, nil
+	}, func(a, b *domain.GetVendorResponse) bool {
+		return opts.Sort.Compare(a, b) < 0
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := &domain.VendorList{Vendors: page, Facets: domain.MergeFacets(opts.FacetLimit, facets...)}
+	if more {
+		last := page[len(page)-1]
+		list.NextCursor = domain.EncodeCursor(domain.Cursor{Values: opts.Sort.Values(last), ID: last.ID})
+	}
+	return list, nil
+}
+
+// owner finds the partition holding the live vendor with the given ID. It
+// returns a nil vendor when no partition has it.
+func (r *PartitionedVendorRepository) owner(ctx context.Context, id primitive.ObjectID) (repository.VendorRepository, *domain.GetVendorResponse, error) {
+	for _, partition := range r.all() {
+		vendor, err := partition.GetVendorByID(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if vendor != nil {
+			return partition, vendor, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// each runs op against the partitions in turn until one of them knows the
+// vendor, that is until op returns anything but ErrVendorNotFound.
+func (r *PartitionedVendorRepository) each(op func(repository.VendorRepository) error) error {
+	for _, partition := range r.all() {
+		if err := op(partition); !errors.Is(err, domain.ErrVendorNotFound) {
+			return err
+		}
+	}
+	return domain.ErrVendorNotFound
+}
+
+func (r *PartitionedVendorRepository) all() []repository.VendorRepository {
+	partitions := make([]repository.VendorRepository, 0, len(r.types))
+	for _, vendorType := range r.types {
+		partitions = append(partitions, r.partitions[vendorType])
+	}
+	return partitions
+}
+
+// selected returns the single partition for vendorType, or all of them when
+// no type is given.
+func (r *PartitionedVendorRepository) selected(vendorType string) ([]repository.VendorRepository, error) {
+	if vendorType == "" {
+		return r.all(), nil
+	}
+	partition, ok := r.partitions[vendorType]
+	if !ok {
+		return nil, domain.ErrUnknownVendorType
+	}
+	return []repository.VendorRepository{partition}, nil
+}
+
+// mergePage reads the page opts asks for from every partition and merges them
+// in the order less defines, which must be the order the partitions sort by.
+// In page mode each partition may contribute every item up to the end of the
+// requested page, so each is asked for that many items from the start, and
+// pages reaching past MaxPageDepth are refused with ErrPageTooDeep. In
+// cursor mode the cursor is valid for every partition as it only holds sort
+// keys. Facets are counted in full by each partition so that they can be
+// merged exactly. A single partition, as a type selects, is asked for the
+// page itself, with no depth limit. The returned flag tells whether more
+// items follow the page.
+func mergePage[T any](partitions []repository.VendorRepository, opts domain.ListOptions, fetch func(repository.VendorRepository, domain.ListOptions) ([]*T, string, error), less func(a, b *T) bool) ([]*T, bool, error) {
+	if len(partitions) == 1 {
+		items, nextCursor, err := fetch(partitions[0], opts)
+		if err != nil {
+			return nil, false, err
+		}
+		return items, nextCursor != "", nil
+	}
+
+	partitionOpts := opts
+	partitionOpts.FacetLimit = domain.AllFacetValues
+	if opts.Cursor == "" {
+		if opts.Page*opts.PageSize > domain.MaxPageDepth {
+			return nil, false, domain.ErrPageTooDeep
+		}
+		partitionOpts.Page = 1
+		partitionOpts.PageSize = opts.Page * opts.PageSize
+	}
+
+	var merged []*T
+	more := false
+	for _, partition := range partitions {
+		items, nextCursor, err := fetch(partition, partitionOpts)
+		if err != nil {
+			return nil, false, err
+		}
+		merged = append(merged, items...)
+		more = more || nextCursor != ""
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return less(merged[i], merged[j])
+	})
+
+	if opts.Cursor == "" {
+		skip := (opts.Page - 1) * opts.PageSize
+		if skip > len(merged) {
+			skip = len(merged)
+		}
+		merged = merged[skip:]
+	}
+
+	if len(merged) > opts.PageSize {
+		return merged[:opts.PageSize], true, nil
+	}
+	return merged, more && len(merged) > 0, nil
+}
+
+func sum(partitions []repository.VendorRepository, count func(repository.VendorRepository) (int, error)) (int, error) {
+	total := 0
+	for _, partition := range partitions {
+		n, err := count(partition)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func lessID(a, b primitive.ObjectID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
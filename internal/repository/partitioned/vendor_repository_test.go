@@ -0,0 +1,346 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+	memoryRepository "vendors/internal/repository/memory"
+	partitionedRepository "vendors/internal/repository/partitioned"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newPartitionedRepository() *partitionedRepository.PartitionedVendorRepository {
+	return partitionedRepository.NewPartitionedVendorRepository(map[string]repository.VendorRepository{
+		domain.VendorTypeCinema:  memoryRepository.NewMemoryVendorRepository(),
+		domain.VendorTypeTheatre: memoryRepository.NewMemoryVendorRepository(),
+		domain.VendorTypeFood:    memoryRepository.NewMemoryVendorRepository(),
+	})
+}
+
+func TestPartitionedVendorRouting(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	_, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: "Circus", Type: "circus"})
+	assert.ErrorIs(t, err, domain.ErrUnknownVendorType)
+
+	created, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: "Odeon", Type: domain.VendorTypeCinema})
+	require.NoError(t, err)
+
+	found, err := repo.GetVendorByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "Odeon", found.Name)
+
+	_, err = repo.UpdateVendor(ctx, created.ID, &domain.UpdateVendorRequest{Name: "Odeon", Type: domain.VendorTypeFood}, 0)
+	assert.ErrorIs(t, err, domain.ErrVendorTypeChanged)
+
+	updated, err := repo.UpdateVendor(ctx, created.ID, &domain.UpdateVendorRequest{Name: "Odeon Luxe", Type: domain.VendorTypeCinema}, created.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "Odeon Luxe", updated.Name)
+
+	require.NoError(t, repo.DeleteVendor(ctx, created.ID, 0))
+	assert.ErrorIs(t, repo.DeleteVendor(ctx, created.ID, 0), domain.ErrVendorNotFound)
+
+	deleted, err := repo.GetDeletedVendorsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	require.NoError(t, repo.RestoreVendor(ctx, created.ID))
+	assert.ErrorIs(t, repo.RestoreVendor(ctx, created.ID), domain.ErrVendorNotFound)
+}
+
+func TestPartitionedVendorPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	types := []string{domain.VendorTypeFood, domain.VendorTypeCinema, domain.VendorTypeTheatre}
+	var names []string
+	for i := 0; i < 7; i++ {
+		name := fmt.Sprintf("vendor-%d", i)
+		_, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: name, Type: types[i%len(types)]})
+		require.NoError(t, err)
+		names = append(names, name)
+	}
+
+	namesOf := func(vendors []*domain.GetVendorResponse) []string {
+		var result []string
+		for _, vendor := range vendors {
+			result = append(result, vendor.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		opts domain.ListOptions
+		want []string
+	}{
+		{name: "First page", opts: domain.ListOptions{Page: 1, PageSize: 3}, want: names[0:3]},
+		{name: "Middle page", opts: domain.ListOptions{Page: 2, PageSize: 3}, want: names[3:6]},
+		{name: "Last page", opts: domain.ListOptions{Page: 3, PageSize: 3}, want: names[6:7]},
+		{name: "Single type", opts: domain.ListOptions{Page: 1, PageSize: 10, Type: domain.VendorTypeFood}, want: []string{"vendor-0", "vendor-3", "vendor-6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.GetAllVendors(ctx, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, namesOf(list.Vendors))
+		})
+	}
+
+	var walked []string
+	opts := domain.ListOptions{Page: 1, PageSize: 2}
+	for {
+		list, err := repo.GetAllVendors(ctx, opts)
+		require.NoError(t, err)
+		walked = append(walked, namesOf(list.Vendors)...)
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+	assert.Equal(t, names, walked)
+
+	byName, err := domain.ParseSort("-name")
+	require.NoError(t, err)
+
+	walked = nil
+	opts = domain.ListOptions{Page: 1, PageSize: 3, Sort: byName}
+	for {
+		list, err := repo.GetAllVendors(ctx, opts)
+		require.NoError(t, err)
+		walked = append(walked, namesOf(list.Vendors)...)
+		if list.NextCursor == "" {
+			break
+		}
+		opts.Cursor = list.NextCursor
+	}
+	assert.Equal(t, []string{"vendor-6", "vendor-5", "vendor-4", "vendor-3", "vendor-2", "vendor-1", "vendor-0"}, walked)
+
+	deepest := domain.ListOptions{Page: domain.MaxPageDepth / 10, PageSize: 10}
+	_, err = repo.GetAllVendors(ctx, deepest)
+	require.NoError(t, err)
+
+	deepest.Page++
+	_, err = repo.GetAllVendors(ctx, deepest)
+	assert.ErrorIs(t, err, domain.ErrPageTooDeep)
+
+	typed := deepest
+	typed.Type = domain.VendorTypeFood
+	_, err = repo.GetAllVendors(ctx, typed)
+	assert.NoError(t, err, "a single partition pages by itself")
+
+	deepest.Cursor = domain.EncodeCursor(domain.Cursor{ID: primitive.NewObjectID()})
+	_, err = repo.GetAllVendors(ctx, deepest)
+	assert.NoError(t, err, "cursors reach any depth")
+
+	total, err := repo.GetTotalVendorsCount(ctx, domain.VendorTypeCinema)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	_, err = repo.GetTotalVendorsCount(ctx, "circus")
+	assert.ErrorIs(t, err, domain.ErrUnknownVendorType)
+}
+
+func TestPartitionedVendorSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	for _, vendor := range []*domain.CreateVendorRequest{
+		{Name: "Pizza Cinema", Type: domain.VendorTypeCinema},
+		{Name: "Pizza Pizza", Type: domain.VendorTypeFood, Tags: []string{"pizza"}},
+		{Name: "Opera", Type: domain.VendorTypeTheatre},
+	} {
+		_, err := repo.CreateVendor(ctx, vendor)
+		require.NoError(t, err)
+	}
+
+	list, err := repo.SearchVendors(ctx, domain.SearchQuery{Text: "pizza", Mode: domain.SearchModeText}, domain.ListOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Vendors, 2)
+	assert.Equal(t, "Pizza Pizza", list.Vendors[0].Name)
+
+	list, err = repo.SearchVendors(ctx, domain.SearchQuery{Text: "pizza", Mode: domain.SearchModePrefix}, domain.ListOptions{Page: 1, PageSize: 10, Type: domain.VendorTypeCinema})
+	require.NoError(t, err)
+	require.Len(t, list.Vendors, 1)
+	assert.Equal(t, "Pizza Cinema", list.Vendors[0].Name)
+}
+
+func TestPartitionedVendorFacets(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	// "shared" is second in every partition but first once they are added up.
+	for _, vendor := range []domain.CreateVendorRequest{
+		{Name: "Pizza Place", Type: domain.VendorTypeFood, Tags: []string{"pizza", "shared"}},
+		{Name: "Pizza Bar", Type: domain.VendorTypeFood, Tags: []string{"pizza"}},
+		{Name: "Odeon", Type: domain.VendorTypeCinema, Tags: []string{"imax", "shared"}},
+		{Name: "Odeon Luxe", Type: domain.VendorTypeCinema, Tags: []string{"imax"}},
+		{Name: "Globe", Type: domain.VendorTypeTheatre, Tags: []string{"shared"}},
+	} {
+		_, err := repo.CreateVendor(ctx, &vendor)
+		require.NoError(t, err)
+	}
+
+	want := []domain.FacetCount{{Value: "shared", Count: 3}}
+
+	facets, err := repo.GetVendorFacets(ctx, []string{domain.FacetTags}, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, want, facets[domain.FacetTags])
+
+	list, err := repo.GetAllVendors(ctx, domain.ListOptions{Page: 1, PageSize: 2, Facets: []string{domain.FacetTags}, FacetLimit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, want, list.Facets[domain.FacetTags])
+
+	facets, err = repo.GetVendorFacets(ctx, []string{domain.FacetTags}, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FacetCount{{Value: "shared", Count: 3}, {Value: "imax", Count: 2}, {Value: "pizza", Count: 2}}, facets[domain.FacetTags])
+}
+
+func TestPartitionedBulkWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	statuses := func(results []domain.BulkItemResult) []domain.BulkItemStatus {
+		var statuses []domain.BulkItemStatus
+		for _, result := range results {
+			statuses = append(statuses, result.Status)
+		}
+		return statuses
+	}
+
+	vendors := []*domain.CreateVendorRequest{
+		{Name: "Odeon", Type: domain.VendorTypeCinema},
+		{Name: "Deli", Type: domain.VendorTypeFood},
+		{Name: "Circus", Type: "circus"},
+		{Name: "Globe", Type: domain.VendorTypeTheatre},
+	}
+
+	results, err := repo.BulkCreateVendors(ctx, vendors, true)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemOK, domain.BulkItemFailed, domain.BulkItemSkipped}, statuses(results))
+	assert.ErrorIs(t, results[2].Err, domain.ErrUnknownVendorType)
+
+	results, err = repo.BulkCreateVendors(ctx, vendors, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemOK, domain.BulkItemFailed, domain.BulkItemOK}, statuses(results))
+	for _, i := range []int{0, 1, 3} {
+		assert.Equal(t, i, results[i].Index)
+		assert.False(t, results[i].ID.IsZero())
+	}
+
+	odeon, globe := results[0], results[3]
+	results, err = repo.BulkUpdateVendors(ctx, []domain.BulkUpdate{
+		{ID: globe.ID, Vendor: &domain.UpdateVendorRequest{Name: "Globe", Type: domain.VendorTypeFood}, Version: 1},
+		{ID: odeon.ID, Vendor: &domain.UpdateVendorRequest{Name: "Odeon Luxe", Type: domain.VendorTypeCinema}, Version: 1},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemFailed, domain.BulkItemOK}, statuses(results))
+	assert.ErrorIs(t, results[0].Err, domain.ErrVendorTypeChanged)
+	assert.Equal(t, globe.ID, results[0].ID)
+
+	results, err = repo.BulkDeleteVendors(ctx, []domain.BulkDelete{{ID: odeon.ID, Version: 2}, {ID: globe.ID, Version: 1}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkItemStatus{domain.BulkItemOK, domain.BulkItemOK}, statuses(results))
+
+	total, err := repo.GetTotalVendorsCount(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+}
+
+func TestPartitionedExportVendors(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	types := []string{domain.VendorTypeFood, domain.VendorTypeCinema, domain.VendorTypeTheatre}
+	for i, name := range []string{"delta", "alpha", "echo", "charlie", "bravo", "foxtrot"} {
+		_, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: name, Type: types[i%len(types)]})
+		require.NoError(t, err)
+	}
+
+	export := func(filter domain.VendorFilter, sort string) ([]string, error) {
+		order, err := domain.ParseSort(sort)
+		require.NoError(t, err)
+
+		var names []string
+		err = repo.ExportVendors(ctx, filter, order, func(vendor *domain.GetVendorResponse) error {
+			names = append(names, vendor.Name)
+			return nil
+		})
+		return names, err
+	}
+
+	names, err := export(domain.VendorFilter{}, "name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}, names)
+
+	names, err = export(domain.VendorFilter{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delta", "alpha", "echo", "charlie", "bravo", "foxtrot"}, names)
+
+	names, err = export(domain.VendorFilter{Type: domain.VendorTypeFood}, "-name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delta", "charlie"}, names)
+
+	_, err = export(domain.VendorFilter{Type: "circus"}, "")
+	assert.ErrorIs(t, err, domain.ErrUnknownVendorType)
+
+	stop := errors.New("stop")
+	emitted := 0
+	err = repo.ExportVendors(ctx, domain.VendorFilter{}, nil, func(*domain.GetVendorResponse) error {
+		emitted++
+		if emitted == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 2, emitted)
+}
+
+func TestPartitionedMergeVendor(t *testing.T) {
+	ctx := context.Background()
+	repo := newPartitionedRepository()
+
+	pizza, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeFood})
+	require.NoError(t, err)
+	pizzaAgain, err := repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: "Pizzaplace", Type: domain.VendorTypeFood})
+	require.NoError(t, err)
+
+	_, err = repo.CreateVendor(ctx, &domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeCinema})
+	require.NoError(t, err)
+
+	candidates, err := repo.FindDuplicateCandidates(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, candidates, 2, "vendors of other partitions are never candidates of each other")
+	_, err = repo.FindDuplicateCandidates(ctx, "circus")
+	assert.ErrorIs(t, err, domain.ErrUnknownVendorType)
+
+	aliases := []primitive.ObjectID{pizzaAgain.ID}
+
+	_, err = repo.MergeVendor(ctx, pizza.ID, &domain.UpdateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeCinema}, aliases, 0)
+	assert.ErrorIs(t, err, domain.ErrVendorTypeChanged)
+	_, err = repo.MergeVendor(ctx, primitive.NewObjectID(), &domain.UpdateVendorRequest{Type: domain.VendorTypeFood}, aliases, 0)
+	assert.ErrorIs(t, err, domain.ErrVendorNotFound)
+
+	merged, err := repo.MergeVendor(ctx, pizza.ID, &domain.UpdateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeFood}, aliases, 0)
+	require.NoError(t, err)
+	assert.Equal(t, aliases, merged.Aliases)
+
+	resolved, err := repo.GetVendorByAlias(ctx, pizzaAgain.ID)
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, pizza.ID, resolved.ID)
+
+	missing, err := repo.GetVendorByAlias(ctx, primitive.NewObjectID())
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *VendorService) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) (*domain.BulkResult, error) {
+	results := make([]domain.BulkItemResult, len(vendors))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	var batch []*domain.CreateVendorRequest
+	var positions []int
+	for i, vendor := range vendors {
+		if err := vendor.Coordinates.Validate(); err != nil {
+			results[i].Fail(err)
+			if ordered {
+				break
+			}
+			continue
+		}
+		batch = append(batch, vendor)
+		positions = append(positions, i)
+	}
+
+	written, err := s.write(results, positions, ordered, func() ([]domain.BulkItemResult, error) {
+		return s.VendorRepository.BulkCreateVendors(ctx, batch, ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for j, result := range written {
+		if result.Status != domain.BulkItemOK {
+			continue
+		}
+		snapshot := domain.CommonVendorRequest(*batch[j])
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: result.ID,
+			Revision: result.Version,
+			Action:   domain.HistoryActionCreated,
+			Changes:  domain.DiffVendors(domain.CommonVendorRequest{}, snapshot),
+			Snapshot: snapshot,
+		})
+	}
+
+	return domain.NewBulkResult(results), nil
+}
+
+// BulkUpdateVendors replaces vendors in bulk. Like UpdateVendor, it reads the
+// vendors first so history entries hold exact diffs, and pins each write to
+// the version it read unless the item names a version itself.
+func (s *VendorService) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) (*domain.BulkResult, error) {
+	ids := make([]primitive.ObjectID, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+	}
+
+	current, err := s.currentVendors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BulkItemResult, len(updates))
+	for i, update := range updates {
+		results[i] = domain.BulkItemResult{Index: i, ID: update.ID}
+	}
+
+	var batch []domain.BulkUpdate
+	var positions []int
+	for i, update := range updates {
+		before := current[update.ID]
+		err := update.Vendor.Coordinates.Validate()
+		switch {
+		case before == nil:
+			err = domain.ErrVendorNotFound
+		case update.Version != 0 && update.Version != before.Version:
+			err = domain.ErrVersionConflict
+		}
+		if err != nil {
+			results[i].Fail(err)
+			if ordered {
+				break
+			}
+			continue
+		}
+
+		update.Version = before.Version
+		batch = append(batch, update)
+		positions = append(positions, i)
+	}
+
+	written, err := s.write(results, positions, ordered, func() ([]domain.BulkItemResult, error) {
+		return s.VendorRepository.BulkUpdateVendors(ctx, batch, ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for j, result := range written {
+		if result.Status != domain.BulkItemOK {
+			continue
+		}
+		before := domain.SnapshotOf(domain.CommonVendorResponse(*current[result.ID]))
+		snapshot := domain.CommonVendorRequest(*batch[j].Vendor)
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: result.ID,
+			Revision: result.Version,
+			Action:   domain.HistoryActionUpdated,
+			Changes:  domain.DiffVendors(before, snapshot),
+			Snapshot: snapshot,
+		})
+	}
+
+	return domain.NewBulkResult(results), nil
+}
+
+func (s *VendorService) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) (*domain.BulkResult, error) {
+	ids := make([]primitive.ObjectID, len(deletes))
+	for i, del := range deletes {
+		ids[i] = del.ID
+	}
+
+	current, err := s.currentVendors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BulkItemResult, len(deletes))
+	for i, del := range deletes {
+		results[i] = domain.BulkItemResult{Index: i, ID: del.ID}
+	}
+
+	var batch []domain.BulkDelete
+	var positions []int
+	for i, del := range deletes {
+		var err error
+		before := current[del.ID]
+		switch {
+		case before == nil:
+			err = domain.ErrVendorNotFound
+		case del.Version != 0 && del.Version != before.Version:
+			err = domain.ErrVersionConflict
+		}
+		if err != nil {
+			results[i].Fail(err)
+			if ordered {
+				break
+			}
+			continue
+		}
+
+		del.Version = before.Version
+		batch = append(batch, del)
+		positions = append(positions, i)
+	}
+
+	written, err := s.write(results, positions, ordered, func() ([]domain.BulkItemResult, error) {
+		return s.VendorRepository.BulkDeleteVendors(ctx, batch, ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range written {
+		if result.Status != domain.BulkItemOK {
+			continue
+		}
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: result.ID,
+			Revision: result.Version,
+			Action:   domain.HistoryActionDeleted,
+			Changes:  []domain.FieldChange{},
+			Snapshot: domain.SnapshotOf(domain.CommonVendorResponse(*current[result.ID])),
+		})
+	}
+
+	return domain.NewBulkResult(results), nil
+}
+
+// write sends the items that passed validation to the repository and copies
+// the outcome back to their positions in the request. Every result must
+// already carry its index, and its ID where the request names one, since
+// items after an ordered failure are never sent. In an ordered request those
+// items are reported as skipped, while the items that were sent keep the
+// outcome the repository reported.
+func (s *VendorService) write(results []domain.BulkItemResult, positions []int, ordered bool, send func() ([]domain.BulkItemResult, error)) ([]domain.BulkItemResult, error) {
+	var written []domain.BulkItemResult
+	if len(positions) > 0 {
+		var err error
+		written, err = send()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for j, result := range written {
+		result.Index = positions[j]
+		results[positions[j]] = result
+	}
+
+	if ordered {
+		domain.SkipUnsent(results)
+	}
+	return written, nil
+}
+
+func (s *VendorService) currentVendors(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]*domain.GetVendorResponse, error) {
+	vendors, err := s.VendorRepository.GetVendorsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.GetVendorResponse, len(vendors))
+	for _, vendor := range vendors {
+		byID[vendor.ID] = vendor
+	}
+	return byID, nil
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/memory"
+	"vendors/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newVendorService() *service.VendorService {
+	webhooks := service.NewWebhookService(repository.NewMemoryWebhookRepository(), config.Webhooks{})
+	return service.NewVendorService(repository.NewMemoryVendorRepository(), repository.NewMemoryHistoryRepository(), webhooks, config.Trash{}, config.Events{Buffer: 10})
+}
+
+type bulkItem struct {
+	Index  int
+	ID     primitive.ObjectID
+	Status domain.BulkItemStatus
+}
+
+func bulkItems(result *domain.BulkResult) []bulkItem {
+	items := make([]bulkItem, len(result.Results))
+	for i, item := range result.Results {
+		items[i] = bulkItem{Index: item.Index, ID: item.ID, Status: item.Status}
+	}
+	return items
+}
+
+func TestOrderedBulkStopsAtValidationFailure(t *testing.T) {
+	ctx := context.Background()
+	s := newVendorService()
+
+	created, err := s.BulkCreateVendors(ctx, []*domain.CreateVendorRequest{
+		{Name: "Pizza Place", Type: domain.VendorTypeFood},
+		{Name: "Nowhere", Type: domain.VendorTypeFood, Coordinates: domain.NewGeoPoint(500, 0)},
+		{Name: "Odeon", Type: domain.VendorTypeCinema},
+		{Name: "Opera", Type: domain.VendorTypeTheatre},
+	}, true)
+	require.NoError(t, err)
+	first := created.Results[0].ID
+	assert.Equal(t, []bulkItem{
+		{Index: 0, ID: first, Status: domain.BulkItemOK},
+		{Index: 1, Status: domain.BulkItemFailed},
+		{Index: 2, Status: domain.BulkItemSkipped},
+		{Index: 3, Status: domain.BulkItemSkipped},
+	}, bulkItems(created))
+
+	missing := primitive.NewObjectID()
+	later := primitive.NewObjectID()
+
+	updated, err := s.BulkUpdateVendors(ctx, []domain.BulkUpdate{
+		{ID: first, Vendor: &domain.UpdateVendorRequest{Name: "Pizza Palace", Type: domain.VendorTypeFood}},
+		{ID: missing, Vendor: &domain.UpdateVendorRequest{Name: "Ghost", Type: domain.VendorTypeFood}},
+		{ID: later, Vendor: &domain.UpdateVendorRequest{Name: "Later", Type: domain.VendorTypeFood}},
+	}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []bulkItem{
+		{Index: 0, ID: first, Status: domain.BulkItemOK},
+		{Index: 1, ID: missing, Status: domain.BulkItemFailed},
+		{Index: 2, ID: later, Status: domain.BulkItemSkipped},
+	}, bulkItems(updated))
+
+	deleted, err := s.BulkDeleteVendors(ctx, []domain.BulkDelete{
+		{ID: missing},
+		{ID: first},
+		{ID: later},
+	}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []bulkItem{
+		{Index: 0, ID: missing, Status: domain.BulkItemFailed},
+		{Index: 1, ID: first, Status: domain.BulkItemSkipped},
+		{Index: 2, ID: later, Status: domain.BulkItemSkipped},
+	}, bulkItems(deleted))
+
+	vendor, err := s.GetVendorByID(ctx, first)
+	require.NoError(t, err)
+	require.NotNil(t, vendor, "skipped deletes must not be applied")
+	assert.Equal(t, "Pizza Palace", vendor.Name)
+}
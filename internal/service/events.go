@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"vendors/internal/domain"
+)
+
+// subscriberBuffer bounds how many events may wait for a subscriber. One that
+// falls further behind is dropped and has to resume from its last event.
+const subscriberBuffer = 64
+
+// EventBroker fans out the events the service publishes to in-process
+// subscribers. It keeps the most recent events so that a subscriber can
+// resume after the last one it received. Event ids are sequence numbers.
+type EventBroker struct {
+	mu          sync.Mutex
+	seq         uint64
+	recent      []*domain.VendorEvent
+	size        int
+	subscribers map[chan *domain.VendorEvent]struct{}
+}
+
+func NewEventBroker(size int) *EventBroker {
+	return &EventBroker{
+		size:        size,
+		subscribers: map[chan *domain.VendorEvent]struct{}{},
+	}
+}
+
+// Publish numbers event and hands it to every subscriber.
+func (b *EventBroker) Publish(event *domain.VendorEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.ID = strconv.FormatUint(b.seq, 10)
+
+	b.recent = append(b.recent, event)
+	if len(b.recent) > b.size {
+		b.recent = b.recent[len(b.recent)-b.size:]
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (b *EventBroker) Subscribe(ctx context.Context, lastEventID string) (<-chan *domain.VendorEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	replay, err := b.after(lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := make(chan *domain.VendorEvent, len(replay)+subscriberBuffer)
+	for _, event := range replay {
+		subscriber <- event
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}()
+
+	return subscriber, nil
+}
+
+// after returns the kept events that follow lastEventID. It fails when events
+// after it have already been discarded.
+func (b *EventBroker) after(lastEventID string) ([]*domain.VendorEvent, error) {
+	if lastEventID == "" {
+		return nil, nil
+	}
+
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || last > b.seq {
+		return nil, domain.ErrInvalidEventID
+	}
+
+	oldest := b.seq - uint64(len(b.recent)) + 1
+	if last+1 < oldest {
+		return nil, domain.ErrEventsExpired
+	}
+	return b.recent[last+1-oldest:], nil
+}
+
+// StreamVendorEvents delivers the events matching filter that follow
+// lastEventID until ctx is done or the source ends the subscription.
+func (s *VendorService) StreamVendorEvents(ctx context.Context, filter domain.VendorFilter, lastEventID string) (<-chan *domain.VendorEvent, error) {
+	source, err := s.EventSource.Subscribe(ctx, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *domain.VendorEvent)
+	go func() {
+		defer close(events)
+		for event := range source {
+			if !filter.MatchesEvent(event) {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// publish announces a change the service has just recorded to event streams
+// and webhooks. With an outbox relay the repository has already added the
+// event to the outbox, and the relay is only woken to publish it.
+func (s *VendorService) publish(ctx context.Context, entry *domain.VendorHistoryEntry) {
+	if s.relay != nil {
+		s.relay.notify()
+		return
+	}
+
+	event := &domain.VendorEvent{
+		Type:      domain.EventTypeOf(entry.Action),
+		VendorID:  entry.VendorID,
+		Revision:  entry.Revision,
+		Timestamp: entry.Timestamp,
+		Vendor:    entry.Snapshot,
+	}
+
+	s.broker.Publish(event)
+	s.enqueue(ctx, event)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"vendors/internal/domain"
+)
+
+// vendorEncoder writes vendors in one export format. Nothing is written
+// before the first vendor or Close, so an export that fails right away
+// leaves the output untouched.
+type vendorEncoder interface {
+	Encode(vendor *domain.GetVendorResponse) error
+	Close() error
+}
+
+// ExportVendors writes the vendors matching filter to w in order. They
+// are encoded one at a time as the repository reads them, so an export of any
+// size takes the same memory.
+func (s *VendorService) ExportVendors(ctx context.Context, w io.Writer, filter domain.VendorFilter, order domain.Sort, opts domain.ExportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if filter.Type != "" && !slices.Contains(domain.VendorTypes, filter.Type) {
+		return domain.ErrUnknownVendorType
+	}
+
+	var encoder vendorEncoder
+	switch opts.Format {
+	case domain.ExportCSV:
+		encoder = &csvEncoder{writer: csv.NewWriter(w), delimiter: opts.ListDelimiter}
+	case domain.ExportNDJSON:
+		encoder = &ndjsonEncoder{encoder: json.NewEncoder(w)}
+	default:
+		encoder = &jsonArrayEncoder{w: w}
+	}
+
+	if err := s.VendorRepository.ExportVendors(ctx, filter, order, encoder.Encode); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// csvEncoder writes the columns of a CSV import, in the same order.
+type csvEncoder struct {
+	writer    *csv.Writer
+	delimiter string
+	started   bool
+}
+
+func (e *csvEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.writer.Write(importColumns)
+}
+
+func (e *csvEncoder) Encode(vendor *domain.GetVendorResponse) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var longitude, latitude string
+	if vendor.Coordinates != nil && len(vendor.Coordinates.Coordinates) == 2 {
+		longitude = strconv.FormatFloat(vendor.Coordinates.Lng(), 'f', -1, 64)
+		latitude = strconv.FormatFloat(vendor.Coordinates.Lat(), 'f', -1, 64)
+	}
+
+	return e.writer.Write([]string{
+		vendor.ID.Hex(),
+		vendor.Cover,
+		vendor.Type,
+		vendor.Name,
+		vendor.Location,
+		strings.Join(vendor.PhoneNumbers, e.delimiter),
+		strings.Join(vendor.Websites, e.delimiter),
+		strings.Join(vendor.SocialNetworks, e.delimiter),
+		strings.Join(vendor.Media, e.delimiter),
+		strings.Join(vendor.Tags, e.delimiter),
+		strings.Join(vendor.Categories, e.delimiter),
+		longitude,
+		latitude,
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(vendor *domain.GetVendorResponse) error {
+	return e.encoder.Encode(vendor)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+// jsonArrayEncoder writes a single JSON array, one element at a time.
+type jsonArrayEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *jsonArrayEncoder) Encode(vendor *domain.GetVendorResponse) error {
+	separator := ","
+	if !e.started {
+		separator = "["
+		e.started = true
+	}
+
+	element, err := json.Marshal(vendor)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(element)
+	return err
+}
+
+func (e *jsonArrayEncoder) Close() error {
+	closing := "]\n"
+	if !e.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
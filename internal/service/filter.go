@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"vendors/internal/domain"
+)
+
+// presenceParams maps the presence query parameters to the fields they test.
+var presenceParams = map[string]string{
+	"has_cover":       "cover",
+	"has_location":    "location",
+	"has_phone":       "phone_numbers",
+	"has_website":     "websites",
+	"has_social":      "social_networks",
+	"has_media":       "media",
+	"has_coordinates": "coordinates",
+}
+
+// ParseVendorFilter reads a filter from query parameters. Tags and categories
+// may be repeated or comma separated, and are matched as the tags_match and
+// categories_match parameters say, or the match parameter for either one
+// left out; presence parameters take a boolean.
+func (s *VendorService) ParseVendorFilter(query url.Values) (domain.VendorFilter, error) {
+	mode, err := domain.ParseMatchMode(query.Get("match"))
+	if err != nil {
+		return domain.VendorFilter{}, err
+	}
+	tagsMode, err := matchParam(query, "tags_match", mode)
+	if err != nil {
+		return domain.VendorFilter{}, err
+	}
+	categoriesMode, err := matchParam(query, "categories_match", mode)
+	if err != nil {
+		return domain.VendorFilter{}, err
+	}
+
+	filter := domain.VendorFilter{
+		Tags: domain.ValueMatch{
+			Values:  listParam(query["tags"]),
+			Mode:    tagsMode,
+			Exclude: listParam(query["exclude_tags"]),
+		},
+		Categories: domain.ValueMatch{
+			Values:  listParam(query["categories"]),
+			Mode:    categoriesMode,
+			Exclude: listParam(query["exclude_categories"]),
+		},
+		Type:  query.Get("type"),
+		Query: strings.TrimSpace(query.Get("q")),
+	}
+
+	for param, field := range presenceParams {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		present, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be true or false", domain.ErrInvalidFilter, param)
+		}
+		if filter.Presence == nil {
+			filter.Presence = map[string]bool{}
+		}
+		filter.Presence[field] = present
+	}
+
+	return filter, filter.Validate()
+}
+
+// matchParam reads the match mode in param, falling back to fallback when the
+// parameter is absent.
+func matchParam(query url.Values, param string, fallback domain.MatchMode) (domain.MatchMode, error) {
+	value := query.Get(param)
+	if value == "" {
+		return fallback, nil
+	}
+	mode, err := domain.ParseMatchMode(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be all, any or none", domain.ErrInvalidFilter, param)
+	}
+	return mode, nil
+}
+
+func listParam(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// FilterVendors returns a page of vendors satisfying filter together with the
+// total number of matches. The type of the filter overrides opts.Type.
+func (s *VendorService) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	opts.Type = filter.Type
+
+	total, err := s.VendorRepository.CountFilteredVendors(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vendors, err := s.VendorRepository.FilterVendors(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vendors, total, nil
+}
@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"net/url"
+	"testing"
+	"vendors/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVendorFilterMatchModes(t *testing.T) {
+	s := newVendorService()
+
+	tests := []struct {
+		name       string
+		query      string
+		tags       domain.MatchMode
+		categories domain.MatchMode
+	}{
+		{name: "Default", query: "tags=pizza&categories=bar", tags: domain.MatchAll, categories: domain.MatchAll},
+		{name: "Shared", query: "match=any", tags: domain.MatchAny, categories: domain.MatchAny},
+		{name: "Mixed", query: "tags_match=any&categories_match=none", tags: domain.MatchAny, categories: domain.MatchNone},
+		{name: "Overriding shared", query: "match=none&tags_match=all", tags: domain.MatchAll, categories: domain.MatchNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			filter, err := s.ParseVendorFilter(query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.tags, filter.Tags.Mode)
+			assert.Equal(t, tt.categories, filter.Categories.Mode)
+		})
+	}
+
+	for _, query := range []url.Values{{"tags_match": {"some"}}, {"categories_match": {"some"}}, {"match": {"some"}}} {
+		_, err := s.ParseVendorFilter(query)
+		assert.ErrorIs(t, err, domain.ErrInvalidFilter, query.Encode())
+	}
+}
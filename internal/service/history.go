@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *VendorService) GetVendorHistory(ctx context.Context, id primitive.ObjectID) ([]*domain.VendorHistoryEntry, error) {
+	return s.HistoryRepository.GetEntries(ctx, id)
+}
+
+// RevertVendor writes the snapshot stored with revision back to the vendor.
+// The revert is itself recorded as a new revision.
+func (s *VendorService) RevertVendor(ctx context.Context, id primitive.ObjectID, revision int64, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	entry, err := s.HistoryRepository.GetEntry(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	update := domain.UpdateVendorRequest(entry.Snapshot)
+
+	return s.updateVendor(ctx, id, &update, expectedVersion, domain.HistoryActionReverted, revision)
+}
+
+// followUpTimeout bounds the writes that follow a committed vendor write.
+// They run detached from the request, so that a client hanging up once the
+// vendor is written doesn't lose them.
+const followUpTimeout = 10 * time.Second
+
+// detached returns a context that keeps the values of ctx, such as the actor,
+// but not its cancellation, for the writes that follow a committed one.
+func detached(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), followUpTimeout)
+}
+
+// record stores a history entry for a change that has already been written
+// and publishes an event for it. A failure is logged rather than returned,
+// since the vendor write itself succeeded and reporting an error would invite
+// a duplicate retry.
+func (s *VendorService) record(ctx context.Context, entry *domain.VendorHistoryEntry) {
+	ctx, cancel := detached(ctx)
+	defer cancel()
+
+	entry.Actor = domain.ActorFromContext(ctx)
+	entry.Timestamp = time.Now().UTC()
+
+	s.publish(ctx, entry)
+
+	if err := s.HistoryRepository.AddEntry(ctx, entry); err != nil {
+		slog.Error("error recording vendor history",
+			slog.String("vendor_id", entry.VendorID.Hex()),
+			slog.Int64("revision", entry.Revision),
+			utils.Err(err))
+	}
+}
@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hangUpAfterUpdate cancels the request once an update is written, like a
+// client that disconnects while the response is on its way.
+type hangUpAfterUpdate struct {
+	*repository.MemoryVendorRepository
+	cancel context.CancelFunc
+}
+
+func (r *hangUpAfterUpdate) UpdateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	updated, err := r.MemoryVendorRepository.UpdateVendor(ctx, id, update, expectedVersion)
+	r.cancel()
+	return updated, err
+}
+
+func TestHistoryOutlivesRequest(t *testing.T) {
+	s := newVendorService()
+
+	vendor, err := s.CreateVendor(context.Background(), &domain.CreateVendorRequest{Name: "Pizza Place", Type: domain.VendorTypeFood})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.VendorRepository = &hangUpAfterUpdate{MemoryVendorRepository: s.VendorRepository.(*repository.MemoryVendorRepository), cancel: cancel}
+
+	updated, err := s.UpdateVendor(ctx, vendor.ID, &domain.UpdateVendorRequest{Name: "Pizza Palace", Type: domain.VendorTypeFood}, 0)
+	require.NoError(t, err)
+
+	entries, err := s.GetVendorHistory(context.Background(), vendor.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, updated.Version, entries[len(entries)-1].Revision)
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxImportLineBytes bounds a single NDJSON line.
+const maxImportLineBytes = 1 << 20
+
+// importColumns lists the columns a CSV import may have. List columns hold
+// their values joined by the list delimiter, and longitude and latitude
+// together give the coordinates.
+var importColumns = []string{
+	"_id", "cover", "type", "name", "location",
+	"phone_numbers", "websites", "social_networks", "media", "tags", "categories",
+	"longitude", "latitude",
+}
+
+// importRecord is one NDJSON line: a vendor as it is created, and the _id to
+// match it by.
+type importRecord struct {
+	ID string `json:"_id"`
+	domain.CreateVendorRequest
+}
+
+// ImportVendors reads vendors from source and creates them, or updates the
+// vendors they match by the import key. Rows that can't be read or written
+// are reported as failed without holding up the others. A dry run checks the
+// rows and matches them, but writes nothing.
+func (s *VendorService) ImportVendors(ctx context.Context, source io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var rows []*domain.ImportRow
+	var err error
+	switch opts.Format {
+	case domain.ImportCSV:
+		rows, err = decodeCSV(source, opts.ListDelimiter)
+	case domain.ImportNDJSON:
+		rows, err = decodeNDJSON(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.ImportRowResult, len(rows))
+	for i, row := range rows {
+		results[i].Line = row.Line
+		if err := checkImportRow(row); err != nil {
+			results[i].Fail(err)
+		}
+	}
+
+	matched, err := s.matchImportRows(ctx, rows, results, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var creates []*domain.CreateVendorRequest
+	var updates []domain.BulkUpdate
+	var createRows, updateRows []int
+	for i, row := range rows {
+		if results[i].Action == domain.ImportFailed {
+			continue
+		}
+		if vendor := matched[i]; vendor != nil {
+			update := domain.UpdateVendorRequest(row.Apply(domain.SnapshotOf(domain.CommonVendorResponse(*vendor))))
+			updates = append(updates, domain.BulkUpdate{ID: vendor.ID, Vendor: &update, Version: vendor.Version})
+			updateRows = append(updateRows, i)
+			results[i].Action = domain.ImportUpdated
+			results[i].ID = vendor.ID
+			continue
+		}
+		if !slices.Contains(domain.VendorTypes, row.Vendor.Type) {
+			results[i].Fail(domain.ErrUnknownVendorType)
+			continue
+		}
+		creates = append(creates, &row.Vendor)
+		createRows = append(createRows, i)
+		results[i].Action = domain.ImportCreated
+	}
+
+	if !opts.DryRun {
+		writeImportBatches(results, createRows, func(from, to int) (*domain.BulkResult, error) {
+			return s.BulkCreateVendors(ctx, creates[from:to], false)
+		})
+		writeImportBatches(results, updateRows, func(from, to int) (*domain.BulkResult, error) {
+			return s.BulkUpdateVendors(ctx, updates[from:to], false)
+		})
+	}
+
+	return domain.NewImportReport(opts.DryRun, results), nil
+}
+
+// checkImportRow catches what would fail the write of a row up front, so
+// that a dry run reports it too. A row without a type can still update the
+// vendor it matches; one that would create a vendor fails once it is known
+// to match none.
+func checkImportRow(row *domain.ImportRow) error {
+	if row.Err != nil {
+		return row.Err
+	}
+	if row.Has("type") && !slices.Contains(domain.VendorTypes, row.Vendor.Type) {
+		return domain.ErrUnknownVendorType
+	}
+	return row.Vendor.Coordinates.Validate()
+}
+
+// matchImportRows returns, for every row, the vendor its key matches, if any.
+// It fails rows whose key repeats an earlier row's or matches more than one
+// vendor, and rows that would change the type of the vendor they match. A
+// row whose _id matches no vendor fails too, since ids are never chosen by
+// the client; a row whose name matches no vendor creates one.
+func (s *VendorService) matchImportRows(ctx context.Context, rows []*domain.ImportRow, results []domain.ImportRowResult, key string) ([]*domain.GetVendorResponse, error) {
+	matched := make([]*domain.GetVendorResponse, len(rows))
+	if key == "" {
+		return matched, nil
+	}
+
+	keys := make([]string, len(rows))
+	firstLine := map[string]int{}
+	var ids []primitive.ObjectID
+	var names []string
+	for i, row := range rows {
+		value := row.KeyValue(key)
+		if results[i].Action == domain.ImportFailed || value == "" {
+			continue
+		}
+
+		var id primitive.ObjectID
+		if key == domain.ImportKeyID {
+			var err error
+			if id, err = primitive.ObjectIDFromHex(value); err != nil {
+				results[i].Fail(fmt.Errorf("%w: invalid _id %q", domain.ErrInvalidImportRow, value))
+				continue
+			}
+			value = id.Hex()
+		}
+
+		if line, ok := firstLine[value]; ok {
+			results[i].Fail(fmt.Errorf("%w: %s %q is also on line %d", domain.ErrDuplicateImportKey, key, value, line))
+			continue
+		}
+		firstLine[value] = row.Line
+		keys[i] = value
+
+		if key == domain.ImportKeyID {
+			ids = append(ids, id)
+		} else {
+			names = append(names, value)
+		}
+	}
+
+	var vendors []*domain.GetVendorResponse
+	var err error
+	switch {
+	case len(ids) > 0:
+		vendors, err = s.VendorRepository.GetVendorsByIDs(ctx, ids)
+	case len(names) > 0:
+		vendors, err = s.VendorRepository.GetVendorsByNames(ctx, names)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]*domain.GetVendorResponse, len(vendors))
+	for _, vendor := range vendors {
+		value := vendor.Name
+		if key == domain.ImportKeyID {
+			value = vendor.ID.Hex()
+		}
+		byKey[value] = append(byKey[value], vendor)
+	}
+
+	for i, row := range rows {
+		if results[i].Action == domain.ImportFailed || keys[i] == "" {
+			continue
+		}
+
+		found := byKey[keys[i]]
+		switch {
+		case len(found) > 1:
+			results[i].Fail(fmt.Errorf("%w: %d vendors are named %q", domain.ErrAmbiguousImportKey, len(found), keys[i]))
+		case len(found) == 0 && key == domain.ImportKeyID:
+			results[i].Fail(domain.ErrVendorNotFound)
+		case len(found) == 0:
+		case row.Has("type") && found[0].Type != row.Vendor.Type:
+			results[i].Fail(domain.ErrVendorTypeChanged)
+		default:
+			matched[i] = found[0]
+		}
+	}
+
+	return matched, nil
+}
+
+// writeImportBatches writes the rows at positions in batches of at most
+// MaxBulkItems and copies the outcome of every item to its row. Once a batch
+// fails as a whole, it and the batches after it are reported failed; the
+// batches before it have been written.
+func writeImportBatches(results []domain.ImportRowResult, positions []int, write func(from, to int) (*domain.BulkResult, error)) {
+	var failed error
+	for from := 0; from < len(positions); from += domain.MaxBulkItems {
+		to := min(from+domain.MaxBulkItems, len(positions))
+
+		var written *domain.BulkResult
+		if failed == nil {
+			written, failed = write(from, to)
+		}
+		if failed != nil {
+			for _, i := range positions[from:to] {
+				results[i].ID = primitive.NilObjectID
+				results[i].Fail(failed)
+			}
+			continue
+		}
+
+		for j, item := range written.Results {
+			row := &results[positions[from+j]]
+			if item.Status != domain.BulkItemOK {
+				row.ID = primitive.NilObjectID
+				row.Fail(item.Err)
+				continue
+			}
+			row.ID = item.ID
+		}
+	}
+}
+
+// decodeCSV reads a CSV import. The first record names the columns, in any
+// order and any case; a column that isn't known fails the whole import.
+// Empty lines are skipped, and a record with a different number of fields
+// than the header fails only its row.
+func decodeCSV(source io.Reader, delimiter string) ([]*domain.ImportRow, error) {
+	reader := csv.NewReader(source)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(importColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrInvalidImport, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidImport, name)
+		}
+		columns[name] = i
+	}
+	fields := csvFields(columns)
+
+	var rows []*domain.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if len(rows) == domain.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain.ErrInvalidImport, domain.MaxImportRows)
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			rows = append(rows, &domain.ImportRow{
+				Line: parseErr.StartLine,
+				Err:  fmt.Errorf("%w: %d fields where the header has %d", domain.ErrInvalidImportRow, len(record), len(header)),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := csvRow(record, columns, delimiter, line)
+		row.Fields = fields
+		rows = append(rows, row)
+	}
+}
+
+// csvFields returns the vendor fields the columns of a CSV header carry.
+// Longitude and latitude together carry the coordinates.
+func csvFields(columns map[string]int) []string {
+	var fields []string
+	for name := range columns {
+		switch name {
+		case "_id":
+		case "longitude", "latitude":
+			if !slices.Contains(fields, "coordinates") {
+				fields = append(fields, "coordinates")
+			}
+		default:
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// csvError fails the import for malformed CSV. Any other error, such as the
+// body being too large, is returned as is.
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidImport, parseErr)
+	}
+	return err
+}
+
+func csvRow(record []string, columns map[string]int, delimiter string, line int) *domain.ImportRow {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	list := func(name string) []string {
+		values := []string{}
+		for _, value := range strings.Split(field(name), delimiter) {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	row := &domain.ImportRow{
+		Line: line,
+		ID:   field("_id"),
+		Vendor: domain.CreateVendorRequest{
+			Cover:          field("cover"),
+			Type:           field("type"),
+			Name:           field("name"),
+			Location:       field("location"),
+			PhoneNumbers:   list("phone_numbers"),
+			Websites:       list("websites"),
+			SocialNetworks: list("social_networks"),
+			Media:          list("media"),
+			Tags:           list("tags"),
+			Categories:     list("categories"),
+		},
+	}
+
+	longitude, latitude := field("longitude"), field("latitude")
+	if longitude == "" && latitude == "" {
+		return row
+	}
+	lng, lngErr := strconv.ParseFloat(longitude, 64)
+	lat, latErr := strconv.ParseFloat(latitude, 64)
+	if lngErr != nil || latErr != nil {
+		row.Err = fmt.Errorf("%w: longitude and latitude must both be numbers", domain.ErrInvalidImportRow)
+		return row
+	}
+	row.Vendor.Coordinates = domain.NewGeoPoint(lng, lat)
+
+	return row
+}
+
+// decodeNDJSON reads an NDJSON import: one vendor object per line, with the
+// fields of a created vendor and optionally an _id. Blank lines are skipped,
+// and a line that isn't such an object fails only its row.
+func decodeNDJSON(source io.Reader) ([]*domain.ImportRow, error) {
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(nil, maxImportLineBytes)
+
+	var rows []*domain.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == domain.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain.ErrInvalidImport, domain.MaxImportRows)
+		}
+
+		row := &domain.ImportRow{Line: line}
+		rows = append(rows, row)
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+
+		var record importRecord
+		if err := decoder.Decode(&record); err != nil {
+			row.Err = fmt.Errorf("%w: %v", domain.ErrInvalidImportRow, err)
+			continue
+		}
+		if decoder.More() {
+			row.Err = fmt.Errorf("%w: more than one value on the line", domain.ErrInvalidImportRow)
+			continue
+		}
+		row.ID = record.ID
+		row.Vendor = record.CreateVendorRequest
+		row.Fields = ndjsonFields(text)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: a line is longer than %d bytes", domain.ErrInvalidImport, maxImportLineBytes)
+		}
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ndjsonFields returns the vendor fields an NDJSON object carries, which are
+// its keys other than _id. Keys are matched to fields regardless of case, as
+// the decoder does. The object has already been decoded once, so it is known
+// to be valid.
+func ndjsonFields(object []byte) []string {
+	var values map[string]json.RawMessage
+	json.Unmarshal(object, &values)
+
+	fields := make([]string, 0, len(values))
+	for name := range values {
+		if name = strings.ToLower(name); name != "_id" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
@@ -1,20 +1,44 @@
 package service
 
 import (
+	"context"
+	"io"
+	"net/url"
 	"vendors/internal/domain"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-//go:generate mockgen -source=vendor_service.go -destination=mocks/vendor_service_mock.go
+//go:generate mockgen -source=vendor_service.go -destination=../mocks/vendor_service_mock.go
 
 type VendorService interface {
-	GetAllVendors(page, pageSize int) ([]*domain.GetVendorResponse, error)
-	GetTotalVendorsCount() (int, error)
-	GetVendorByID(id primitive.ObjectID) (*domain.GetVendorResponse, error)
-	CreateVendor(request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error)
-	UpdateVendor(id primitive.ObjectID, request *domain.UpdateVendorRequest) (*domain.UpdateVendorResponse, error)
-	DeleteVendor(id primitive.ObjectID) error
-	SearchVendors(query string, page int, pageSize int) ([]*domain.GetVendorResponse, error)
-	FilterVendorsByTags(tags []string, page int, pageSize int) ([]*domain.GetVendorResponse, error)
+	GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error)
+	GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error)
+	GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error)
+	ResolveVendor(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error)
+	CreateVendor(ctx context.Context, request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error)
+	UpdateVendor(ctx context.Context, id primitive.ObjectID, request *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	PatchVendor(ctx context.Context, id primitive.ObjectID, format domain.PatchFormat, patch []byte, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error
+	GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error)
+	GetDeletedVendorsCount(ctx context.Context) (int, error)
+	RestoreVendor(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedVendors(ctx context.Context) (int, error)
+	GetVendorHistory(ctx context.Context, id primitive.ObjectID) ([]*domain.VendorHistoryEntry, error)
+	RevertVendor(ctx context.Context, id primitive.ObjectID, revision int64, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, int, error)
+	FindVendorsNear(ctx context.Context, lat, lng, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, int, error)
+	GetVendorMap(ctx context.Context, query domain.MapQuery) (*domain.VendorMap, error)
+	BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) (*domain.BulkResult, error)
+	BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) (*domain.BulkResult, error)
+	BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) (*domain.BulkResult, error)
+	FindDuplicateVendors(ctx context.Context, query domain.DuplicateQuery) (*domain.DuplicateReport, error)
+	MergeVendors(ctx context.Context, id primitive.ObjectID, request domain.MergeRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error)
+	ImportVendors(ctx context.Context, source io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error)
+	ExportVendors(ctx context.Context, w io.Writer, filter domain.VendorFilter, order domain.Sort, opts domain.ExportOptions) error
+	GetVendorFacets(ctx context.Context, fields []string, vendorType string) (domain.Facets, error)
+	ParseVendorFilter(query url.Values) (domain.VendorFilter, error)
+	FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, int, error)
+	FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, int, error)
+	StreamVendorEvents(ctx context.Context, filter domain.VendorFilter, lastEventID string) (<-chan *domain.VendorEvent, error)
 }
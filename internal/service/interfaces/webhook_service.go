@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=webhook_service.go -destination=../mocks/webhook_service_mock.go
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, request *domain.CreateWebhookRequest) (*domain.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	GetWebhook(ctx context.Context, id primitive.ObjectID) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) error
+	GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"vendors/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResolveVendor returns the live vendor with the given ID or, when there is
+// none, the vendor it was merged into.
+func (s *VendorService) ResolveVendor(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	vendor, err := s.VendorRepository.GetVendorByID(ctx, id)
+	if err != nil || vendor != nil {
+		return vendor, err
+	}
+	return s.VendorRepository.GetVendorByAlias(ctx, id)
+}
+
+// FindDuplicateVendors reads the live vendors of the query's type, or of
+// every type, that share a duplicate key with another one, and reports the
+// pairs that look like the same vendor entered twice.
+func (s *VendorService) FindDuplicateVendors(ctx context.Context, query domain.DuplicateQuery) (*domain.DuplicateReport, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if query.Type != "" && !slices.Contains(domain.VendorTypes, query.Type) {
+		return nil, domain.ErrUnknownVendorType
+	}
+
+	vendors, err := s.VendorRepository.FindDuplicateCandidates(ctx, query.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.FindDuplicates(vendors, query), nil
+}
+
+// MergeVendors folds the vendors named by request into the vendor with the
+// given ID, which keeps its ID and takes on their IDs as aliases. The merged
+// vendors are then moved to the trash. A merge that fails part way, say
+// because a duplicate changed in the meantime, returns a PartialMergeError
+// and can be retried as is: duplicates already aliased and in the trash are
+// skipped, and those aliased but still live are merged again.
+func (s *VendorService) MergeVendors(ctx context.Context, id primitive.ObjectID, request domain.MergeRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	if err := request.Validate(id); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		before, err := s.VendorRepository.GetVendorByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil {
+			return nil, domain.ErrVendorNotFound
+		}
+
+		version := expectedVersion
+		if version == 0 {
+			version = before.Version
+		}
+
+		duplicates, deleted, err := s.mergeDuplicates(ctx, before, request.IDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) == 0 {
+			merged := domain.UpdateVendorResponse(*before)
+			return &merged, nil
+		}
+
+		snapshot := domain.SnapshotOf(domain.CommonVendorResponse(*before))
+		snapshots := make([]domain.CommonVendorRequest, len(duplicates))
+		var aliases []primitive.ObjectID
+		for i, duplicate := range duplicates {
+			snapshots[i] = domain.SnapshotOf(domain.CommonVendorResponse(*duplicate))
+			aliases = append(aliases, duplicate.ID)
+			aliases = append(aliases, duplicate.Aliases...)
+		}
+		merged := domain.UpdateVendorRequest(domain.MergeVendors(snapshot, snapshots...))
+
+		updated, err := s.VendorRepository.MergeVendor(ctx, id, &merged, aliases, version)
+		if errors.Is(err, domain.ErrVersionConflict) && expectedVersion == 0 && attempt < maxWriteAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		updatedSnapshot := domain.SnapshotOf(domain.CommonVendorResponse(*updated))
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: id,
+			Revision: updated.Version,
+			Action:   domain.HistoryActionMerged,
+			Changes:  domain.DiffVendors(snapshot, updatedSnapshot),
+			Snapshot: updatedSnapshot,
+		})
+
+		for _, duplicate := range duplicates {
+			if err := s.VendorRepository.DeleteVendor(ctx, duplicate.ID, duplicate.Version); err != nil {
+				return nil, &domain.PartialMergeError{Deleted: deleted, Version: updated.Version, Err: err}
+			}
+			deleted = append(deleted, duplicate.ID)
+
+			s.record(ctx, &domain.VendorHistoryEntry{
+				VendorID:   duplicate.ID,
+				Revision:   duplicate.Version + 1,
+				Action:     domain.HistoryActionDeleted,
+				Changes:    []domain.FieldChange{},
+				Snapshot:   domain.SnapshotOf(domain.CommonVendorResponse(*duplicate)),
+				MergedInto: &id,
+			})
+		}
+
+		return updated, nil
+	}
+}
+
+// mergeDuplicates reads the vendors to merge into vendor, in the order they
+// were asked for, and checks they can be merged into it. Vendors no longer
+// live that are already among its aliases were merged and deleted by an
+// earlier attempt; they are returned apart.
+func (s *VendorService) mergeDuplicates(ctx context.Context, vendor *domain.GetVendorResponse, ids []primitive.ObjectID) ([]*domain.GetVendorResponse, []primitive.ObjectID, error) {
+	found, err := s.VendorRepository.GetVendorsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.GetVendorResponse, len(found))
+	for _, duplicate := range found {
+		byID[duplicate.ID] = duplicate
+	}
+
+	duplicates := make([]*domain.GetVendorResponse, 0, len(ids))
+	deleted := []primitive.ObjectID{}
+	for _, id := range ids {
+		duplicate, ok := byID[id]
+		if !ok && slices.Contains(vendor.Aliases, id) {
+			deleted = append(deleted, id)
+			continue
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: vendor %s not found", domain.ErrInvalidMerge, id.Hex())
+		}
+		if duplicate.Type != vendor.Type {
+			return nil, nil, fmt.Errorf("%w: vendor %s is of type %s, not %s", domain.ErrInvalidMerge, id.Hex(), duplicate.Type, vendor.Type)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	return duplicates, deleted, nil
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// failingDeletes fails the next delete of each vendor in fail.
+type failingDeletes struct {
+	*repository.MemoryVendorRepository
+	fail map[primitive.ObjectID]bool
+}
+
+func (r *failingDeletes) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	if r.fail[id] {
+		delete(r.fail, id)
+		return errors.New("connection reset")
+	}
+	return r.MemoryVendorRepository.DeleteVendor(ctx, id, expectedVersion)
+}
+
+func TestMergeVendorsRetriesAfterPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := &failingDeletes{MemoryVendorRepository: repository.NewMemoryVendorRepository(), fail: map[primitive.ObjectID]bool{}}
+	s := newVendorService()
+	s.VendorRepository = repo
+
+	var ids []primitive.ObjectID
+	for _, name := range []string{"Pizza Place", "Pizzaplace", "Pizza-Place"} {
+		vendor, err := s.CreateVendor(ctx, &domain.CreateVendorRequest{Name: name, Type: domain.VendorTypeFood, Tags: []string{name}})
+		require.NoError(t, err)
+		ids = append(ids, vendor.ID)
+	}
+	survivor, duplicates := ids[0], ids[1:]
+	repo.fail[duplicates[1]] = true
+
+	_, err := s.MergeVendors(ctx, survivor, domain.MergeRequest{IDs: duplicates}, 1)
+	var partial *domain.PartialMergeError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, []primitive.ObjectID{duplicates[0]}, partial.Deleted)
+	assert.Equal(t, int64(2), partial.Version)
+
+	merged, err := s.MergeVendors(ctx, survivor, domain.MergeRequest{IDs: duplicates}, partial.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Pizza Place", "Pizzaplace", "Pizza-Place"}, merged.Tags)
+	assert.ElementsMatch(t, duplicates, merged.Aliases)
+
+	for _, id := range duplicates {
+		vendor, err := s.GetVendorByID(ctx, id)
+		require.NoError(t, err)
+		assert.Nil(t, vendor, "every duplicate ends up in the trash")
+	}
+
+	again, err := s.MergeVendors(ctx, survivor, domain.MergeRequest{IDs: duplicates}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, merged.Version, again.Version, "a finished merge is not written again")
+}
@@ -0,0 +1,475 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vendor_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	io "io"
+	url "net/url"
+	reflect "reflect"
+	domain "vendors/internal/domain"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockVendorService is a mock of VendorService interface.
+type MockVendorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVendorServiceMockRecorder
+}
+
+// MockVendorServiceMockRecorder is the mock recorder for MockVendorService.
+type MockVendorServiceMockRecorder struct {
+	mock *MockVendorService
+}
+
+// NewMockVendorService creates a new mock instance.
+func NewMockVendorService(ctrl *gomock.Controller) *MockVendorService {
+	mock := &MockVendorService{ctrl: ctrl}
+	mock.recorder = &MockVendorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVendorService) EXPECT() *MockVendorServiceMockRecorder {
+	return m.recorder
+}
+
+// BulkCreateVendors mocks base method.
+func (m *MockVendorService) BulkCreateVendors(ctx context.Context, vendors []*domain.CreateVendorRequest, ordered bool) (*domain.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkCreateVendors", ctx, vendors, ordered)
+	ret0, _ := ret[0].(*domain.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkCreateVendors indicates an expected call of BulkCreateVendors.
+func (mr *MockVendorServiceMockRecorder) BulkCreateVendors(ctx, vendors, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateVendors", reflect.TypeOf((*MockVendorService)(nil).BulkCreateVendors), ctx, vendors, ordered)
+}
+
+// BulkDeleteVendors mocks base method.
+func (m *MockVendorService) BulkDeleteVendors(ctx context.Context, deletes []domain.BulkDelete, ordered bool) (*domain.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDeleteVendors", ctx, deletes, ordered)
+	ret0, _ := ret[0].(*domain.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkDeleteVendors indicates an expected call of BulkDeleteVendors.
+func (mr *MockVendorServiceMockRecorder) BulkDeleteVendors(ctx, deletes, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeleteVendors", reflect.TypeOf((*MockVendorService)(nil).BulkDeleteVendors), ctx, deletes, ordered)
+}
+
+// BulkUpdateVendors mocks base method.
+func (m *MockVendorService) BulkUpdateVendors(ctx context.Context, updates []domain.BulkUpdate, ordered bool) (*domain.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateVendors", ctx, updates, ordered)
+	ret0, _ := ret[0].(*domain.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateVendors indicates an expected call of BulkUpdateVendors.
+func (mr *MockVendorServiceMockRecorder) BulkUpdateVendors(ctx, updates, ordered interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateVendors", reflect.TypeOf((*MockVendorService)(nil).BulkUpdateVendors), ctx, updates, ordered)
+}
+
+// CreateVendor mocks base method.
+func (m *MockVendorService) CreateVendor(ctx context.Context, request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVendor", ctx, request)
+	ret0, _ := ret[0].(*domain.CreateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVendor indicates an expected call of CreateVendor.
+func (mr *MockVendorServiceMockRecorder) CreateVendor(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVendor", reflect.TypeOf((*MockVendorService)(nil).CreateVendor), ctx, request)
+}
+
+// DeleteVendor mocks base method.
+func (m *MockVendorService) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVendor", ctx, id, expectedVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVendor indicates an expected call of DeleteVendor.
+func (mr *MockVendorServiceMockRecorder) DeleteVendor(ctx, id, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVendor", reflect.TypeOf((*MockVendorService)(nil).DeleteVendor), ctx, id, expectedVersion)
+}
+
+// ExportVendors mocks base method.
+func (m *MockVendorService) ExportVendors(ctx context.Context, w io.Writer, filter domain.VendorFilter, order domain.Sort, opts domain.ExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportVendors", ctx, w, filter, order, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportVendors indicates an expected call of ExportVendors.
+func (mr *MockVendorServiceMockRecorder) ExportVendors(ctx, w, filter, order, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportVendors", reflect.TypeOf((*MockVendorService)(nil).ExportVendors), ctx, w, filter, order, opts)
+}
+
+// FilterVendors mocks base method.
+func (m *MockVendorService) FilterVendors(ctx context.Context, filter domain.VendorFilter, opts domain.ListOptions) (*domain.VendorList, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterVendors", ctx, filter, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FilterVendors indicates an expected call of FilterVendors.
+func (mr *MockVendorServiceMockRecorder) FilterVendors(ctx, filter, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVendors", reflect.TypeOf((*MockVendorService)(nil).FilterVendors), ctx, filter, opts)
+}
+
+// FilterVendorsByTags mocks base method.
+func (m *MockVendorService) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterVendorsByTags", ctx, tags, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FilterVendorsByTags indicates an expected call of FilterVendorsByTags.
+func (mr *MockVendorServiceMockRecorder) FilterVendorsByTags(ctx, tags, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVendorsByTags", reflect.TypeOf((*MockVendorService)(nil).FilterVendorsByTags), ctx, tags, opts)
+}
+
+// FindDuplicateVendors mocks base method.
+func (m *MockVendorService) FindDuplicateVendors(ctx context.Context, query domain.DuplicateQuery) (*domain.DuplicateReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicateVendors", ctx, query)
+	ret0, _ := ret[0].(*domain.DuplicateReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicateVendors indicates an expected call of FindDuplicateVendors.
+func (mr *MockVendorServiceMockRecorder) FindDuplicateVendors(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicateVendors", reflect.TypeOf((*MockVendorService)(nil).FindDuplicateVendors), ctx, query)
+}
+
+// FindVendorsNear mocks base method.
+func (m *MockVendorService) FindVendorsNear(ctx context.Context, lat, lng, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVendorsNear", ctx, lat, lng, radiusMeters, opts)
+	ret0, _ := ret[0].(*domain.NearbyVendorList)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindVendorsNear indicates an expected call of FindVendorsNear.
+func (mr *MockVendorServiceMockRecorder) FindVendorsNear(ctx, lat, lng, radiusMeters, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVendorsNear", reflect.TypeOf((*MockVendorService)(nil).FindVendorsNear), ctx, lat, lng, radiusMeters, opts)
+}
+
+// GetAllVendors mocks base method.
+func (m *MockVendorService) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllVendors", ctx, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllVendors indicates an expected call of GetAllVendors.
+func (mr *MockVendorServiceMockRecorder) GetAllVendors(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllVendors", reflect.TypeOf((*MockVendorService)(nil).GetAllVendors), ctx, opts)
+}
+
+// GetDeletedVendors mocks base method.
+func (m *MockVendorService) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedVendors", ctx, opts)
+	ret0, _ := ret[0].(*domain.VendorList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedVendors indicates an expected call of GetDeletedVendors.
+func (mr *MockVendorServiceMockRecorder) GetDeletedVendors(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedVendors", reflect.TypeOf((*MockVendorService)(nil).GetDeletedVendors), ctx, opts)
+}
+
+// GetDeletedVendorsCount mocks base method.
+func (m *MockVendorService) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedVendorsCount", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedVendorsCount indicates an expected call of GetDeletedVendorsCount.
+func (mr *MockVendorServiceMockRecorder) GetDeletedVendorsCount(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedVendorsCount", reflect.TypeOf((*MockVendorService)(nil).GetDeletedVendorsCount), ctx)
+}
+
+// GetTotalVendorsCount mocks base method.
+func (m *MockVendorService) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalVendorsCount", ctx, vendorType)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalVendorsCount indicates an expected call of GetTotalVendorsCount.
+func (mr *MockVendorServiceMockRecorder) GetTotalVendorsCount(ctx, vendorType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalVendorsCount", reflect.TypeOf((*MockVendorService)(nil).GetTotalVendorsCount), ctx, vendorType)
+}
+
+// GetVendorByID mocks base method.
+func (m *MockVendorService) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorByID", ctx, id)
+	ret0, _ := ret[0].(*domain.GetVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorByID indicates an expected call of GetVendorByID.
+func (mr *MockVendorServiceMockRecorder) GetVendorByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorByID", reflect.TypeOf((*MockVendorService)(nil).GetVendorByID), ctx, id)
+}
+
+// GetVendorFacets mocks base method.
+func (m *MockVendorService) GetVendorFacets(ctx context.Context, fields []string, vendorType string) (domain.Facets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorFacets", ctx, fields, vendorType)
+	ret0, _ := ret[0].(domain.Facets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorFacets indicates an expected call of GetVendorFacets.
+func (mr *MockVendorServiceMockRecorder) GetVendorFacets(ctx, fields, vendorType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorFacets", reflect.TypeOf((*MockVendorService)(nil).GetVendorFacets), ctx, fields, vendorType)
+}
+
+// GetVendorHistory mocks base method.
+func (m *MockVendorService) GetVendorHistory(ctx context.Context, id primitive.ObjectID) ([]*domain.VendorHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorHistory", ctx, id)
+	ret0, _ := ret[0].([]*domain.VendorHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorHistory indicates an expected call of GetVendorHistory.
+func (mr *MockVendorServiceMockRecorder) GetVendorHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorHistory", reflect.TypeOf((*MockVendorService)(nil).GetVendorHistory), ctx, id)
+}
+
+// GetVendorMap mocks base method.
+func (m *MockVendorService) GetVendorMap(ctx context.Context, query domain.MapQuery) (*domain.VendorMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVendorMap", ctx, query)
+	ret0, _ := ret[0].(*domain.VendorMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVendorMap indicates an expected call of GetVendorMap.
+func (mr *MockVendorServiceMockRecorder) GetVendorMap(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVendorMap", reflect.TypeOf((*MockVendorService)(nil).GetVendorMap), ctx, query)
+}
+
+// ImportVendors mocks base method.
+func (m *MockVendorService) ImportVendors(ctx context.Context, source io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportVendors", ctx, source, opts)
+	ret0, _ := ret[0].(*domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportVendors indicates an expected call of ImportVendors.
+func (mr *MockVendorServiceMockRecorder) ImportVendors(ctx, source, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportVendors", reflect.TypeOf((*MockVendorService)(nil).ImportVendors), ctx, source, opts)
+}
+
+// MergeVendors mocks base method.
+func (m *MockVendorService) MergeVendors(ctx context.Context, id primitive.ObjectID, request domain.MergeRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeVendors", ctx, id, request, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeVendors indicates an expected call of MergeVendors.
+func (mr *MockVendorServiceMockRecorder) MergeVendors(ctx, id, request, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeVendors", reflect.TypeOf((*MockVendorService)(nil).MergeVendors), ctx, id, request, expectedVersion)
+}
+
+// ParseVendorFilter mocks base method.
+func (m *MockVendorService) ParseVendorFilter(query url.Values) (domain.VendorFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseVendorFilter", query)
+	ret0, _ := ret[0].(domain.VendorFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseVendorFilter indicates an expected call of ParseVendorFilter.
+func (mr *MockVendorServiceMockRecorder) ParseVendorFilter(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseVendorFilter", reflect.TypeOf((*MockVendorService)(nil).ParseVendorFilter), query)
+}
+
+// PatchVendor mocks base method.
+func (m *MockVendorService) PatchVendor(ctx context.Context, id primitive.ObjectID, format domain.PatchFormat, patch []byte, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchVendor", ctx, id, format, patch, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchVendor indicates an expected call of PatchVendor.
+func (mr *MockVendorServiceMockRecorder) PatchVendor(ctx, id, format, patch, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchVendor", reflect.TypeOf((*MockVendorService)(nil).PatchVendor), ctx, id, format, patch, expectedVersion)
+}
+
+// PurgeDeletedVendors mocks base method.
+func (m *MockVendorService) PurgeDeletedVendors(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedVendors", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedVendors indicates an expected call of PurgeDeletedVendors.
+func (mr *MockVendorServiceMockRecorder) PurgeDeletedVendors(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedVendors", reflect.TypeOf((*MockVendorService)(nil).PurgeDeletedVendors), ctx)
+}
+
+// ResolveVendor mocks base method.
+func (m *MockVendorService) ResolveVendor(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveVendor", ctx, id)
+	ret0, _ := ret[0].(*domain.GetVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveVendor indicates an expected call of ResolveVendor.
+func (mr *MockVendorServiceMockRecorder) ResolveVendor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveVendor", reflect.TypeOf((*MockVendorService)(nil).ResolveVendor), ctx, id)
+}
+
+// RestoreVendor mocks base method.
+func (m *MockVendorService) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVendor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVendor indicates an expected call of RestoreVendor.
+func (mr *MockVendorServiceMockRecorder) RestoreVendor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVendor", reflect.TypeOf((*MockVendorService)(nil).RestoreVendor), ctx, id)
+}
+
+// RevertVendor mocks base method.
+func (m *MockVendorService) RevertVendor(ctx context.Context, id primitive.ObjectID, revision, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertVendor", ctx, id, revision, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertVendor indicates an expected call of RevertVendor.
+func (mr *MockVendorServiceMockRecorder) RevertVendor(ctx, id, revision, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertVendor", reflect.TypeOf((*MockVendorService)(nil).RevertVendor), ctx, id, revision, expectedVersion)
+}
+
+// SearchVendors mocks base method.
+func (m *MockVendorService) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchVendors", ctx, query, opts)
+	ret0, _ := ret[0].(*domain.SearchVendorList)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchVendors indicates an expected call of SearchVendors.
+func (mr *MockVendorServiceMockRecorder) SearchVendors(ctx, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchVendors", reflect.TypeOf((*MockVendorService)(nil).SearchVendors), ctx, query, opts)
+}
+
+// StreamVendorEvents mocks base method.
+func (m *MockVendorService) StreamVendorEvents(ctx context.Context, filter domain.VendorFilter, lastEventID string) (<-chan *domain.VendorEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamVendorEvents", ctx, filter, lastEventID)
+	ret0, _ := ret[0].(<-chan *domain.VendorEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamVendorEvents indicates an expected call of StreamVendorEvents.
+func (mr *MockVendorServiceMockRecorder) StreamVendorEvents(ctx, filter, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamVendorEvents", reflect.TypeOf((*MockVendorService)(nil).StreamVendorEvents), ctx, filter, lastEventID)
+}
+
+// UpdateVendor mocks base method.
+func (m *MockVendorService) UpdateVendor(ctx context.Context, id primitive.ObjectID, request *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVendor", ctx, id, request, expectedVersion)
+	ret0, _ := ret[0].(*domain.UpdateVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVendor indicates an expected call of UpdateVendor.
+func (mr *MockVendorServiceMockRecorder) UpdateVendor(ctx, id, request, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVendor", reflect.TypeOf((*MockVendorService)(nil).UpdateVendor), ctx, id, request, expectedVersion)
+}
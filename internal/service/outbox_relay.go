@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+	"vendors/pkg/lib/utils"
+)
+
+// relayBatch bounds how many outbox records are claimed at once.
+const relayBatch = 50
+
+// EventPublisher hands vendor events on to one kind of consumer.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.VendorEvent) error
+}
+
+// EventPublisherFunc lets an ordinary function act as an EventPublisher.
+type EventPublisherFunc func(ctx context.Context, event *domain.VendorEvent) error
+
+func (f EventPublisherFunc) Publish(ctx context.Context, event *domain.VendorEvent) error {
+	return f(ctx, event)
+}
+
+// OutboxRelay publishes the events vendor writes added to the outbox. A
+// record is removed only once every publisher has taken its event, and is
+// otherwise handed to all of them again later, so publishers see every event
+// at least once and possibly more than once.
+type OutboxRelay struct {
+	repository repository.OutboxRepository
+	publishers []EventPublisher
+	cfg        config.Outbox
+	wake       chan struct{}
+}
+
+func NewOutboxRelay(outboxRepository repository.OutboxRepository, cfg config.Outbox, publishers ...EventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		repository: outboxRepository,
+		publishers: publishers,
+		cfg:        cfg,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Run relays available records every poll interval, and as soon as the
+// service has made a change, until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	if r.cfg.PollInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+
+		if _, err := r.RelayAvailable(ctx); err != nil {
+			slog.Error("error relaying outbox records", utils.Err(err))
+		}
+	}
+}
+
+// RelayAvailable publishes every available record, oldest first, and returns
+// how many were attempted.
+func (r *OutboxRelay) RelayAvailable(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		claimed, err := r.repository.ClaimOutboxRecords(ctx, time.Now().UTC(), r.cfg.Lease, relayBatch)
+		if err != nil {
+			return attempted, err
+		}
+
+		for _, record := range claimed {
+			r.relay(ctx, record)
+		}
+
+		attempted += len(claimed)
+		if len(claimed) < relayBatch {
+			return attempted, nil
+		}
+	}
+}
+
+// relay hands one record's event to every publisher. When one of them fails
+// the record is released for a later attempt, which goes to all of them.
+func (r *OutboxRelay) relay(ctx context.Context, record *domain.OutboxRecord) {
+	for _, publisher := range r.publishers {
+		if err := publisher.Publish(ctx, &record.Event); err != nil {
+			r.release(ctx, record, err)
+			return
+		}
+	}
+
+	if err := r.repository.DeleteOutboxRecord(ctx, record.ID); err != nil {
+		slog.Error("error deleting relayed outbox record", slog.String("record_id", record.ID.Hex()), utils.Err(err))
+	}
+}
+
+func (r *OutboxRelay) release(ctx context.Context, record *domain.OutboxRecord, cause error) {
+	record.Attempts++
+	record.LastError = cause.Error()
+	record.AvailableAt = time.Now().UTC().Add(backoff(r.cfg.InitialBackoff, r.cfg.MaxBackoff, record.Attempts))
+
+	slog.Warn("error publishing outbox record",
+		slog.String("record_id", record.ID.Hex()),
+		slog.String("vendor_id", record.Event.VendorID.Hex()),
+		slog.Int("attempts", record.Attempts),
+		utils.Err(cause))
+
+	if err := r.repository.ReleaseOutboxRecord(ctx, record); err != nil {
+		slog.Error("error releasing outbox record", slog.String("record_id", record.ID.Hex()), utils.Err(err))
+	}
+}
+
+func (r *OutboxRelay) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// EventPublishers returns the publishers the service itself hands its events
+// to: the event broker and the webhooks.
+func (s *VendorService) EventPublishers() []EventPublisher {
+	return []EventPublisher{
+		EventPublisherFunc(func(_ context.Context, event *domain.VendorEvent) error {
+			s.broker.Publish(event)
+			return nil
+		}),
+		EventPublisherFunc(s.webhooks.Enqueue),
+	}
+}
+
+// PublishThrough leaves publishing to relay, for a vendor repository that
+// adds the event of every write to the outbox itself. The service then only
+// wakes the relay after a change.
+func (s *VendorService) PublishThrough(relay *OutboxRelay) {
+	s.relay = relay
+}
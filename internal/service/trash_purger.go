@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"vendors/pkg/lib/utils"
+)
+
+// RunTrashPurger purges expired vendors from the trash every interval until
+// ctx is cancelled. A purge that fails is logged and retried on the next tick.
+func (s *VendorService) RunTrashPurger(ctx context.Context) {
+	if s.trash.PurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.trash.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeDeletedVendors(ctx)
+			if err != nil {
+				slog.Error("error purging deleted vendors", utils.Err(err))
+				continue
+			}
+			if purged > 0 {
+				slog.Info("purged deleted vendors", slog.Int("count", purged))
+			}
+		}
+	}
+}
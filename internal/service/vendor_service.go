@@ -1,48 +1,351 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	"vendors/internal/config"
 	"vendors/internal/domain"
 	repository "vendors/internal/repository/interfaces"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// maxWriteAttempts bounds how often a write without a caller supplied version
+// is retried when another writer bumps the version between our read and write.
+const maxWriteAttempts = 3
+
+// VendorService publishes an event for every change it makes and queues it
+// for the webhooks subscribed to it. EventSource is where event streams are
+// read from; it defaults to those published events and may be replaced by a
+// source that sees changes made by every instance. With an outbox relay the
+// relay publishes the events instead.
 type VendorService struct {
-	VendorRepository repository.VendorRepository
+	VendorRepository  repository.VendorRepository
+	HistoryRepository repository.HistoryRepository
+	EventSource       repository.VendorEventSource
+	broker            *EventBroker
+	webhooks          *WebhookService
+	relay             *OutboxRelay
+	trash             config.Trash
+}
+
+func NewVendorService(vendorRepository repository.VendorRepository, historyRepository repository.HistoryRepository, webhooks *WebhookService, trash config.Trash, events config.Events) *VendorService {
+	broker := NewEventBroker(events.Buffer)
+
+	return &VendorService{
+		VendorRepository:  vendorRepository,
+		HistoryRepository: historyRepository,
+		EventSource:       broker,
+		broker:            broker,
+		webhooks:          webhooks,
+		trash:             trash,
+	}
 }
 
-func NewVendorService(vendorRepository repository.VendorRepository) *VendorService {
-	return &VendorService{VendorRepository: vendorRepository}
+func (s *VendorService) GetAllVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return s.VendorRepository.GetAllVendors(ctx, opts)
 }
 
-func (s *VendorService) GetAllVendors(page, pageSize int) ([]*domain.GetVendorResponse, error) {
-	return s.VendorRepository.GetAllVendors(page, pageSize)
+func (s *VendorService) GetTotalVendorsCount(ctx context.Context, vendorType string) (int, error) {
+	return s.VendorRepository.GetTotalVendorsCount(ctx, vendorType)
 }
 
-func (s *VendorService) GetTotalVendorsCount() (int, error) {
-	return s.VendorRepository.GetTotalVendorsCount()
+func (s *VendorService) GetVendorByID(ctx context.Context, id primitive.ObjectID) (*domain.GetVendorResponse, error) {
+	return s.VendorRepository.GetVendorByID(ctx, id)
 }
 
-func (s *VendorService) GetVendorByID(id primitive.ObjectID) (*domain.GetVendorResponse, error) {
-	return s.VendorRepository.GetVendorByID(id)
+func (s *VendorService) CreateVendor(ctx context.Context, request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
+	if err := request.Coordinates.Validate(); err != nil {
+		return nil, err
+	}
+
+	vendor, err := s.VendorRepository.CreateVendor(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := domain.SnapshotOf(domain.CommonVendorResponse(*vendor))
+	s.record(ctx, &domain.VendorHistoryEntry{
+		VendorID: vendor.ID,
+		Revision: vendor.Version,
+		Action:   domain.HistoryActionCreated,
+		Changes:  domain.DiffVendors(domain.CommonVendorRequest{}, snapshot),
+		Snapshot: snapshot,
+	})
+
+	return vendor, nil
+}
+
+func (s *VendorService) UpdateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	if err := update.Coordinates.Validate(); err != nil {
+		return nil, err
+	}
+	return s.updateVendor(ctx, id, update, expectedVersion, domain.HistoryActionUpdated, 0)
+}
+
+// updateVendor reads the current state before writing so the history entry
+// can hold an exact diff. Without a caller supplied version the write is
+// pinned to the version that was read, and retried if it loses a race.
+func (s *VendorService) updateVendor(ctx context.Context, id primitive.ObjectID, update *domain.UpdateVendorRequest, expectedVersion int64, action domain.HistoryAction, revertedTo int64) (*domain.UpdateVendorResponse, error) {
+	for attempt := 1; ; attempt++ {
+		before, err := s.VendorRepository.GetVendorByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil {
+			return nil, domain.ErrVendorNotFound
+		}
+
+		version := expectedVersion
+		if version == 0 {
+			version = before.Version
+		}
+
+		updated, err := s.VendorRepository.UpdateVendor(ctx, id, update, version)
+		if errors.Is(err, domain.ErrVersionConflict) && expectedVersion == 0 && attempt < maxWriteAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := domain.SnapshotOf(domain.CommonVendorResponse(*updated))
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID:   id,
+			Revision:   updated.Version,
+			Action:     action,
+			Changes:    domain.DiffVendors(domain.SnapshotOf(domain.CommonVendorResponse(*before)), snapshot),
+			Snapshot:   snapshot,
+			RevertedTo: revertedTo,
+		})
+
+		return updated, nil
+	}
 }
 
-func (s *VendorService) CreateVendor(request *domain.CreateVendorRequest) (*domain.CreateVendorResponse, error) {
-	return s.VendorRepository.CreateVendor(request)
+// PatchVendor applies a patch to the current state of a vendor and writes
+// only the fields that changed. A retried write re-applies the patch to the
+// state that won the race, so a JSON Patch test runs against it as well.
+func (s *VendorService) PatchVendor(ctx context.Context, id primitive.ObjectID, format domain.PatchFormat, patch []byte, expectedVersion int64) (*domain.UpdateVendorResponse, error) {
+	for attempt := 1; ; attempt++ {
+		before, err := s.VendorRepository.GetVendorByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil {
+			return nil, domain.ErrVendorNotFound
+		}
+		if expectedVersion != 0 && expectedVersion != before.Version {
+			return nil, domain.ErrVersionConflict
+		}
+
+		snapshot := domain.SnapshotOf(domain.CommonVendorResponse(*before))
+		after, err := domain.ApplyPatch(snapshot, format, patch)
+		if err != nil {
+			return nil, err
+		}
+		if err := after.Coordinates.Validate(); err != nil {
+			return nil, err
+		}
+
+		changes := domain.NewVendorPatch(snapshot, after)
+		if changes.Empty() {
+			unchanged := domain.UpdateVendorResponse(*before)
+			return &unchanged, nil
+		}
+
+		updated, err := s.VendorRepository.PatchVendor(ctx, id, changes, before.Version)
+		if errors.Is(err, domain.ErrVersionConflict) && expectedVersion == 0 && attempt < maxWriteAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		updatedSnapshot := domain.SnapshotOf(domain.CommonVendorResponse(*updated))
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: id,
+			Revision: updated.Version,
+			Action:   domain.HistoryActionUpdated,
+			Changes:  domain.DiffVendors(snapshot, updatedSnapshot),
+			Snapshot: updatedSnapshot,
+		})
+
+		return updated, nil
+	}
 }
 
-func (s *VendorService) UpdateVendor(id primitive.ObjectID, update *domain.UpdateVendorRequest) (*domain.UpdateVendorResponse, error) {
-	return s.VendorRepository.UpdateVendor(id, update)
+func (s *VendorService) DeleteVendor(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	for attempt := 1; ; attempt++ {
+		before, err := s.VendorRepository.GetVendorByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return domain.ErrVendorNotFound
+		}
+
+		version := expectedVersion
+		if version == 0 {
+			version = before.Version
+		}
+
+		err = s.VendorRepository.DeleteVendor(ctx, id, version)
+		if errors.Is(err, domain.ErrVersionConflict) && expectedVersion == 0 && attempt < maxWriteAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.record(ctx, &domain.VendorHistoryEntry{
+			VendorID: id,
+			Revision: before.Version + 1,
+			Action:   domain.HistoryActionDeleted,
+			Changes:  []domain.FieldChange{},
+			Snapshot: domain.SnapshotOf(domain.CommonVendorResponse(*before)),
+		})
+
+		return nil
+	}
 }
 
-func (s *VendorService) DeleteVendor(id primitive.ObjectID) error {
-	return s.VendorRepository.DeleteVendor(id)
+func (s *VendorService) GetDeletedVendors(ctx context.Context, opts domain.ListOptions) (*domain.VendorList, error) {
+	return s.VendorRepository.GetDeletedVendors(ctx, opts)
 }
 
-func (s *VendorService) SearchVendors(query string, page int, pageSize int) ([]*domain.GetVendorResponse, error) {
-	return s.VendorRepository.SearchVendors(query, page, pageSize)
+func (s *VendorService) GetDeletedVendorsCount(ctx context.Context) (int, error) {
+	return s.VendorRepository.GetDeletedVendorsCount(ctx)
 }
 
-func (s *VendorService) FilterVendorsByTags(tags []string, page int, pageSize int) ([]*domain.GetVendorResponse, error) {
-	return s.VendorRepository.FilterVendorsByTags(tags, page, pageSize)
+func (s *VendorService) RestoreVendor(ctx context.Context, id primitive.ObjectID) error {
+	if err := s.VendorRepository.RestoreVendor(ctx, id); err != nil {
+		return err
+	}
+
+	restored, err := s.VendorRepository.GetVendorByID(ctx, id)
+	if err != nil || restored == nil {
+		slog.Error("error reading restored vendor for history", slog.String("vendor_id", id.Hex()))
+		return nil
+	}
+
+	s.record(ctx, &domain.VendorHistoryEntry{
+		VendorID: id,
+		Revision: restored.Version,
+		Action:   domain.HistoryActionRestored,
+		Changes:  []domain.FieldChange{},
+		Snapshot: domain.SnapshotOf(domain.CommonVendorResponse(*restored)),
+	})
+
+	return nil
+}
+
+// PurgeDeletedVendors permanently removes vendors that have been in the trash
+// for longer than the configured retention period.
+func (s *VendorService) PurgeDeletedVendors(ctx context.Context) (int, error) {
+	return s.VendorRepository.PurgeDeletedVendors(ctx, time.Now().UTC().Add(-s.trash.Retention))
+}
+
+// SearchVendors returns a page of vendors matching query together with the
+// total number of matches.
+func (s *VendorService) SearchVendors(ctx context.Context, query domain.SearchQuery, opts domain.ListOptions) (*domain.SearchVendorList, int, error) {
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.VendorRepository.CountSearchVendors(ctx, query, opts.Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vendors, err := s.VendorRepository.SearchVendors(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vendors, total, nil
+}
+
+// FindVendorsNear returns a page of vendors within radiusMeters of the given
+// point, closest first, together with the total number of vendors in range.
+func (s *VendorService) FindVendorsNear(ctx context.Context, lat, lng, radiusMeters float64, opts domain.ListOptions) (*domain.NearbyVendorList, int, error) {
+	if err := domain.ValidateLngLat(lng, lat); err != nil {
+		return nil, 0, err
+	}
+	point := domain.NewGeoPoint(lng, lat)
+
+	vendors, err := s.VendorRepository.FindVendorsNear(ctx, point, radiusMeters, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.VendorRepository.CountVendorsNear(ctx, point, radiusMeters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vendors, total, nil
+}
+
+// GetVendorMap returns the vendors inside the requested area. When the query
+// asks for clustering, cells holding a single vendor are returned as plain
+// vendors and every other cell as a cluster.
+func (s *VendorService) GetVendorMap(ctx context.Context, query domain.MapQuery) (*domain.VendorMap, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.VendorMap{
+		Vendors:  []*domain.MapVendor{},
+		Clusters: []*domain.MapCluster{},
+	}
+
+	if query.Grid == 0 {
+		vendors, err := s.VendorRepository.FindVendorsInArea(ctx, query.Area, domain.MaxMapVendors+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(vendors) > domain.MaxMapVendors {
+			vendors = vendors[:domain.MaxMapVendors]
+			result.Truncated = true
+		}
+		result.Vendors = append(result.Vendors, vendors...)
+		return result, nil
+	}
+
+	clusters, err := s.VendorRepository.ClusterVendorsInArea(ctx, query.Area, query.CellSize())
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if cluster.Count == 1 {
+			result.Vendors = append(result.Vendors, cluster.Vendor)
+		} else {
+			result.Clusters = append(result.Clusters, cluster)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *VendorService) GetVendorFacets(ctx context.Context, fields []string, vendorType string) (domain.Facets, error) {
+	return s.VendorRepository.GetVendorFacets(ctx, fields, vendorType, domain.MaxFacetValues)
+}
+
+// FilterVendorsByTags returns a page of vendors whose tags match together with
+// the total number of matches.
+func (s *VendorService) FilterVendorsByTags(ctx context.Context, tags domain.ValueMatch, opts domain.ListOptions) (*domain.VendorList, int, error) {
+	total, err := s.VendorRepository.CountVendorsByTags(ctx, tags, opts.Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vendors, err := s.VendorRepository.FilterVendorsByTags(ctx, tags, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vendors, total, nil
 }
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"vendors/internal/domain"
+	"vendors/pkg/lib/utils"
+)
+
+// dispatchBatch bounds how many deliveries are claimed and sent at once.
+const dispatchBatch = 20
+
+// RunWebhookDispatcher sends due webhook deliveries every poll interval, and
+// as soon as new ones are queued, until ctx is cancelled.
+func (s *WebhookService) RunWebhookDispatcher(ctx context.Context) {
+	if s.cfg.PollInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		if _, err := s.DispatchDue(ctx); err != nil {
+			slog.Error("error dispatching webhook deliveries", utils.Err(err))
+		}
+	}
+}
+
+// DispatchDue sends every delivery that is due and returns how many were
+// attempted. Claimed deliveries are leased for twice the request timeout, so
+// that one whose dispatcher died is picked up again.
+func (s *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		claimed, err := s.WebhookRepository.ClaimDueDeliveries(ctx, time.Now().UTC(), 2*s.cfg.Timeout, dispatchBatch)
+		if err != nil {
+			return attempted, err
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range claimed {
+			wg.Add(1)
+			go func(delivery *domain.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		attempted += len(claimed)
+		if len(claimed) < dispatchBatch {
+			return attempted, nil
+		}
+	}
+}
+
+// deliver makes one attempt to send a delivery and records its outcome. Any
+// 2xx response counts as accepted.
+func (s *WebhookService) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	webhook, err := s.WebhookRepository.GetWebhook(ctx, delivery.WebhookID)
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		return
+	}
+	if err != nil {
+		slog.Error("error getting webhook for delivery", slog.String("delivery_id", delivery.ID.Hex()), utils.Err(err))
+		return
+	}
+
+	attempt := s.send(ctx, webhook, delivery)
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = domain.DeliverySucceeded
+	case len(delivery.Attempts) >= s.cfg.MaxAttempts:
+		delivery.Status = domain.DeliveryDead
+		slog.Warn("webhook delivery moved to dead letters",
+			slog.String("webhook_id", webhook.ID.Hex()),
+			slog.String("delivery_id", delivery.ID.Hex()),
+			slog.String("error", attempt.Error))
+	default:
+		delivery.NextAttemptAt = attempt.At.Add(backoff(s.cfg.InitialBackoff, s.cfg.MaxBackoff, len(delivery.Attempts)))
+	}
+
+	if err := s.WebhookRepository.UpdateDelivery(ctx, delivery); err != nil && !errors.Is(err, domain.ErrDeliveryNotFound) {
+		slog.Error("error updating webhook delivery", slog.String("delivery_id", delivery.ID.Hex()), utils.Err(err))
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) domain.DeliveryAttempt {
+	attempt := domain.DeliveryAttempt{At: time.Now().UTC()}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(domain.WebhookEventHeader, string(delivery.Event.Type))
+	req.Header.Set(domain.WebhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(domain.WebhookTimestampHeader, strconv.FormatInt(attempt.At.Unix(), 10))
+	req.Header.Set(domain.WebhookSignatureHeader, domain.SignWebhook(webhook.Secret, attempt.At, body))
+
+	resp, err := s.client.Do(req)
+	attempt.Duration = time.Since(attempt.At)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = resp.Status
+	}
+	return attempt
+}
+
+// backoff returns the wait after the given number of failed attempts: the
+// initial backoff, doubled for every further attempt, up to the maximum.
+func backoff(initial, maximum time.Duration, failed int) time.Duration {
+	wait := initial
+	for i := 1; i < failed && wait < maximum; i++ {
+		wait *= 2
+	}
+	if wait > maximum {
+		wait = maximum
+	}
+	return wait
+}
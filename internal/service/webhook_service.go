@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+	"vendors/internal/config"
+	"vendors/internal/domain"
+	repository "vendors/internal/repository/interfaces"
+	"vendors/pkg/lib/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxDeliveryLog bounds the number of deliveries listed for a webhook.
+const maxDeliveryLog = 100
+
+// WebhookService manages webhook subscriptions and delivers vendor events to
+// them. Events are queued as deliveries and sent by RunWebhookDispatcher, so
+// a slow or failing receiver never holds up a vendor change.
+type WebhookService struct {
+	WebhookRepository repository.WebhookRepository
+	client            *http.Client
+	cfg               config.Webhooks
+	wake              chan struct{}
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, cfg config.Webhooks) *WebhookService {
+	return &WebhookService{
+		WebhookRepository: webhookRepository,
+		client:            &http.Client{Timeout: cfg.Timeout},
+		cfg:               cfg,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// CreateWebhook stores a webhook, generating its secret unless one is given.
+// The returned webhook is the only one to carry the secret.
+func (s *WebhookService) CreateWebhook(ctx context.Context, request *domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		generated := make([]byte, 32)
+		if _, err := rand.Read(generated); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(generated)
+	}
+
+	webhook := &domain.Webhook{
+		URL:       request.URL,
+		Secret:    secret,
+		Events:    request.Events,
+		CreatedAt: time.Now().UTC(),
+	}
+	if webhook.Events == nil {
+		webhook.Events = []domain.VendorEventType{}
+	}
+
+	if err := s.WebhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	webhooks, err := s.WebhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id primitive.ObjectID) (*domain.Webhook, error) {
+	webhook, err := s.WebhookRepository.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	return s.WebhookRepository.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns the delivery log of a webhook, newest first. Asking
+// for DeliveryDead lists its dead letters.
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.WebhookRepository.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return s.WebhookRepository.GetDeliveries(ctx, webhookID, status, maxDeliveryLog)
+}
+
+// RetryDelivery queues a delivery to be sent again right away, typically one
+// taken from the dead letters. Its attempt log is kept.
+func (s *WebhookService) RetryDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	delivery, err := s.WebhookRepository.GetDelivery(ctx, webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = domain.DeliveryPending
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := s.WebhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return delivery, nil
+}
+
+// Enqueue queues a delivery of event for every webhook subscribed to it.
+func (s *WebhookService) Enqueue(ctx context.Context, event *domain.VendorEvent) error {
+	webhooks, err := s.WebhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var deliveries []*domain.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         *event,
+			Status:        domain.DeliveryPending,
+			Attempts:      []domain.DeliveryAttempt{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := s.WebhookRepository.AddDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	s.notify()
+	return nil
+}
+
+// notify wakes the dispatcher without waiting for it.
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue queues webhook deliveries for a change the service has just made.
+// Like history, the deliveries are queued detached from the request, and a
+// failure is logged rather than returned.
+func (s *VendorService) enqueue(ctx context.Context, event *domain.VendorEvent) {
+	ctx, cancel := detached(ctx)
+	defer cancel()
+
+	if err := s.webhooks.Enqueue(ctx, event); err != nil {
+		slog.Error("error queueing webhook deliveries",
+			slog.String("vendor_id", event.VendorID.Hex()),
+			utils.Err(err))
+	}
+}
@@ -1,12 +1,42 @@
 package errs
 
 const (
-	InvalidRequestFormat = "Invalid request format"
-	InvalidVendorID      = "Invalid vendor id"
-	VendorNotFound       = "Vendor not found"
-	InternalServerError  = "Internal server error"
-	InvalidRequestBody   = "Invalid request body"
-	InvalidPage          = "Invalid page"
-	InvalidPageSize      = "Invalid page size"
-	MissingTags          = "Missing tags"
+	InvalidRequestFormat  = "Invalid request format"
+	InvalidVendorID       = "Invalid vendor id"
+	VendorNotFound        = "Vendor not found"
+	VendorNotInTrash      = "Vendor not found in trash"
+	InternalServerError   = "Internal server error"
+	InvalidRequestBody    = "Invalid request body"
+	InvalidPage           = "Invalid page"
+	InvalidPageSize       = "Invalid page size"
+	PageTooDeep           = "Page is too deep, continue with next_cursor instead"
+	MissingTags           = "Missing tags"
+	InvalidCursor         = "Invalid cursor"
+	InvalidIfMatch        = "Invalid If-Match header"
+	VersionConflict       = "Vendor was modified by another request"
+	InvalidRevision       = "Invalid revision"
+	RevisionNotFound      = "Revision not found"
+	InvalidCoordinates    = "Invalid coordinates"
+	InvalidRadius         = "Invalid radius"
+	InvalidMapArea        = "Invalid map area"
+	UnknownVendorType     = "Unknown vendor type"
+	VendorTypeChanged     = "Vendor type cannot be changed"
+	EmptyBulkRequest      = "Bulk request has no vendors"
+	TooManyBulkItems      = "Bulk request has too many vendors"
+	RequestTooLarge       = "Request body is too large"
+	PatchTestFailed       = "Patch test operation failed"
+	UnsupportedPatchType  = "Unsupported patch content type"
+	InvalidSort           = "Invalid sort"
+	InvalidFacet          = "Invalid facet"
+	InvalidEventID        = "Invalid Last-Event-ID"
+	EventsExpired         = "Events after Last-Event-ID are no longer available"
+	InvalidWebhookID      = "Invalid webhook id"
+	WebhookNotFound       = "Webhook not found"
+	InvalidDeliveryID     = "Invalid delivery id"
+	DeliveryNotFound      = "Webhook delivery not found"
+	InvalidDeliveryStatus = "Invalid delivery status"
+	InvalidDryRun         = "Invalid dry_run"
+	InvalidThreshold      = "Invalid threshold"
+	InvalidLimit          = "Invalid limit"
+	MergeIncomplete       = "Merge stopped part way, send it again to finish it"
 )
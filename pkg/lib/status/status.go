@@ -3,10 +3,15 @@ package status
 import "net/http"
 
 const (
-	BadRequest          = http.StatusBadRequest
-	NotFound            = http.StatusNotFound
-	OK                  = http.StatusOK
-	InternalServerError = http.StatusInternalServerError
-	Forbidden           = http.StatusForbidden
-	Conflict            = http.StatusConflict
+	BadRequest           = http.StatusBadRequest
+	NotFound             = http.StatusNotFound
+	OK                   = http.StatusOK
+	Created              = http.StatusCreated
+	InternalServerError  = http.StatusInternalServerError
+	Forbidden            = http.StatusForbidden
+	Conflict             = http.StatusConflict
+	PreconditionFailed   = http.StatusPreconditionFailed
+	PayloadTooLarge      = http.StatusRequestEntityTooLarge
+	UnsupportedMediaType = http.StatusUnsupportedMediaType
+	Gone                 = http.StatusGone
 )